@@ -0,0 +1,222 @@
+// Package analysis beoordeelt gespeelde zetten met de engine: per zet de
+// beste zet, de score van de gespeelde zet en een oordeel (✅ / ⚠️ / ❌).
+package analysis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/engine"
+	"github.com/azen-engine/game"
+	"github.com/azen-engine/gameio"
+	"github.com/azen-engine/knowledge"
+)
+
+// Grade is het oordeel over een gespeelde zet.
+type Grade int
+
+const (
+	Good Grade = iota
+	Inaccuracy
+	Blunder
+)
+
+func (g Grade) String() string {
+	switch g {
+	case Inaccuracy:
+		return "onnauwkeurig"
+	case Blunder:
+		return "blunder"
+	}
+	return "goed"
+}
+
+// Emoji geeft het symbool waarmee de CLI het oordeel toont.
+func (g Grade) Emoji() string {
+	switch g {
+	case Inaccuracy:
+		return "⚠️ "
+	case Blunder:
+		return "❌"
+	}
+	return "✅"
+}
+
+// MoveAnalysis is de beoordeling van één gespeelde zet door de engine.
+type MoveAnalysis struct {
+	Move       game.Move
+	Best       game.Move
+	BestFollow *game.Move // vervolg-zet na een joker in Best
+	Eval       engine.MoveEval
+	Actual     engine.MoveDetail // score van de gespeelde zet
+}
+
+// Analyze laat de engine de positie vóór move doorrekenen en zoekt de score
+// van de effectief gespeelde zet op (of simuleert die apart).
+func Analyze(cfg engine.Config, gs *game.GameState, tracker *knowledge.KnowledgeTracker, move game.Move) MoveAnalysis {
+	return AnalyzeContext(context.Background(), cfg, gs, tracker, move)
+}
+
+// AnalyzeContext is Analyze die stopt zodra ctx afloopt; de beoordeling
+// rust dan op wat de engine tot dan doorrekende.
+func AnalyzeContext(ctx context.Context, cfg engine.Config, gs *game.GameState, tracker *knowledge.KnowledgeTracker, move game.Move) MoveAnalysis {
+	eng := engine.NewEngine(cfg)
+	a := MoveAnalysis{Move: move}
+	a.Best, a.Eval = eng.BestMoveContext(ctx, gs, tracker)
+	if f, ok := eng.BestFollowContext(ctx, gs, tracker, a.Best); ok {
+		a.BestFollow = &f
+	}
+	if d, ok := engine.FindMoveInEval(a.Eval, move); ok {
+		a.Actual = d
+	} else {
+		a.Actual = eng.AnalyzeMoveContext(ctx, gs, tracker, move)
+	}
+	return a
+}
+
+// PlayedIsBest meldt of de gespeelde zet de keuze van de engine was.
+func (a MoveAnalysis) PlayedIsBest() bool { return game.MovesEqual(a.Best, a.Move) }
+
+// Diff is hoeveel winkans de gespeelde zet kost t.o.v. de beste zet.
+func (a MoveAnalysis) Diff() float64 {
+	if a.PlayedIsBest() {
+		return 0
+	}
+	return a.Eval.Score - a.Actual.WinRate
+}
+
+// Grade: een gemiste gedwongen winst of meer dan 15% verlies is een blunder,
+// meer dan 2% een onnauwkeurigheid.
+func (a MoveAnalysis) Grade() Grade {
+	if a.PlayedIsBest() {
+		return Good
+	}
+	diff := a.Diff()
+	switch {
+	case a.Eval.ForcedWinDepth > 0, diff > 0.15:
+		return Blunder
+	case diff > 0.02:
+		return Inaccuracy
+	}
+	return Good
+}
+
+// ShowBest meldt of de beste zet het vermelden waard is: een duidelijk
+// verschil, of een klein verschil in een bijna gewonnen stelling.
+func (a MoveAnalysis) ShowBest() bool {
+	diff := a.Diff()
+	return !a.PlayedIsBest() && (diff > 0.02 || (a.Eval.Score > 0.90 && diff > 0.005))
+}
+
+// BestLabel is de beste zet in invoernotatie, met vervolg-zet ("0 / K K").
+func (a MoveAnalysis) BestLabel() string {
+	if a.BestFollow != nil {
+		return fmt.Sprintf("%s / %s", game.FormatMove(a.Best), game.FormatMove(*a.BestFollow))
+	}
+	return game.FormatMove(a.Best)
+}
+
+// Annotation is de beoordeling in de vorm van een gameio-record.
+func (a MoveAnalysis) Annotation() *gameio.Eval {
+	e := &gameio.Eval{
+		Best:           game.FormatMove(a.Best),
+		BestScore:      a.Eval.Score,
+		Score:          a.Actual.WinRate,
+		Loss:           a.Diff(),
+		Visits:         a.Actual.Visits,
+		ForcedWinDepth: a.Eval.ForcedWinDepth,
+		Grade:          a.Grade().String(),
+	}
+	if a.BestFollow != nil {
+		e.BestFollow = game.FormatMove(*a.BestFollow)
+	}
+	return e
+}
+
+// Ply is één beurt uit een herspeelde partij: een zet met eventuele
+// vervolg-zet na een joker, en de analyse als die speler geanalyseerd werd.
+type Ply struct {
+	Num      int // 1-based beurtnummer
+	Move     game.Move
+	Follow   *game.Move
+	Analysis *MoveAnalysis
+}
+
+// Label is de beurt in invoernotatie ("K K", "0 / 5 5", "PASS").
+func (p Ply) Label() string {
+	if p.Follow != nil {
+		return fmt.Sprintf("%s / %s", game.FormatMove(p.Move), game.FormatMove(*p.Follow))
+	}
+	return game.FormatMove(p.Move)
+}
+
+// Turn is de beurt als gameio-record, met de beoordeling als die er is.
+func (p Ply) Turn() gameio.Turn {
+	t := gameio.NewTurn(p.Move, p.Follow)
+	if p.Analysis != nil {
+		t.Eval = p.Analysis.Annotation()
+	}
+	return t
+}
+
+// ReplayLog speelt een opgeslagen partij opnieuw af en analyseert de zetten
+// van players (nil of leeg = alle spelers) met volledige kennis van alle
+// handen. visit wordt na elke beurt aangeroepen. De eindstand wordt
+// teruggegeven, ook als de log halverwege stopt.
+func ReplayLog(cfg engine.Config, log *gameio.GameLog, players map[int]bool, visit func(Ply)) (*game.GameState, error) {
+	return ReplayLogContext(context.Background(), cfg, log, players, visit)
+}
+
+// ReplayLogContext is ReplayLog die stopt zodra ctx afloopt: de stand tot
+// dan wordt teruggegeven met de fout van ctx.
+func ReplayLogContext(ctx context.Context, cfg engine.Config, log *gameio.GameLog, players map[int]bool, visit func(Ply)) (*game.GameState, error) {
+	n := log.NumPlayers
+	if n < 2 || n > 4 || len(log.Hands) != n {
+		return nil, fmt.Errorf("ongeldige log (%d spelers, %d handen)", n, len(log.Hands))
+	}
+	cfg.NumPlayers = n
+	cfg.OmniscientMode = true
+
+	hands := make([]*cards.Hand, n)
+	for i, h := range log.Hands {
+		hands[i] = cards.NewHand(h)
+	}
+	startPlayer := 0
+	if len(log.Moves) > 0 {
+		startPlayer = log.Moves[0].PlayerID
+	}
+	g := NewReplay(hands, log.DeadCards, startPlayer, players)
+
+	num := 0
+	for i := 0; i < len(log.Moves) && !g.GS.GameOver; i++ {
+		if err := ctx.Err(); err != nil {
+			return g.GS, err
+		}
+		num++
+		ply := Ply{Num: num, Move: log.Moves[i]}
+		playerID := ply.Move.PlayerID
+		if err := g.GS.ValidateMove(ply.Move); err != nil {
+			return g.GS, fmt.Errorf("zet %d (%s): %v", num, ply.Move, err)
+		}
+		if g.Analyzed(playerID) {
+			a := AnalyzeContext(ctx, cfg, g.GS, g.Trackers[playerID], ply.Move)
+			ply.Analysis = &a
+		}
+		g.Apply(ply.Move)
+		// Een joker geeft dezelfde speler meteen weer de beurt: de volgende
+		// logregel van die speler is de vervolg-zet ("0 / K K"). Zonder joker
+		// kan dezelfde speler ook opnieuw aan de beurt zijn (bv. als alle
+		// anderen uit zijn); dan is het een gewone beurt.
+		if !g.GS.GameOver && g.GS.CurrentTurn == playerID && ply.Move.ContainsReset() && i+1 < len(log.Moves) && log.Moves[i+1].PlayerID == playerID && !log.Moves[i+1].IsPass {
+			follow := log.Moves[i+1]
+			if err := g.ApplyFollow(follow); err != nil {
+				return g.GS, fmt.Errorf("zet %d (%s): vervolg-zet: %v", num, follow, err)
+			}
+			i++
+			ply.Follow = &follow
+		}
+		visit(ply)
+	}
+	return g.GS, nil
+}
@@ -0,0 +1,159 @@
+package analysis
+
+import (
+	"fmt"
+
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/game"
+	"github.com/azen-engine/gameio"
+	"github.com/azen-engine/knowledge"
+)
+
+// Replay is een partij met bekende handen, zoals in de analysemodus: de
+// spelstaat en een KnowledgeTracker per geanalyseerde speler. Beurten
+// kunnen teruggenomen en opnieuw gedaan worden; de staat wordt dan opnieuw
+// opgebouwd vanaf de starthanden, inclusief de passes in de trackers.
+type Replay struct {
+	GS       *game.GameState
+	Trackers []*knowledge.KnowledgeTracker // nil voor niet-geanalyseerde spelers
+
+	start   *game.GameState
+	players map[int]bool
+	turns   []turn
+	redo    []turn
+}
+
+// turn is één gespeelde beurt met de beoordeling van de engine, als die er
+// is.
+type turn struct {
+	moves []game.Move
+	eval  *gameio.Eval
+}
+
+// NewReplay start een partij. players zijn de spelers die een tracker
+// krijgen (nil of leeg = alle spelers).
+func NewReplay(hands []*cards.Hand, dead []cards.Card, startPlayer int, players map[int]bool) *Replay {
+	r := &Replay{
+		start:   game.NewGameWithHands(hands, dead, startPlayer),
+		players: players,
+	}
+	r.rebuild()
+	return r
+}
+
+// Analyzed meldt of speler p een tracker heeft.
+func (r *Replay) Analyzed(p int) bool { return r.Trackers[p] != nil }
+
+// Turns is het aantal gespeelde beurten.
+func (r *Replay) Turns() int { return len(r.turns) }
+
+// Apply valideert en speelt een nieuwe beurt. Teruggenomen beurten vervallen.
+func (r *Replay) Apply(m game.Move) error {
+	if err := r.GS.ValidateMove(m); err != nil {
+		return err
+	}
+	r.record(m)
+	r.turns = append(r.turns, turn{moves: []game.Move{m}})
+	r.redo = nil
+	return nil
+}
+
+// ApplyFollow speelt de vervolg-zet na een joker-reset als deel van de
+// laatste beurt.
+func (r *Replay) ApplyFollow(f game.Move) error {
+	last := len(r.turns) - 1
+	if last < 0 || r.GS.GameOver || r.GS.CurrentTurn != f.PlayerID || f.IsPass {
+		return fmt.Errorf("vervolg-zet kan enkel na een joker-reset")
+	}
+	if err := r.GS.ValidateMove(f); err != nil {
+		return err
+	}
+	r.record(f)
+	r.turns[last].moves = append(r.turns[last].moves, f)
+	return nil
+}
+
+// Annotate hangt de beoordeling van de engine aan de laatste beurt.
+func (r *Replay) Annotate(eval *gameio.Eval) {
+	if n := len(r.turns); n > 0 {
+		r.turns[n-1].eval = eval
+	}
+}
+
+// Undo neemt de laatste beurt terug en geeft haar zetten terug.
+func (r *Replay) Undo() ([]game.Move, error) {
+	n := len(r.turns)
+	if n == 0 {
+		return nil, fmt.Errorf("geen zet om terug te nemen")
+	}
+	undone := r.turns[n-1]
+	r.turns = r.turns[:n-1]
+	r.redo = append(r.redo, undone)
+	r.rebuild()
+	return undone.moves, nil
+}
+
+// Redo speelt de laatst teruggenomen beurt opnieuw.
+func (r *Replay) Redo() ([]game.Move, error) {
+	n := len(r.redo)
+	if n == 0 {
+		return nil, fmt.Errorf("geen zet om opnieuw te doen")
+	}
+	t := r.redo[n-1]
+	for _, m := range t.moves {
+		r.record(m)
+	}
+	r.turns = append(r.turns, t)
+	r.redo = r.redo[:n-1]
+	return t.moves, nil
+}
+
+// record past een gevalideerde zet toe op de staat en alle trackers.
+func (r *Replay) record(m game.Move) {
+	if m.IsPass {
+		for _, t := range r.Trackers {
+			if t != nil {
+				t.RecordPass(m.PlayerID, r.GS.Round)
+			}
+		}
+	}
+	r.GS.ApplyMove(m)
+	for _, t := range r.Trackers {
+		if t != nil {
+			t.RecordMove(m)
+		}
+	}
+}
+
+// rebuild speelt alle beurten opnieuw af vanaf de starthanden.
+func (r *Replay) rebuild() {
+	r.GS = r.start.Clone()
+	n := r.GS.NumPlayers
+	r.Trackers = make([]*knowledge.KnowledgeTracker, n)
+	for p := 0; p < n; p++ {
+		if len(r.players) == 0 || r.players[p] {
+			r.Trackers[p] = knowledge.NewKnowledgeTracker(n, p, r.GS.Hands[p], r.GS.DeadCards)
+		}
+	}
+	for _, t := range r.turns {
+		for _, m := range t.moves {
+			r.record(m)
+		}
+	}
+}
+
+// Record zet de partij tot nu toe om naar een gameio-record, met de
+// beoordelingen.
+func (r *Replay) Record() *gameio.Record {
+	rec := gameio.NewRecord(r.start)
+	rec.Meta.Source = "analyze"
+	for _, t := range r.turns {
+		var rt *gameio.Turn
+		for _, m := range t.moves {
+			rt = rec.AddMove(m)
+		}
+		rt.Eval = t.eval
+	}
+	rec.Finish(r.GS)
+	return rec
+}
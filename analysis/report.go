@@ -0,0 +1,97 @@
+package analysis
+
+import "sort"
+
+// Mistake is een onnauwkeurigheid of blunder, met de zet die de engine
+// verkoos.
+type Mistake struct {
+	Game   string // partij, bv. de bestandsnaam
+	Num    int    // beurtnummer in die partij
+	Played string
+	Best   string
+	Loss   float64 // verloren winkans t.o.v. de beste zet
+	Grade  Grade
+}
+
+// Stats telt de beoordelingen van één speler over een of meer partijen.
+type Stats struct {
+	Name         string
+	Games        int
+	Moves        int
+	Good         int
+	Inaccuracies int
+	Blunders     int
+	Loss         float64 // som van Diff over alle zetten
+	Mistakes     []Mistake
+}
+
+// Add telt een geanalyseerde beurt uit partij game mee. Beurten zonder
+// analyse worden genegeerd.
+func (s *Stats) Add(game string, p Ply) {
+	a := p.Analysis
+	if a == nil {
+		return
+	}
+	s.Moves++
+	s.Loss += a.Diff()
+	switch a.Grade() {
+	case Good:
+		s.Good++
+		return
+	case Inaccuracy:
+		s.Inaccuracies++
+	case Blunder:
+		s.Blunders++
+	}
+	s.Mistakes = append(s.Mistakes, Mistake{
+		Game:   game,
+		Num:    p.Num,
+		Played: p.Label(),
+		Best:   a.BestLabel(),
+		Loss:   a.Diff(),
+		Grade:  a.Grade(),
+	})
+}
+
+// Merge telt de cijfers van o bij s op.
+func (s *Stats) Merge(o *Stats) {
+	s.Games += o.Games
+	s.Moves += o.Moves
+	s.Good += o.Good
+	s.Inaccuracies += o.Inaccuracies
+	s.Blunders += o.Blunders
+	s.Loss += o.Loss
+	s.Mistakes = append(s.Mistakes, o.Mistakes...)
+}
+
+// Accuracy is het aandeel goede zetten (0-1).
+func (s *Stats) Accuracy() float64 {
+	if s.Moves == 0 {
+		return 0
+	}
+	return float64(s.Good) / float64(s.Moves)
+}
+
+// AvgLoss is het gemiddelde verlies aan winkans per zet.
+func (s *Stats) AvgLoss() float64 {
+	if s.Moves == 0 {
+		return 0
+	}
+	return s.Loss / float64(s.Moves)
+}
+
+// Worst geeft de n ergste fouten: eerst de blunders (een gemiste gedwongen
+// winst kan weinig winkans kosten), daarbinnen het grootste verlies.
+func (s *Stats) Worst(n int) []Mistake {
+	out := append([]Mistake(nil), s.Mistakes...)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Grade != out[j].Grade {
+			return out[i].Grade > out[j].Grade
+		}
+		return out[i].Loss > out[j].Loss
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
@@ -0,0 +1,50 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/engine"
+	"github.com/azen-engine/game"
+)
+
+// ply maakt een geanalyseerde beurt: gespeeld played (winkans actual), de
+// engine verkoos best (winkans bestScore).
+func ply(num int, played, best cards.Rank, actual, bestScore float64) Ply {
+	m := game.Move{Cards: []cards.Card{{Rank: played}}}
+	return Ply{Num: num, Move: m, Analysis: &MoveAnalysis{
+		Move:   m,
+		Best:   game.Move{Cards: []cards.Card{{Rank: best}}},
+		Eval:   engine.MoveEval{Score: bestScore},
+		Actual: engine.MoveDetail{WinRate: actual},
+	}}
+}
+
+func TestStats(t *testing.T) {
+	a := &Stats{Name: "Speler 1", Games: 1}
+	a.Add("a.log", ply(1, cards.RankFive, cards.RankFive, 0.6, 0.6))   // goed
+	a.Add("a.log", ply(2, cards.RankFive, cards.RankNine, 0.50, 0.55)) // onnauwkeurig
+	a.Add("a.log", Ply{Num: 3})                                        // niet geanalyseerd
+	b := &Stats{Name: "Speler 1", Games: 1}
+	b.Add("b.log", ply(4, cards.RankFive, cards.RankNine, 0.2, 0.6)) // blunder
+	b.Add("b.log", ply(5, cards.RankFive, cards.RankNine, 0.3, 0.4)) // onnauwkeurig, groter verlies
+	a.Merge(b)
+
+	if a.Games != 2 || a.Moves != 4 || a.Good != 1 || a.Inaccuracies != 2 || a.Blunders != 1 {
+		t.Errorf("tellingen %+v", a)
+	}
+	if got := a.Accuracy(); got != 0.25 {
+		t.Errorf("Accuracy = %v, want 0.25", got)
+	}
+	if got := a.AvgLoss(); math.Abs(got-(0.05+0.4+0.1)/4) > 1e-9 {
+		t.Errorf("AvgLoss = %v", got)
+	}
+	worst := a.Worst(2)
+	if len(worst) != 2 || worst[0].Num != 4 || worst[1].Num != 5 {
+		t.Fatalf("Worst = %+v, want blunder 4 en daarna zet 5", worst)
+	}
+	if worst[0].Game != "b.log" || worst[0].Played != "5" || worst[0].Best != "9" {
+		t.Errorf("Worst[0] = %+v", worst[0])
+	}
+}
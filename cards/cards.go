@@ -0,0 +1,149 @@
+// Package cards bevat het kaartmodel van AZEN: ranks, kaarten, handen en
+// het deck, plus het parsen van de één-teken-notatie (0 1 2..9 X J Q K).
+package cards
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Rank int
+
+const (
+	RankThree Rank = 3
+	RankFour  Rank = 4
+	RankFive  Rank = 5
+	RankSix   Rank = 6
+	RankSeven Rank = 7
+	RankEight Rank = 8
+	RankNine  Rank = 9
+	RankTen   Rank = 10
+	RankJack  Rank = 11
+	RankQueen Rank = 12
+	RankKing  Rank = 13
+	RankAce   Rank = 14 // Hoogste naturelle kaart (boven Koning)
+	RankTwo   Rank = 15 // Wildcard (vervangt elke kaart)
+	RankJoker Rank = 16 // Reset-kaart (verslaat alles, opent nieuwe ronde)
+)
+
+type Suit int
+
+const (
+	SuitHearts   Suit = 0
+	SuitDiamonds Suit = 1
+	SuitClubs    Suit = 2
+	SuitSpades   Suit = 3
+	SuitJoker1   Suit = 4
+	SuitJoker2   Suit = 5
+)
+
+type Card struct {
+	Rank Rank
+	Suit Suit
+}
+
+func (c Card) IsWild() bool    { return c.Rank == RankTwo }   // alleen de 2 is wildcard
+func (c Card) IsReset() bool   { return c.Rank == RankJoker } // joker reset de ronde
+func (c Card) IsAce() bool     { return c.Rank == RankAce }   // naturelle hoge kaart
+func (c Card) IsSpecial() bool { return c.IsWild() || c.IsReset() }
+
+func (c Card) String() string { return c.RankStr() }
+
+func (c Card) RankStr() string {
+	switch c.Rank {
+	case RankAce:
+		return "1"
+	case RankTwo:
+		return "2"
+	case RankThree:
+		return "3"
+	case RankFour:
+		return "4"
+	case RankFive:
+		return "5"
+	case RankSix:
+		return "6"
+	case RankSeven:
+		return "7"
+	case RankEight:
+		return "8"
+	case RankNine:
+		return "9"
+	case RankTen:
+		return "X"
+	case RankJack:
+		return "J"
+	case RankQueen:
+		return "Q"
+	case RankKing:
+		return "K"
+	case RankJoker:
+		return "0"
+	}
+	return "?"
+}
+
+func ParseCard(s string) (Card, error) {
+	s = strings.TrimSpace(s)
+	if len(s) != 1 {
+		return Card{}, fmt.Errorf("ongeldige kaart: %q (verwacht één teken: 0 1 2..9 X J Q K)", s)
+	}
+	switch strings.ToUpper(s) {
+	case "0":
+		return Card{RankJoker, SuitJoker1}, nil
+	case "1":
+		return Card{RankAce, SuitHearts}, nil
+	case "2":
+		return Card{RankTwo, SuitHearts}, nil
+	case "3":
+		return Card{RankThree, SuitHearts}, nil
+	case "4":
+		return Card{RankFour, SuitHearts}, nil
+	case "5":
+		return Card{RankFive, SuitHearts}, nil
+	case "6":
+		return Card{RankSix, SuitHearts}, nil
+	case "7":
+		return Card{RankSeven, SuitHearts}, nil
+	case "8":
+		return Card{RankEight, SuitHearts}, nil
+	case "9":
+		return Card{RankNine, SuitHearts}, nil
+	case "X":
+		return Card{RankTen, SuitHearts}, nil
+	case "J":
+		return Card{RankJack, SuitHearts}, nil
+	case "Q":
+		return Card{RankQueen, SuitHearts}, nil
+	case "K":
+		return Card{RankKing, SuitHearts}, nil
+	}
+	return Card{}, fmt.Errorf("ongeldige kaart: %q (gebruik: 0 1 2..9 X J Q K)", s)
+}
+
+func ParseCards(s string) ([]Card, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	s = strings.ReplaceAll(s, ",", " ")
+	parts := strings.Fields(s)
+	result := make([]Card, 0, len(parts))
+	for _, p := range parts {
+		for _, ch := range p {
+			c, err := ParseCard(string(ch))
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+func CardsToString(cc []Card) string {
+	parts := make([]string, len(cc))
+	for i, c := range cc {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,127 @@
+package cards
+
+import "testing"
+
+func TestParseCards(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []Rank
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"3", []Rank{RankThree}},
+		{"3 4 5", []Rank{RankThree, RankFour, RankFive}},
+		{"345", []Rank{RankThree, RankFour, RankFive}},
+		{"3,4, 5", []Rank{RankThree, RankFour, RankFive}},
+		{"x j q k", []Rank{RankTen, RankJack, RankQueen, RankKing}},
+		{"1 2 0", []Rank{RankAce, RankTwo, RankJoker}},
+		{"KK 22", []Rank{RankKing, RankKing, RankTwo, RankTwo}},
+	}
+	for _, tt := range tests {
+		got, err := ParseCards(tt.in)
+		if err != nil {
+			t.Errorf("ParseCards(%q): %v", tt.in, err)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseCards(%q) = %v, want %d cards", tt.in, got, len(tt.want))
+			continue
+		}
+		for i, c := range got {
+			if c.Rank != tt.want[i] {
+				t.Errorf("ParseCards(%q)[%d] = %s, want rank %d", tt.in, i, c, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestParseCardsInvalid(t *testing.T) {
+	for _, in := range []string{"A", "T", "3 Z", "K-", "?"} {
+		if cc, err := ParseCards(in); err == nil {
+			t.Errorf("ParseCards(%q) = %v, want error", in, cc)
+		}
+	}
+}
+
+func TestParseCardsRoundTrip(t *testing.T) {
+	const s = "3 4 5 6 7 8 9 X J Q K 1 2 0"
+	cc, err := ParseCards(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := CardsToString(cc); got != s {
+		t.Errorf("CardsToString(ParseCards(%q)) = %q", s, got)
+	}
+}
+
+func TestCardClasses(t *testing.T) {
+	cc, _ := ParseCards("1 2 0 K")
+	ace, two, joker, king := cc[0], cc[1], cc[2], cc[3]
+	if !ace.IsAce() || ace.IsSpecial() {
+		t.Errorf("aas: IsAce=%v IsSpecial=%v", ace.IsAce(), ace.IsSpecial())
+	}
+	if !two.IsWild() || two.IsReset() || !two.IsSpecial() {
+		t.Errorf("twee: IsWild=%v IsReset=%v IsSpecial=%v", two.IsWild(), two.IsReset(), two.IsSpecial())
+	}
+	if !joker.IsReset() || joker.IsWild() || !joker.IsSpecial() {
+		t.Errorf("joker: IsWild=%v IsReset=%v IsSpecial=%v", joker.IsWild(), joker.IsReset(), joker.IsSpecial())
+	}
+	if king.IsSpecial() || king.Rank >= ace.Rank {
+		t.Errorf("koning moet een normale kaart onder de aas zijn")
+	}
+}
+
+func TestDeck(t *testing.T) {
+	d := NewDeck()
+	if len(d.Cards) != 54 {
+		t.Fatalf("NewDeck: %d kaarten, want 54", len(d.Cards))
+	}
+	counts := map[Rank]int{}
+	for _, c := range d.Cards {
+		counts[c.Rank]++
+	}
+	for _, r := range NormalRanks() {
+		if counts[r] != 4 {
+			t.Errorf("rank %s: %d kaarten, want 4", Card{Rank: r}, counts[r])
+		}
+	}
+	if counts[RankTwo] != 4 || counts[RankJoker] != 2 {
+		t.Errorf("twee: %d, joker: %d, want 4 en 2", counts[RankTwo], counts[RankJoker])
+	}
+	if n := len(NewMultiDeck(2).Cards); n != 108 {
+		t.Errorf("NewMultiDeck(2): %d kaarten, want 108", n)
+	}
+}
+
+func TestDeal(t *testing.T) {
+	for _, tt := range []struct{ players, dead int }{{2, 18}, {3, 0}} {
+		hands, rest := NewDeck().Deal(tt.players, 18)
+		for i, h := range hands {
+			if h.Count() != 18 {
+				t.Errorf("%d spelers: hand %d heeft %d kaarten", tt.players, i, h.Count())
+			}
+		}
+		if len(rest) != tt.dead {
+			t.Errorf("%d spelers: %d dode kaarten, want %d", tt.players, len(rest), tt.dead)
+		}
+	}
+}
+
+func TestHandRemove(t *testing.T) {
+	cc, _ := ParseCards("3 3 K 2")
+	h := NewHand(cc)
+	rm, _ := ParseCards("3 2")
+	if err := h.Remove(rm); err != nil {
+		t.Fatal(err)
+	}
+	if got := CardsToString(h.Cards); got != "3 K" {
+		t.Errorf("na Remove: %q, want %q", got, "3 K")
+	}
+	bad, _ := ParseCards("K K")
+	if err := h.Remove(bad); err == nil {
+		t.Error("Remove van ontbrekende kaart gaf geen fout")
+	}
+	if h.Count() != 2 {
+		t.Errorf("mislukte Remove veranderde de hand: %v", h.Cards)
+	}
+}
@@ -0,0 +1,66 @@
+package cards
+
+import (
+	"math/rand"
+)
+
+type Deck struct {
+	Cards []Card
+}
+
+func NewDeck() *Deck {
+	d := &Deck{}
+	suits := []Suit{SuitHearts, SuitDiamonds, SuitClubs, SuitSpades}
+	ranks := []Rank{
+		RankThree, RankFour, RankFive, RankSix, RankSeven,
+		RankEight, RankNine, RankTen, RankJack, RankQueen, RankKing,
+		RankAce, RankTwo, // Aas = hoogste naturelle; Twee = wildcard
+	}
+	for _, s := range suits {
+		for _, r := range ranks {
+			d.Cards = append(d.Cards, Card{r, s})
+		}
+	}
+	d.Cards = append(d.Cards, Card{RankJoker, SuitJoker1})
+	d.Cards = append(d.Cards, Card{RankJoker, SuitJoker2})
+	return d
+}
+
+func NewMultiDeck(n int) *Deck {
+	d := &Deck{}
+	for i := 0; i < n; i++ {
+		single := NewDeck()
+		d.Cards = append(d.Cards, single.Cards...)
+	}
+	return d
+}
+
+func (d *Deck) Shuffle(rng *rand.Rand) {
+	rng.Shuffle(len(d.Cards), func(i, j int) {
+		d.Cards[i], d.Cards[j] = d.Cards[j], d.Cards[i]
+	})
+}
+
+func (d *Deck) Deal(numPlayers, cardsPerPlayer int) ([]*Hand, []Card) {
+	hands := make([]*Hand, numPlayers)
+	for i := range hands {
+		hands[i] = &Hand{}
+	}
+	idx := 0
+	for c := 0; c < cardsPerPlayer; c++ {
+		for p := 0; p < numPlayers; p++ {
+			if idx < len(d.Cards) {
+				hands[p].Cards = append(hands[p].Cards, d.Cards[idx])
+				idx++
+			}
+		}
+	}
+	return hands, d.Cards[idx:]
+}
+
+func NormalRanks() []Rank {
+	return []Rank{
+		RankThree, RankFour, RankFive, RankSix, RankSeven,
+		RankEight, RankNine, RankTen, RankJack, RankQueen, RankKing, RankAce,
+	}
+}
@@ -0,0 +1,113 @@
+package cards
+
+import (
+	"fmt"
+	"sort"
+)
+
+type Hand struct {
+	Cards []Card
+}
+
+func NewHand(cc []Card) *Hand {
+	h := &Hand{Cards: make([]Card, len(cc))}
+	copy(h.Cards, cc)
+	return h
+}
+
+func (h *Hand) Count() int    { return len(h.Cards) }
+func (h *Hand) IsEmpty() bool { return len(h.Cards) == 0 }
+
+func (h *Hand) Remove(cc []Card) error {
+	rem := make([]Card, len(h.Cards))
+	copy(rem, h.Cards)
+	for _, c := range cc {
+		found := false
+		for i, hc := range rem {
+			if hc.Rank == c.Rank {
+				rem = append(rem[:i], rem[i+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			for i, hc := range rem {
+				if hc.Rank == 0 {
+					rem = append(rem[:i], rem[i+1:]...)
+					found = true
+					break
+				}
+			}
+		}
+		if !found {
+			return fmt.Errorf("kaart %s niet in hand", c)
+		}
+	}
+	h.Cards = rem
+	return nil
+}
+
+func (h *Hand) Has(c Card) bool {
+	for _, hc := range h.Cards {
+		if hc.Rank == c.Rank {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Hand) CountWilds() int {
+	n := 0
+	for _, c := range h.Cards {
+		if c.IsWild() {
+			n++
+		}
+	}
+	return n
+}
+
+func (h *Hand) CountResets() int {
+	n := 0
+	for _, c := range h.Cards {
+		if c.IsReset() {
+			n++
+		}
+	}
+	return n
+}
+
+func (h *Hand) CountRank(r Rank) int {
+	n := 0
+	for _, c := range h.Cards {
+		if c.Rank == r {
+			n++
+		}
+	}
+	return n
+}
+
+func (h *Hand) GetByRank(r Rank) []Card {
+	var res []Card
+	for _, c := range h.Cards {
+		if c.Rank == r {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+func (h *Hand) Sort() {
+	sort.Slice(h.Cards, func(i, j int) bool {
+		if h.Cards[i].Rank != h.Cards[j].Rank {
+			return h.Cards[i].Rank < h.Cards[j].Rank
+		}
+		return h.Cards[i].Suit < h.Cards[j].Suit
+	})
+}
+
+func (h *Hand) String() string {
+	h.Sort()
+	return CardsToString(h.Cards)
+}
+
+func (h *Hand) Clone() *Hand { return NewHand(h.Cards) }
@@ -0,0 +1,286 @@
+// Command azen-bundle voegt de AZEN packages samen tot één zelfstandig
+// bestand (package main, enkel standaardbibliotheek) voor Termux/Android.
+//
+// Gebruik (vanuit de module-root):
+//
+//	go run ./cmd/azen-bundle -o azen-termux.go
+//	go build azen-termux.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// mainPackage is het startpunt van de bundel; alle interne packages die het
+// (transitief) importeert komen mee, afhankelijkheden eerst.
+const mainPackage = "cmd/azen"
+
+func main() {
+	out := flag.String("o", "azen-termux.go", "uitvoerbestand")
+	root := flag.String("root", ".", "module-root (map met go.mod)")
+	flag.Parse()
+
+	src, err := bundle(*root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "azen-bundle: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*out, src, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "azen-bundle: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func modulePath(root string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.TrimSpace(rest), nil
+		}
+	}
+	return "", fmt.Errorf("geen module-regel in go.mod")
+}
+
+func bundle(root string) ([]byte, error) {
+	mod, err := modulePath(root)
+	if err != nil {
+		return nil, err
+	}
+	order, err := packageOrder(root, mod, mainPackage)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	imports := map[string]bool{}
+	declared := map[string]string{}
+	var body bytes.Buffer
+
+	for _, dir := range order {
+		files, err := filepath.Glob(filepath.Join(root, dir, "*.go"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+		fmt.Fprintf(&body, "\n// %s\n// %s\n// %s\n",
+			strings.Repeat("═", 63), strings.ToUpper(dir), strings.Repeat("═", 63))
+		for _, path := range files {
+			if strings.HasSuffix(path, "_test.go") {
+				continue
+			}
+			src, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+			if err != nil {
+				return nil, err
+			}
+			if err := collectDecls(f, dir, declared); err != nil {
+				return nil, err
+			}
+			text, err := stripFile(fset, f, src, mod, imports)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", path, err)
+			}
+			body.WriteString("\n")
+			body.Write(text)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by azen-bundle; DO NOT EDIT.\n\n")
+	buf.WriteString("// azen-termux.go\n")
+	buf.WriteString("// Standalone single-file versie van de AZEN engine voor Termux/Android.\n")
+	buf.WriteString("// Bevat alle code in één bestand zonder externe afhankelijkheden.\n")
+	buf.WriteString("//\n// Compileer: go build azen-termux.go\n// Starten:   go run azen-termux.go\n\n")
+	buf.WriteString("package main\n\nimport (\n")
+	paths := make([]string, 0, len(imports))
+	for p := range imports {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	// In één bestand mogen twee imports geen naam delen (crypto/rand en
+	// math/rand): de bron moet er dan één een alias geven.
+	names := map[string]string{}
+	for _, p := range paths {
+		name, path, aliased := strings.Cut(p, " ")
+		if !aliased {
+			path = name
+			unq, _ := strconv.Unquote(path)
+			name = unq[strings.LastIndex(unq, "/")+1:]
+		}
+		if prev, ok := names[name]; ok && prev != path {
+			return nil, fmt.Errorf("import-naam %q botst: %s en %s (gebruik een alias)", name, prev, path)
+		}
+		names[name] = path
+	}
+	for _, p := range paths {
+		fmt.Fprintf(&buf, "\t%s\n", p)
+	}
+	buf.WriteString(")\n")
+	buf.Write(body.Bytes())
+	return format.Source(buf.Bytes())
+}
+
+// packageOrder geeft dir en al zijn interne afhankelijkheden terug in
+// topologische volgorde (afhankelijkheden eerst).
+func packageOrder(root, mod, dir string) ([]string, error) {
+	var order []string
+	state := map[string]int{} // 1 = bezig, 2 = klaar
+	var visit func(dir string) error
+	visit = func(dir string) error {
+		switch state[dir] {
+		case 1:
+			return fmt.Errorf("importcyclus via %s", dir)
+		case 2:
+			return nil
+		}
+		state[dir] = 1
+		files, err := filepath.Glob(filepath.Join(root, dir, "*.go"))
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("geen Go-bestanden in %s", dir)
+		}
+		var deps []string
+		for _, path := range files {
+			if strings.HasSuffix(path, "_test.go") {
+				continue
+			}
+			f, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ImportsOnly)
+			if err != nil {
+				return err
+			}
+			for _, imp := range f.Imports {
+				p, _ := strconv.Unquote(imp.Path.Value)
+				if dep, ok := strings.CutPrefix(p, mod+"/"); ok {
+					deps = append(deps, dep)
+				}
+			}
+		}
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[dir] = 2
+		order = append(order, dir)
+		return nil
+	}
+	return order, visit(dir)
+}
+
+// collectDecls registreert alle top-level namen en faalt bij een botsing,
+// want in één package main kunnen twee packages geen naam delen.
+func collectDecls(f *ast.File, dir string, declared map[string]string) error {
+	add := func(name string) error {
+		if name == "_" || name == "init" {
+			return nil
+		}
+		if prev, ok := declared[name]; ok {
+			return fmt.Errorf("naam %q bestaat in zowel %s als %s", name, prev, dir)
+		}
+		declared[name] = dir
+		return nil
+	}
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				if err := add(d.Name.Name); err != nil {
+					return err
+				}
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if err := add(s.Name.Name); err != nil {
+						return err
+					}
+				case *ast.ValueSpec:
+					for _, n := range s.Names {
+						if err := add(n.Name); err != nil {
+							return err
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// stripFile geeft de broncode van f terug zonder package-clausule en imports,
+// met alle verwijzingen naar interne packages (cards.Card → Card) ontdaan
+// van hun qualifier. Standaard-imports worden in imports verzameld.
+func stripFile(fset *token.FileSet, f *ast.File, src []byte, mod string, imports map[string]bool) ([]byte, error) {
+	internal := map[string]bool{}
+	start := fset.Position(f.Name.End()).Offset
+	for _, imp := range f.Imports {
+		p, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(p, mod+"/") {
+			name := p[strings.LastIndex(p, "/")+1:]
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			internal[name] = true
+			continue
+		}
+		if imp.Name != nil {
+			imports[imp.Name.Name+" "+imp.Path.Value] = true
+		} else {
+			imports[imp.Path.Value] = true
+		}
+	}
+	for _, d := range f.Decls {
+		if g, ok := d.(*ast.GenDecl); ok && g.Tok == token.IMPORT {
+			start = fset.Position(g.End()).Offset
+		}
+	}
+
+	type cut struct{ from, to int }
+	var cuts []cut
+	ast.Inspect(f, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); ok && x.Obj == nil && internal[x.Name] {
+			cuts = append(cuts, cut{fset.Position(x.Pos()).Offset, fset.Position(sel.Sel.Pos()).Offset})
+		}
+		return true
+	})
+	sort.Slice(cuts, func(i, j int) bool { return cuts[i].from < cuts[j].from })
+
+	var out bytes.Buffer
+	pos := start
+	for _, c := range cuts {
+		if c.from < pos {
+			continue
+		}
+		out.Write(src[pos:c.from])
+		pos = c.to
+	}
+	out.Write(src[pos:])
+	return out.Bytes(), nil
+}
@@ -0,0 +1,543 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/azen-engine/analysis"
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/engine"
+	"github.com/azen-engine/game"
+	"github.com/azen-engine/gameio"
+	"github.com/azen-engine/knowledge"
+)
+
+func analyzeMode(reader *Reader, cfg settings) {
+	PrintHeader("Analyse Modus")
+	fmt.Println("Voer het volledige spel in voor analyse, of laad een opgeslagen partij.")
+	fmt.Println()
+	var loaded *gameio.Record
+	var numPlayers, start int
+	var hands []*cards.Hand
+	var deadCards []cards.Card
+	if path := reader.ReadLine("Opgeslagen partij (bestand, leeg = zelf invoeren): "); path != "" {
+		rec, err := loadRecord(path)
+		var log *gameio.GameLog
+		if err == nil {
+			log, err = rec.ToLog()
+		}
+		if err != nil {
+			fmt.Printf("Fout: %v\n", err)
+			return
+		}
+		loaded, numPlayers, start, deadCards = rec, log.NumPlayers, rec.Start, log.DeadCards
+		for _, h := range log.Hands {
+			hands = append(hands, cards.NewHand(h))
+		}
+		fmt.Printf("📂 Partij geladen: %d spelers, %d beurten.\n\n", numPlayers, len(rec.Turns))
+	} else {
+		numPlayers, hands, deadCards = readStartHands(reader)
+	}
+	engConfig := cfg.engineConfig(numPlayers)
+	engConfig.OmniscientMode = true
+	engConfig.Seed = cfg.seed
+	iters := 3000
+	if n, err := reader.ReadInt("Iteraties per zet (standaard 3000, meer = nauwkeuriger maar trager): "); err == nil && n > 0 {
+		iters = n
+	}
+	engConfig.Iterations = iters
+	engConfig.NumWorkers = cfg.numThreads
+	analyzeStr := reader.ReadLine(fmt.Sprintf("Welke speler(s) analyseren? (bv. '1' of '1,3', leeg = alle %d spelers): ", numPlayers))
+	analyzePlayers := map[int]bool{}
+	if s := strings.ToLower(strings.TrimSpace(analyzeStr)); s != "" && s != "alle" {
+		for _, part := range strings.Split(analyzeStr, ",") {
+			if n, err := strconv.Atoi(strings.TrimSpace(part)); err == nil && n >= 1 && n <= numPlayers {
+				analyzePlayers[n-1] = true
+			}
+		}
+	}
+	g := analysis.NewReplay(hands, deadCards, start, analyzePlayers)
+	if loaded != nil {
+		fmt.Println()
+		for i, t := range loaded.Turns {
+			moves, err := t.Moves()
+			if err != nil || len(moves) == 0 || !playTurn(g, engConfig, moves[0], followOf(moves)) {
+				fmt.Printf("⚠️  Beurt %d uit het bestand is ongeldig; de rest wordt niet ingelezen.\n\n", i+1)
+				break
+			}
+		}
+	}
+	if !g.GS.GameOver {
+		fmt.Println("\nVoer nu elke zet van het spel in.")
+		fmt.Println("Formaat: 'speler:kaarten'  bv. '1:KK' of '2:-' (pas) of '1:11/444' (aas+vervolg)")
+		fmt.Println("Zonder spelernummer gebruikt de engine de speler aan de beurt.")
+		fmt.Println("Typ 'undo' om de vorige zet terug te nemen, 'redo' om hem opnieuw te spelen.")
+		fmt.Println("Typ 'klaar' om te stoppen.")
+		fmt.Println()
+	}
+	for !g.GS.GameOver {
+		gs := g.GS
+		fmt.Printf("--- Zet %d (Speler %d aan de beurt) ---\n", g.Turns()+1, gs.CurrentTurn+1)
+		input := reader.ReadLine("Zet: ")
+		lower := strings.ToLower(strings.TrimSpace(input))
+		if lower == "klaar" || lower == "done" {
+			break
+		}
+		switch lower {
+		case "undo":
+			if undone, err := g.Undo(); err != nil {
+				fmt.Printf("⚠️  %v\n\n", err)
+			} else {
+				fmt.Printf("↩️  Teruggenomen: Speler %d: %s\n\n", undone[0].PlayerID+1, movesLabel(undone))
+			}
+		case "redo":
+			if redone, err := g.Redo(); err != nil {
+				fmt.Printf("⚠️  %v\n\n", err)
+			} else {
+				fmt.Printf("↪️  Opnieuw: Speler %d: %s\n\n", redone[0].PlayerID+1, movesLabel(redone))
+			}
+		default:
+			analyzeTurn(g, engConfig, input)
+		}
+	}
+	if g.GS.GameOver {
+		fmt.Println()
+		printRanking(g.GS)
+	}
+	fmt.Println("\nAnalyse klaar.")
+	if g.Turns() > 0 {
+		offerSave(reader, analyzeRecord(g, engConfig))
+	}
+}
+
+// analyzeRecord is de geanalyseerde partij als record, met de beoordelingen.
+func analyzeRecord(g *analysis.Replay, engConfig engine.Config) *gameio.Record {
+	rec := g.Record()
+	rec.Meta.Seed = engConfig.Seed
+	rec.Meta.Iterations = engConfig.Iterations
+	return rec
+}
+
+// readStartHands vraagt het aantal spelers, hun starthanden en (bij 2
+// spelers) de dode kaarten.
+func readStartHands(reader *Reader) (int, []*cards.Hand, []cards.Card) {
+	numPlayers := 2
+	if n, err := reader.ReadInt("Aantal spelers (2/3/4): "); err == nil && n >= 2 && n <= 4 {
+		numPlayers = n
+	}
+	hands := make([]*cards.Hand, numPlayers)
+	for i := 0; i < numPlayers; i++ {
+		cardCount := 18
+		if n, err := reader.ReadInt(fmt.Sprintf("Aantal startkaarten voor Speler %d (standaard 18): ", i+1)); err == nil && n > 0 {
+			cardCount = n
+		}
+		fmt.Printf("\nVoer de starthand van Speler %d in (%d kaarten):\n", i+1, cardCount)
+		for {
+			parsed, err := reader.ReadCards(fmt.Sprintf("Speler %d kaarten: ", i+1))
+			if err != nil {
+				fmt.Printf("Fout: %v\n", err)
+				continue
+			}
+			if len(parsed) != cardCount {
+				fmt.Printf("Verwacht %d, kreeg %d\n", cardCount, len(parsed))
+				continue
+			}
+			hands[i] = cards.NewHand(parsed)
+			break
+		}
+	}
+	var deadCards []cards.Card
+	if numPlayers == 2 {
+		if reader.ReadYesNo("Dode kaarten invoeren?") {
+			for {
+				parsed, err := reader.ReadCards("Dode kaarten: ")
+				if err != nil {
+					fmt.Printf("Fout: %v\n", err)
+					continue
+				}
+				deadCards = parsed
+				break
+			}
+		}
+	}
+	return numPlayers, hands, deadCards
+}
+
+// analyzeTurn leest één beurt ("1:KK", "2:-", "0/55"), beoordeelt ze als de
+// speler geanalyseerd wordt en speelt ze op g.
+func analyzeTurn(g *analysis.Replay, engConfig engine.Config, input string) {
+	gs := g.GS
+	parts := strings.SplitN(input, ":", 2)
+	playerStr := strings.TrimSpace(parts[0])
+	cardsStr := ""
+	if len(parts) > 1 {
+		cardsStr = strings.TrimSpace(parts[1])
+	} else {
+		cardsStr = playerStr
+		playerStr = strconv.Itoa(gs.CurrentTurn + 1)
+	}
+	playerNum, _ := strconv.Atoi(playerStr)
+	playerID := playerNum - 1
+	if playerID < 0 {
+		playerID = gs.CurrentTurn
+	}
+	mainCardsStr, followCardsStr, hasFollowCards := strings.Cut(cardsStr, "/")
+	mainCardsStr = strings.TrimSpace(mainCardsStr)
+	mainCardsLower := strings.ToLower(mainCardsStr)
+	var move game.Move
+	if mainCardsLower == "pass" || mainCardsLower == "p" || mainCardsStr == "-" {
+		move = game.Move{PlayerID: playerID, IsPass: true}
+	} else {
+		parsed, err := cards.ParseCards(mainCardsStr)
+		if err != nil {
+			fmt.Printf("Fout: %v\n", err)
+			return
+		}
+		move = game.Move{PlayerID: playerID, Cards: parsed}
+	}
+	var follow *game.Move
+	if hasFollowCards {
+		parsed, err := cards.ParseCards(strings.TrimSpace(followCardsStr))
+		if err != nil {
+			fmt.Printf("⚠️  Fout in vervolg-zet: %v\n", err)
+		} else {
+			follow = &game.Move{PlayerID: playerID, Cards: parsed}
+		}
+	}
+	playTurn(g, engConfig, move, follow)
+}
+
+// followOf is de vervolg-zet van een beurt, of nil.
+func followOf(moves []game.Move) *game.Move {
+	if len(moves) < 2 {
+		return nil
+	}
+	return &moves[1]
+}
+
+// playTurn speelt één beurt (zet met eventuele vervolg-zet) in de replay,
+// analyseert ze als de speler geanalyseerd wordt en toont het resultaat.
+// De beoordeling gaat mee in het record. Geeft false als de zet ongeldig is.
+func playTurn(g *analysis.Replay, engConfig engine.Config, move game.Move, follow *game.Move) bool {
+	gs := g.GS
+	playerID := move.PlayerID
+	if err := gs.ValidateMove(move); err != nil {
+		fmt.Printf("Ongeldige zet: %v\n", err)
+		return false
+	}
+	doAnalysis := g.Analyzed(playerID)
+	var ma analysis.MoveAnalysis
+	if doAnalysis {
+		ma = analysis.Analyze(engConfig, gs, g.Trackers[playerID], move)
+	}
+	g.Apply(move)
+	if doAnalysis {
+		g.Annotate(ma.Annotation())
+	}
+	gs = g.GS
+	moveLabel := game.FormatMove(move)
+	if follow != nil && !gs.GameOver && gs.CurrentTurn == playerID {
+		if err := g.ApplyFollow(*follow); err != nil {
+			fmt.Printf("⚠️  Ongeldige vervolg-zet: %v\n", err)
+		} else {
+			moveLabel = fmt.Sprintf("%s / %s", game.FormatMove(move), game.FormatMove(*follow))
+		}
+	}
+	if doAnalysis {
+		printMoveAnalysis(ma, "Gespeeld: "+moveLabel)
+	} else {
+		fmt.Printf("⏭️  Speler %d: %s\n", playerID+1, moveLabel)
+	}
+	if !gs.GameOver && gs.Finished[playerID] && gs.Hands[playerID].IsEmpty() {
+		rank := gs.PlayerRank(playerID)
+		medals := []string{"🥇", "🥈", "🥉"}
+		m := ""
+		if rank >= 0 && rank < len(medals) {
+			m = medals[rank]
+		}
+		fmt.Printf("%s Speler %d eindigt op plaats %d!\n", m, playerID+1, rank+1)
+	}
+	fmt.Println()
+	return true
+}
+
+// movesLabel toont de zetten van één beurt in invoernotatie ("0 / K K").
+func movesLabel(moves []game.Move) string {
+	labels := make([]string, len(moves))
+	for i, m := range moves {
+		labels[i] = game.FormatMove(m)
+	}
+	return strings.Join(labels, " / ")
+}
+
+func quickAnalyzeMode(reader *Reader, cfg settings) {
+	PrintHeader("Snelle Analyse")
+	if path := reader.ReadLine("Opgeslagen partij (bestand, leeg = zelf invoeren): "); path != "" {
+		quickAnalyzeFile(reader, cfg, path)
+		return
+	}
+	fmt.Println("Voer de partij in één keer in.")
+	fmt.Println("Zetten: spatie-gescheiden tokens die alterneren tussen spelers.")
+	fmt.Println("Aas+vervolg: schrijf als '1/5' (aas, dan 5 in dezelfde beurt).")
+	fmt.Println("Pas: p of - of pass")
+	numPlayers := 2
+	if n, err := reader.ReadInt("Aantal spelers (2/3/4): "); err == nil && n >= 2 && n <= 4 {
+		numPlayers = n
+	}
+	analyzePlayer := 0
+	if p, err := reader.ReadInt(fmt.Sprintf("Welke speler analyseren (1-%d): ", numPlayers)); err == nil && p >= 1 && p <= numPlayers {
+		analyzePlayer = p - 1
+	}
+	hands := make([]*cards.Hand, numPlayers)
+	for i := 0; i < numPlayers; i++ {
+		cardCount := 18
+		if n, err := reader.ReadInt(fmt.Sprintf("Aantal startkaarten voor Speler %d (standaard 18): ", i+1)); err == nil && n > 0 {
+			cardCount = n
+		}
+		for {
+			parsed, err := reader.ReadCards(fmt.Sprintf("Speler %d kaarten (%d): ", i+1, cardCount))
+			if err != nil {
+				fmt.Printf("Fout: %v\n", err)
+				continue
+			}
+			if len(parsed) != cardCount {
+				fmt.Printf("Verwacht %d, kreeg %d\n", cardCount, len(parsed))
+				continue
+			}
+			hands[i] = cards.NewHand(parsed)
+			break
+		}
+	}
+	var deadCards []cards.Card
+	startPlayer := 0
+	if p, err := reader.ReadInt(fmt.Sprintf("Wie begint (spelernummer 1-%d): ", numPlayers)); err == nil && p >= 1 && p <= numPlayers {
+		startPlayer = p - 1
+	}
+	iters := 3000
+	if n, err := reader.ReadInt("Iteraties per zet (standaard 3000): "); err == nil && n > 0 {
+		iters = n
+	}
+	fmt.Println()
+	fmt.Printf("Voer alle zetten in als spatie-gescheiden tokens (bv: 8888 p 33 44 66 jj p 4 5 9 1/5 ...)\n")
+	movesLine := reader.ReadLine("Zetten: ")
+	tokens := strings.Fields(movesLine)
+	if len(tokens) == 0 {
+		fmt.Println("Geen zetten ingevoerd.")
+		return
+	}
+	gs := game.NewGameWithHands(hands, deadCards, startPlayer)
+	engConfig := cfg.engineConfig(numPlayers)
+	engConfig.OmniscientMode = true
+	engConfig.Iterations = iters
+	engConfig.NumWorkers = cfg.numThreads
+	engConfig.Seed = cfg.seed
+	trackers := make([]*knowledge.KnowledgeTracker, numPlayers)
+	for p := 0; p < numPlayers; p++ {
+		trackers[p] = knowledge.NewKnowledgeTracker(numPlayers, p, gs.Hands[p], gs.DeadCards)
+	}
+	rec := gameio.NewRecord(gs)
+	rec.Meta.Source = "analyze"
+	rec.Meta.Seed = engConfig.Seed
+	rec.Meta.Iterations = iters
+	fmt.Println()
+	moveNum := 0
+	ti := 0
+	for ti < len(tokens) && !gs.GameOver {
+		token := tokens[ti]
+		ti++
+		moveNum++
+		playerID := gs.CurrentTurn
+		mainStr, followStr, hasFollow := strings.Cut(token, "/")
+		mainLower := strings.ToLower(strings.TrimSpace(mainStr))
+		var move game.Move
+		if mainLower == "p" || mainLower == "pass" || mainLower == "-" {
+			move = game.PassMove(playerID)
+		} else {
+			parsed, err := cards.ParseCards(mainStr)
+			if err != nil {
+				fmt.Printf("⚠️  Token %d (%q): %v — overgeslagen\n", moveNum, token, err)
+				continue
+			}
+			move = game.Move{PlayerID: playerID, Cards: parsed}
+		}
+		doAnalysis := playerID == analyzePlayer
+		var ma analysis.MoveAnalysis
+		if doAnalysis {
+			ma = analysis.Analyze(engConfig, gs, trackers[playerID], move)
+		}
+		if err := gs.ValidateMove(move); err != nil {
+			fmt.Printf("⚠️  Token %d (%q): ongeldige zet: %v — overgeslagen\n", moveNum, token, err)
+			continue
+		}
+		if move.IsPass {
+			for p := 0; p < numPlayers; p++ {
+				if trackers[p] != nil {
+					trackers[p].RecordPass(move.PlayerID, gs.Round)
+				}
+			}
+		}
+		gs.ApplyMove(move)
+		for p := 0; p < numPlayers; p++ {
+			if trackers[p] != nil {
+				trackers[p].RecordMove(move)
+			}
+		}
+		if t := rec.AddMove(move); doAnalysis {
+			t.Eval = ma.Annotation()
+		}
+		moveLabel := game.FormatMove(move)
+		if hasFollow && !gs.GameOver && gs.CurrentTurn == playerID {
+			followStr = strings.TrimSpace(followStr)
+			parsed, err := cards.ParseCards(followStr)
+			if err == nil {
+				followMove := game.Move{PlayerID: playerID, Cards: parsed}
+				if err2 := gs.ValidateMove(followMove); err2 == nil {
+					gs.ApplyMove(followMove)
+					rec.AddMove(followMove)
+					for p := 0; p < numPlayers; p++ {
+						if trackers[p] != nil {
+							trackers[p].RecordMove(followMove)
+						}
+					}
+					moveLabel = fmt.Sprintf("%s / %s", game.FormatMove(move), game.FormatMove(followMove))
+				} else {
+					fmt.Printf("⚠️  Vervolg-zet %q ongeldig: %v\n", followStr, err2)
+				}
+			} else {
+				fmt.Printf("⚠️  Vervolg-zet %q fout: %v\n", followStr, err)
+			}
+		}
+		if doAnalysis {
+			printMoveAnalysis(ma, fmt.Sprintf("Z%d P%d: %s", moveNum, playerID+1, moveLabel))
+		} else {
+			fmt.Printf("⏭️  Z%d P%d: %s\n", moveNum, playerID+1, moveLabel)
+		}
+	}
+	fmt.Println()
+	if gs.GameOver {
+		printRanking(gs)
+	} else {
+		fmt.Printf("Partij gestopt na %d zetten (spel nog niet voorbij).\n", moveNum)
+	}
+	fmt.Println("\nSnelle analyse klaar.")
+	rec.Finish(gs)
+	offerSave(reader, rec)
+}
+
+// quickAnalyzeFile analyseert een opgeslagen partij (tekstlog of JSON).
+func quickAnalyzeFile(reader *Reader, cfg settings, path string) {
+	log, err := loadLog(path)
+	if err != nil {
+		fmt.Printf("Fout: %v\n", err)
+		return
+	}
+	if log.NumPlayers < 2 || log.NumPlayers > 4 {
+		fmt.Printf("Fout: ongeldig aantal spelers: %d\n", log.NumPlayers)
+		return
+	}
+	analyzePlayer := 0
+	if p, err := reader.ReadInt(fmt.Sprintf("Welke speler analyseren (1-%d): ", log.NumPlayers)); err == nil && p >= 1 && p <= log.NumPlayers {
+		analyzePlayer = p - 1
+	}
+	iters := 3000
+	if n, err := reader.ReadInt("Iteraties per zet (standaard 3000): "); err == nil && n > 0 {
+		iters = n
+	}
+	engConfig := cfg.engineConfig(log.NumPlayers)
+	engConfig.OmniscientMode = true
+	engConfig.Iterations = iters
+	engConfig.NumWorkers = cfg.numThreads
+	engConfig.Seed = cfg.seed
+	fmt.Println()
+	rec, err := printReplay(engConfig, log, map[int]bool{analyzePlayer: true})
+	if err != nil {
+		fmt.Printf("Fout: %v\n", err)
+		return
+	}
+	fmt.Println("\nSnelle analyse klaar.")
+	offerSave(reader, rec)
+}
+
+// printReplay speelt een log na, toont per zet de analyse (of enkel de zet
+// voor spelers die niet geanalyseerd worden) en daarna de eindstand. Geeft
+// de partij terug als record met de beoordelingen.
+func printReplay(engConfig engine.Config, log *gameio.GameLog, analyzePlayers map[int]bool) (*gameio.Record, error) {
+	rec, err := gameio.FromLog(log)
+	if err != nil {
+		return nil, err
+	}
+	rec.Meta.Source = "analyze"
+	rec.Meta.Seed = engConfig.Seed
+	rec.Meta.Iterations = engConfig.Iterations
+	rec.Turns = nil
+	moveNum := 0
+	gs, err := analysis.ReplayLog(engConfig, log, analyzePlayers, func(p analysis.Ply) {
+		moveNum = p.Num
+		rec.Turns = append(rec.Turns, p.Turn())
+		label := fmt.Sprintf("Z%d P%d: %s", p.Num, p.Move.PlayerID+1, p.Label())
+		if p.Analysis != nil {
+			printMoveAnalysis(*p.Analysis, label)
+		} else {
+			fmt.Printf("⏭️  %s\n", label)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	rec.Finish(gs)
+	fmt.Println()
+	if gs.GameOver {
+		printRanking(gs)
+	} else {
+		fmt.Printf("Partij gestopt na %d zetten (spel nog niet voorbij).\n", moveNum)
+	}
+	return rec, nil
+}
+
+// printMoveAnalysis toont het oordeel (✅ / ⚠️ / ❌) over een gespeelde zet,
+// de betere zet indien relevant en de top-5 alternatieven.
+func printMoveAnalysis(a analysis.MoveAnalysis, label string) {
+	forcedWin := a.Eval.ForcedWinDepth > 0
+	playedIsBest := a.PlayedIsBest()
+	fmt.Printf("%s %s (score: %.1f%%)\n", a.Grade().Emoji(), label, a.Actual.WinRate*100)
+	if forcedWin && !playedIsBest {
+		fmt.Printf("   ♟️  Gedwongen winst in %d beurt(en) gemist! Beste was: %s\n",
+			a.Eval.ForcedWinDepth, a.BestLabel())
+	} else if forcedWin && playedIsBest {
+		fmt.Printf("   ♟️  Gedwongen winst in %d beurt(en)!\n", a.Eval.ForcedWinDepth)
+	} else if a.ShowBest() {
+		fmt.Printf("   Beste was: %s (score: %.1f%%, verschil: %.1f%%)\n",
+			a.BestLabel(), a.Eval.Score*100, a.Diff()*100)
+	}
+	// Diagnostiek: toon top alternatieven (gesorteerd op score, max 5)
+	if len(a.Eval.Details) > 1 {
+		sorted := make([]engine.MoveDetail, len(a.Eval.Details))
+		copy(sorted, a.Eval.Details)
+		for i := 0; i < len(sorted); i++ {
+			for j := i + 1; j < len(sorted); j++ {
+				if sorted[j].WinRate > sorted[i].WinRate {
+					sorted[i], sorted[j] = sorted[j], sorted[i]
+				}
+			}
+		}
+		fmt.Printf("   Top: ")
+		limit := len(sorted)
+		if limit > 5 {
+			limit = 5
+		}
+		for k := 0; k < limit; k++ {
+			d := sorted[k]
+			label := game.FormatMove(d.Move)
+			marker := ""
+			if game.MovesEqual(d.Move, a.Move) {
+				marker = "←"
+			}
+			if k > 0 {
+				fmt.Printf(" | ")
+			}
+			fmt.Printf("%s %.1f%%%s", label, d.WinRate*100, marker)
+		}
+		fmt.Println()
+	}
+}
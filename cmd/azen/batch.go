@@ -0,0 +1,221 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/azen-engine/analysis"
+	"github.com/azen-engine/engine"
+	"github.com/azen-engine/gameio"
+)
+
+// batchGame is één partij uit een batch: een log met de naam waaronder ze
+// in het rapport verschijnt en de spelersnamen (leeg = "Speler n").
+type batchGame struct {
+	label string
+	log   *gameio.GameLog
+	names []string
+}
+
+// batchResult is de analyse van één partij, per spelersnaam.
+type batchResult struct {
+	stats map[string]*analysis.Stats
+	plies int
+	err   error
+}
+
+func runAnalyzeBatch(args []string) error {
+	fset := flag.NewFlagSet("analyze-batch", flag.ContinueOnError)
+	var ef engineFlags
+	ef.register(fset, 3000)
+	playersStr := fset.String("analyze", "", "te analyseren spelers (zitplaatsen), bv. 1,3 (leeg = alle)")
+	jobs := fset.Int("jobs", 0, "partijen tegelijk (0 = CPU's / workers)")
+	worst := fset.Int("worst", 5, "aantal slechtste zetten per speler")
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+	if fset.NArg() == 0 {
+		return fmt.Errorf("gebruik: azen analyze-batch [flags] <map of partij>...")
+	}
+	games, err := collectGames(fset.Args())
+	if err != nil {
+		return err
+	}
+	if len(games) == 0 {
+		return fmt.Errorf("geen partijen gevonden")
+	}
+	if *jobs <= 0 {
+		*jobs = runtime.NumCPU() / max(ef.workers, 1)
+	}
+	*jobs = max(min(*jobs, len(games)), 1)
+
+	// Gewichten één keer lezen en melden; elke partij krijgt een kopie met
+	// haar eigen aantal spelers.
+	engConfig, err := ef.config()
+	if err != nil {
+		return err
+	}
+
+	results := make([]batchResult, len(games))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < *jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = analyzeBatchGame(engConfig, games[i], *playersStr)
+			}
+		}()
+	}
+	for i := range games {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	total := map[string]*analysis.Stats{}
+	var names []string
+	failed := 0
+	for i, r := range results {
+		if r.err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %s: %v\n", games[i].label, r.err)
+			failed++
+			continue
+		}
+		fmt.Printf("✓ %s (%d beurten)\n", games[i].label, r.plies)
+		for name, s := range r.stats {
+			if total[name] == nil {
+				total[name] = &analysis.Stats{Name: name}
+				names = append(names, name)
+			}
+			total[name].Merge(s)
+		}
+	}
+	sort.Strings(names)
+	printBatchReport(names, total, *worst)
+	if failed > 0 {
+		return fmt.Errorf("%d van %d partijen niet geanalyseerd", failed, len(games))
+	}
+	return nil
+}
+
+// analyzeBatchGame analyseert één partij en telt de zetten per speler.
+func analyzeBatchGame(engConfig engine.Config, g batchGame, playersStr string) batchResult {
+	n := g.log.NumPlayers
+	if n < 2 || n > 4 {
+		return batchResult{err: fmt.Errorf("ongeldig aantal spelers: %d", n)}
+	}
+	engConfig.NumPlayers = n
+	players, err := parsePlayerList(playersStr, n)
+	if err != nil {
+		return batchResult{err: err}
+	}
+	r := batchResult{stats: map[string]*analysis.Stats{}}
+	name := func(p int) string {
+		if p < len(g.names) && g.names[p] != "" {
+			return g.names[p]
+		}
+		return fmt.Sprintf("Speler %d", p+1)
+	}
+	_, r.err = analysis.ReplayLog(engConfig, g.log, players, func(p analysis.Ply) {
+		r.plies = p.Num
+		if p.Analysis == nil {
+			return
+		}
+		s := r.stats[name(p.Move.PlayerID)]
+		if s == nil {
+			s = &analysis.Stats{Name: name(p.Move.PlayerID), Games: 1}
+			r.stats[s.Name] = s
+		}
+		s.Add(g.label, p)
+	})
+	return r
+}
+
+// collectGames leest alle partijen uit paths. Een map wordt recursief
+// doorzocht naar .log, .txt, .json en .jsonl; een bestand met meerdere
+// records geeft "bestand#n" als label.
+func collectGames(paths []string) ([]batchGame, error) {
+	var files []string
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, p)
+			continue
+		}
+		err = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".log", ".txt", ".json", ".jsonl":
+				if !d.IsDir() {
+					files = append(files, path)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	var games []batchGame
+	for _, f := range files {
+		recs, err := gameio.LoadRecords(f)
+		if err != nil {
+			return nil, err
+		}
+		for i, rec := range recs {
+			label := f
+			if len(recs) > 1 {
+				label = fmt.Sprintf("%s#%d", f, i+1)
+			}
+			log, err := rec.ToLog()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  %s: %v (overgeslagen)\n", label, err)
+				continue
+			}
+			games = append(games, batchGame{label: label, log: log, names: rec.Meta.Names})
+		}
+	}
+	return games, nil
+}
+
+// printBatchReport toont per speler nauwkeurigheid, fouten en gemiddeld
+// verlies, gevolgd door de slechtste zetten met het alternatief van de
+// engine.
+func printBatchReport(names []string, total map[string]*analysis.Stats, worst int) {
+	PrintSubHeader("Rapport")
+	fmt.Printf("%-12s %8s %7s %8s %7s %8s %10s\n",
+		"Speler", "Partijen", "Zetten", "Nauwk.", "Onnauw.", "Blunders", "Gem.verlies")
+	for _, name := range names {
+		s := total[name]
+		fmt.Printf("%-12s %8d %7d %7.1f%% %7d %8d %9.1f%%\n",
+			name, s.Games, s.Moves, s.Accuracy()*100, s.Inaccuracies, s.Blunders, s.AvgLoss()*100)
+	}
+	if worst <= 0 {
+		return
+	}
+	for _, name := range names {
+		mistakes := total[name].Worst(worst)
+		if len(mistakes) == 0 {
+			continue
+		}
+		fmt.Printf("\nSlechtste zetten van %s:\n", name)
+		for _, m := range mistakes {
+			fmt.Printf("  %s %s Z%d: %s, beste was %s (-%.1f%%)\n",
+				m.Grade.Emoji(), m.Game, m.Num, m.Played, m.Best, m.Loss*100)
+		}
+	}
+}
@@ -0,0 +1,553 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/engine"
+	"github.com/azen-engine/game"
+	"github.com/azen-engine/gameio"
+	"github.com/azen-engine/protocol"
+	"github.com/azen-engine/server"
+	"github.com/azen-engine/session"
+	"github.com/azen-engine/tuner"
+)
+
+// command is een niet-interactief subcommando (azen <naam> [flags]).
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{"bestmove", "beste zet voor jouw hand na een reeks zetten", runBestMove},
+	{"analyze", "beoordeel elke zet van een opgeslagen partij (azen analyze <log>)", runAnalyze},
+	{"analyze-batch", "analyseer veel partijen tegelijk, met rapport per speler", runAnalyzeBatch},
+	{"convert", "zet een partij om tussen tekstlog, JSON en JSONL", runConvert},
+	{"simulate", "laat de engine tegen zichzelf spelen", runSimulate},
+	{"tournament", "laat engine-varianten tegen elkaar spelen, met Elo en statistiek", runTournament},
+	{"weights", "toon de actieve gewichten en profielen, of bewaar een profiel", runWeights},
+	{"tune", "optimaliseer de gewichten: kandidaten tegen een basislijn op duplicate-deals", runTune},
+	{"engine", "JSON-lines engineprotocol op stdin/stdout (voor GUI's en bots)", runEngine},
+	{"serve", "HTTP/WebSocket-server met webpagina (bv. voor de telefoon)", runServe},
+}
+
+// runCommand voert het subcommando in args[0] uit. Zonder subcommando start
+// main het interactieve menu.
+func runCommand(args []string) int {
+	name := args[0]
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage(os.Stdout)
+		return 0
+	}
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		if err := c.run(args[1:]); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return 0
+			}
+			fmt.Fprintf(os.Stderr, "azen %s: %v\n", name, err)
+			return 1
+		}
+		return 0
+	}
+	fmt.Fprintf(os.Stderr, "azen: onbekend commando %q\n\n", name)
+	printUsage(os.Stderr)
+	return 2
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Gebruik: azen [commando] [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Zonder commando start het interactieve menu.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commando's:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-14s %s\n", c.name, c.usage)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Gebruik 'azen <commando> -h' voor de flags van een commando.")
+}
+
+// engineFlags zijn de engine-instellingen die elk subcommando deelt.
+type engineFlags struct {
+	players int
+	iters   int
+	maxTime time.Duration
+	workers int
+	weights string
+	seed    int64
+}
+
+func (ef *engineFlags) register(fs *flag.FlagSet, defaultIters int) {
+	fs.IntVar(&ef.players, "players", 2, "aantal spelers (2-4)")
+	fs.IntVar(&ef.iters, "iters", defaultIters, "engine-iteraties per zet")
+	fs.DurationVar(&ef.maxTime, "time", 0, "maximale denktijd per zet (0 = geen limiet)")
+	fs.IntVar(&ef.workers, "workers", 2, "parallelle ISMCTS-bomen")
+	fs.StringVar(&ef.weights, "weights", "", "gewichtenbestand (JSON) of profielnaam (standaard $"+engine.WeightsEnv+", dan weights.json in de configmap)")
+	fs.Int64Var(&ef.seed, "seed", 0, "seed voor deal en engine; zelfde seed = zelfde run (0 = tijdsafhankelijk)")
+}
+
+func (ef *engineFlags) config() (engine.Config, error) {
+	if ef.players < 2 || ef.players > 4 {
+		return engine.Config{}, fmt.Errorf("ongeldig aantal spelers: %d (2-4)", ef.players)
+	}
+	cfg := engine.DefaultConfig(ef.players)
+	cfg.Iterations = ef.iters
+	cfg.MaxTime = ef.maxTime
+	cfg.NumWorkers = ef.workers
+	cfg.Seed = ef.seed
+	w, src, err := engine.ResolveWeights(ef.weights)
+	if err != nil {
+		return engine.Config{}, err
+	}
+	reportWeights(os.Stderr, src)
+	cfg.Weights = w
+	return cfg, nil
+}
+
+// resolveSeed geeft seed terug, of een tijdsafhankelijke seed als die 0 is.
+// De uitkomst wordt geprint zodat elke run herhaald kan worden.
+func resolveSeed(seed int64) int64 {
+	if seed == 0 {
+		return time.Now().UnixNano()
+	}
+	return seed
+}
+
+// readArg geeft de waarde van een flag terug, of de volledige stdin als de
+// waarde "-" is. Regels op stdin worden door spaties gescheiden.
+func readArg(v string) (string, error) {
+	if v != "-" {
+		return v, nil
+	}
+	data, err := io.ReadAll(bufio.NewReader(os.Stdin))
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(strings.Fields(string(data)), " "), nil
+}
+
+// parseMoveInput leest een zet in invoernotatie ("K K", "p", "-", "0/55")
+// voor speler pid. Een vervolg-zet na "/" wordt apart teruggegeven.
+func parseMoveInput(input string, pid int) (move game.Move, follow string, hasFollow bool, err error) {
+	mainInput, follow, hasFollow := strings.Cut(input, "/")
+	mainInput = strings.TrimSpace(mainInput)
+	switch strings.ToLower(mainInput) {
+	case "pass", "p", "-":
+		return game.PassMove(pid), strings.TrimSpace(follow), hasFollow, nil
+	}
+	parsed, err := cards.ParseCards(mainInput)
+	if err != nil {
+		return game.Move{}, "", false, err
+	}
+	return game.Move{PlayerID: pid, Cards: parsed}, strings.TrimSpace(follow), hasFollow, nil
+}
+
+// parseCounts leest een kommalijst met startkaarten per speler ("18,18,12").
+func parseCounts(s string, numPlayers int) ([]int, error) {
+	counts := make([]int, numPlayers)
+	for i := range counts {
+		counts[i] = 18
+	}
+	if strings.TrimSpace(s) == "" {
+		return counts, nil
+	}
+	parts := strings.Split(s, ",")
+	if len(parts) != numPlayers {
+		return nil, fmt.Errorf("verwacht %d kaartaantallen, kreeg %d", numPlayers, len(parts))
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("ongeldig kaartaantal: %q", p)
+		}
+		counts[i] = n
+	}
+	return counts, nil
+}
+
+func runBestMove(args []string) error {
+	fs := flag.NewFlagSet("bestmove", flag.ContinueOnError)
+	var ef engineFlags
+	ef.register(fs, 10000)
+	handStr := fs.String("hand", "", "jouw kaarten (\"-\" = lees van stdin)")
+	seat := fs.Int("seat", 1, "jouw spelernummer (1-based)")
+	start := fs.Int("start", 1, "spelernummer dat de partij begon")
+	countsStr := fs.String("counts", "", "startkaarten per speler, bv. 18,18 (standaard 18)")
+	movesStr := fs.String("moves", "", "gespeelde zetten als tokens, bv. \"88 p 33 0/55\" (\"-\" = stdin)")
+	verbose := fs.Bool("v", false, "toon alle kandidaat-zetten")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *handStr == "-" && *movesStr == "-" {
+		return fmt.Errorf("-hand en -moves kunnen niet allebei van stdin lezen")
+	}
+	engConfig, err := ef.config()
+	if err != nil {
+		return err
+	}
+	numPlayers := ef.players
+	myPlayer := *seat - 1
+	if myPlayer < 0 || myPlayer >= numPlayers {
+		return fmt.Errorf("ongeldig spelernummer: %d", *seat)
+	}
+	if *start < 1 || *start > numPlayers {
+		return fmt.Errorf("ongeldige startspeler: %d", *start)
+	}
+	counts, err := parseCounts(*countsStr, numPlayers)
+	if err != nil {
+		return err
+	}
+	handInput, err := readArg(*handStr)
+	if err != nil {
+		return err
+	}
+	myCards, err := cards.ParseCards(handInput)
+	if err != nil {
+		return err
+	}
+	if len(myCards) != counts[myPlayer] {
+		return fmt.Errorf("verwacht %d kaarten in je hand, kreeg %d", counts[myPlayer], len(myCards))
+	}
+	g, err := session.Open(session.Setup{
+		Players: numPlayers,
+		Seat:    myPlayer,
+		Start:   *start - 1,
+		Hand:    myCards,
+		Counts:  counts,
+	})
+	if err != nil {
+		return err
+	}
+
+	movesInput, err := readArg(*movesStr)
+	if err != nil {
+		return err
+	}
+	for i, token := range strings.Fields(movesInput) {
+		pid := g.GS.CurrentTurn
+		move, followStr, hasFollow, err := parseMoveInput(token, pid)
+		if err != nil {
+			return fmt.Errorf("zet %d (%q): %v", i+1, token, err)
+		}
+		if err := g.Apply(move); err != nil {
+			return fmt.Errorf("zet %d (%q): %v", i+1, token, err)
+		}
+		if hasFollow && !g.GS.GameOver && g.GS.CurrentTurn == pid {
+			follow, _, _, err := parseMoveInput(followStr, pid)
+			if err != nil {
+				return fmt.Errorf("zet %d (%q): vervolg-zet: %v", i+1, token, err)
+			}
+			if err := g.ApplyFollow(follow); err != nil {
+				return fmt.Errorf("zet %d (%q): vervolg-zet: %v", i+1, token, err)
+			}
+		}
+	}
+	gs, tracker := g.GS, g.Tracker
+	if gs.GameOver {
+		printRanking(gs)
+		return nil
+	}
+	if gs.CurrentTurn != myPlayer {
+		return fmt.Errorf("speler %d is aan de beurt, niet jij (speler %d)", gs.CurrentTurn+1, myPlayer+1)
+	}
+
+	eng := engine.NewEngine(engConfig)
+	best, eval := eng.BestMove(gs, tracker)
+	label := game.FormatMove(best)
+	if follow, ok := eng.BestFollow(gs, tracker, best); ok {
+		label = fmt.Sprintf("%s / %s", label, game.FormatMove(follow))
+	}
+	fmt.Printf("bestmove: %s\n", label)
+	fmt.Printf("score: %s\n", FormatScore(eval.Score))
+	fmt.Printf("visits: %d\n", eval.Visits)
+	fmt.Printf("seed: %d\n", eng.Seed())
+	if eval.ForcedWinDepth > 0 {
+		fmt.Printf("forcedwin: %d\n", eval.ForcedWinDepth)
+	}
+	if *verbose {
+		for _, d := range eval.Details {
+			fmt.Printf("  %-12s %6s  (%d visits)\n", game.FormatMove(d.Move), FormatScore(d.WinRate), d.Visits)
+		}
+	}
+	return nil
+}
+
+func runAnalyze(args []string) error {
+	fs := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	var ef engineFlags
+	ef.register(fs, 3000)
+	playersStr := fs.String("analyze", "", "te analyseren spelers, bv. 1,3 (leeg = alle)")
+	out := fs.String("o", "", "geannoteerde partij opslaan (.json, .jsonl, .csv, .md, .html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("gebruik: azen analyze [flags] <log>")
+	}
+	log, err := loadLog(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if log.NumPlayers < 2 || log.NumPlayers > 4 {
+		return fmt.Errorf("%s: ongeldig aantal spelers: %d", fs.Arg(0), log.NumPlayers)
+	}
+	ef.players = log.NumPlayers
+	engConfig, err := ef.config()
+	if err != nil {
+		return err
+	}
+	analyzePlayers, err := parsePlayerList(*playersStr, log.NumPlayers)
+	if err != nil {
+		return err
+	}
+	rec, err := printReplay(engConfig, log, analyzePlayers)
+	if err != nil || *out == "" {
+		return err
+	}
+	return saveRecord(*out, rec)
+}
+
+// loadLog leest een partij als tekstlog, ook uit een JSON-record (het eerste
+// als het bestand er meerdere bevat).
+func loadLog(path string) (*gameio.GameLog, error) {
+	rec, err := loadRecord(path)
+	if err != nil {
+		return nil, err
+	}
+	log, err := rec.ToLog()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return log, nil
+}
+
+func runConvert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	out := fs.String("o", "-", "uitvoer; .log/.txt = tekstlog, .jsonl = JSONL, .csv/.md/.html = export, anders JSON (- = stdout)")
+	format := fs.String("format", "", "formaat van de uitvoer (log, json, jsonl, csv, md, html); standaard volgens -o")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("gebruik: azen convert [-o uit] <partij>...")
+	}
+	var recs []*gameio.Record
+	for _, path := range fs.Args() {
+		r, err := gameio.LoadRecords(path)
+		if err != nil {
+			return err
+		}
+		recs = append(recs, r...)
+	}
+	f := *format
+	if f == "" {
+		f = recordFormat(*out)
+		if f == "json" && len(recs) > 1 {
+			f = "jsonl"
+		}
+	}
+	var w io.Writer = os.Stdout
+	if *out != "-" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	switch f {
+	case "log":
+		if len(recs) != 1 {
+			return fmt.Errorf("een tekstlog bevat één partij, kreeg er %d", len(recs))
+		}
+		log, err := recs[0].ToLog()
+		if err != nil {
+			return err
+		}
+		return gameio.WriteGame(w, log)
+	case "json":
+		if len(recs) != 1 {
+			return fmt.Errorf("JSON bevat één partij, kreeg er %d (gebruik jsonl)", len(recs))
+		}
+		return gameio.WriteRecord(w, recs[0])
+	case "jsonl":
+		return gameio.WriteRecords(w, recs)
+	case "csv", "md", "html":
+		if len(recs) != 1 {
+			return fmt.Errorf("een export bevat één partij, kreeg er %d", len(recs))
+		}
+		return writeExport(w, f, recs[0])
+	}
+	return fmt.Errorf("onbekend formaat %q (log, json, jsonl, csv, md, html)", f)
+}
+
+// parsePlayerList leest een kommalijst met 1-based spelernummers ("1,3")
+// als 0-based set. Een lege lijst geeft een lege set (= alle spelers).
+func parsePlayerList(s string, numPlayers int) (map[int]bool, error) {
+	players := map[int]bool{}
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 1 || n > numPlayers {
+			return nil, fmt.Errorf("ongeldig spelernummer: %q", part)
+		}
+		players[n-1] = true
+	}
+	return players, nil
+}
+
+func runSimulate(args []string) error {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	var ef engineFlags
+	ef.register(fs, 1000)
+	games := fs.Int("games", 1, "aantal partijen")
+	save := fs.String("save", "", "partijen opslaan (.json, .log; .jsonl voegt elke partij toe)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *save != "" && *games > 1 && !strings.HasSuffix(*save, ".jsonl") {
+		return fmt.Errorf("-save: gebruik een .jsonl-bestand voor meerdere partijen")
+	}
+	engConfig, err := ef.config()
+	if err != nil {
+		return err
+	}
+	// Partij g krijgt seed basis+g: "-seed <seed> -games 1" herhaalt ze.
+	base := resolveSeed(ef.seed)
+	wins := make([]int, ef.players)
+	for g := 0; g < *games; g++ {
+		if *games > 1 {
+			PrintHeader(fmt.Sprintf("Partij %d/%d", g+1, *games))
+		}
+		gs, rec := runSimulation(ef.players, engConfig, base+int64(g))
+		if *save != "" {
+			if err := saveRecord(*save, rec); err != nil {
+				return err
+			}
+		}
+		if gs.GameOver && len(gs.Ranking) > 0 {
+			wins[gs.Ranking[0]]++
+		}
+	}
+	if *games > 1 {
+		PrintSubHeader("Overwinningen")
+		for p, w := range wins {
+			fmt.Printf("Speler %d: %d/%d (%.1f%%)\n", p+1, w, *games, float64(w)*100/float64(*games))
+		}
+	}
+	return nil
+}
+
+func runTune(args []string) error {
+	fs := flag.NewFlagSet("tune", flag.ContinueOnError)
+	var ef engineFlags
+	ef.register(fs, 10000)
+	deals := fs.Int("deals", 400, "deals per kandidaat; elke deal met gewisselde plaatsen")
+	validate := fs.Int("validate", 0, "verse deals voor de eindcontrole tegen de startgewichten (0 = -deals)")
+	generations := fs.Int("generations", 35, "aantal generaties")
+	optimizer := fs.String("optimizer", tuner.Optimizers[0], "zoekmethode: "+strings.Join(tuner.Optimizers, ", "))
+	candidates := fs.Int("candidates", 0, "kandidaten per generatie (0 = standaard van de optimizer)")
+	baseline := fs.String("baseline", "best", "tegenstander van de kandidaten: best (beste tot nu toe) of start (startgewichten)")
+	jobs := fs.Int("jobs", 0, "partijen tegelijk, elk met eigen engines (0 = CPU's / workers)")
+	maxMoves := fs.Int("maxmoves", 0, "partij afbreken na zoveel zetten (0 = 1000)")
+	checkpoint := fs.String("checkpoint", "", "voortgangsbestand (standaard naast -weights, bv. weights.tune.json)")
+	resume := fs.Bool("resume", false, "verder vanaf het checkpoint")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if ef.players != 2 {
+		return fmt.Errorf("de tuner speelt enkel 2-speler partijen")
+	}
+	weightsPath, err := tuneWeightsPath(ef.weights)
+	if err != nil {
+		return err
+	}
+	// Veel partijen tegelijk met één worker elk benut de cores beter dan
+	// parallel zoeken binnen een zet; -workers kiest expliciet anders.
+	workersSet := false
+	fs.Visit(func(f *flag.Flag) { workersSet = workersSet || f.Name == "workers" })
+	if !workersSet {
+		ef.workers = 1
+	}
+	if *checkpoint == "" {
+		*checkpoint = checkpointPath(weightsPath)
+	}
+	if *deals < 1 || *generations < 1 || *candidates < 0 || ef.iters < 1 {
+		return fmt.Errorf("-deals, -generations en -iters moeten positief zijn")
+	}
+	b, err := tuner.ParseBaseline(*baseline)
+	if err != nil {
+		return err
+	}
+	if *jobs <= 0 {
+		*jobs = max(runtime.NumCPU()/max(ef.workers, 1), 1)
+	}
+	return runTuner(tunerParams{
+		deals:       *deals,
+		validate:    *validate,
+		generations: *generations,
+		optimizer:   *optimizer,
+		candidates:  *candidates,
+		iters:       ef.iters,
+		threads:     ef.workers,
+		jobs:        *jobs,
+		maxMoves:    *maxMoves,
+		baseline:    b,
+		weightsPath: weightsPath,
+		checkpoint:  *checkpoint,
+		resume:      *resume,
+		seed:        resolveSeed(ef.seed),
+	})
+}
+
+func runEngine(args []string) error {
+	fs := flag.NewFlagSet("engine", flag.ContinueOnError)
+	var ef engineFlags
+	ef.register(fs, 10000)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	engConfig, err := ef.config()
+	if err != nil {
+		return err
+	}
+	return protocol.NewSession(engConfig, os.Stdout).Run(os.Stdin)
+}
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	var ef engineFlags
+	ef.register(fs, 10000)
+	addr := fs.String("addr", "localhost:8080", "luisteradres; gebruik :8080 om de telefoon op het wifi-netwerk toe te laten")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	engConfig, err := ef.config()
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           server.New(engConfig),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Fprintf(os.Stderr, "AZEN server op http://%s/\n", *addr)
+	return srv.ListenAndServe()
+}
@@ -0,0 +1,137 @@
+// Command azen is de terminal-interface van de AZEN engine: zonder argumenten
+// een interactief menu, met een subcommando (bestmove, analyze, simulate,
+// tune) een niet-interactieve run voor scripts en pipelines.
+//
+// Het single-file Termux-bestand wordt vanuit de packages gegenereerd met
+// go run ./cmd/azen-bundle (zie README).
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/azen-engine/engine"
+)
+
+type settings struct {
+	numThreads int
+	seed       int64 // 0 = tijdsafhankelijk
+	weights    engine.Weights
+	weightsSrc engine.WeightsSource
+}
+
+// engineConfig is de standaardconfiguratie met de gekozen gewichten.
+func (s settings) engineConfig(numPlayers int) engine.Config {
+	c := engine.DefaultConfig(numPlayers)
+	c.Weights = s.weights
+	return c
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+	reader := NewReader()
+	cfg := settings{numThreads: 2}
+	var err error
+	if cfg.weights, cfg.weightsSrc, err = engine.ResolveWeights(""); err != nil {
+		fmt.Printf("⚠️  %v; standaardgewichten gebruikt.\n", err)
+	}
+	for {
+		PrintHeader("AZEN Engine v1.0")
+		fmt.Println("Welkom bij de AZEN kaartspel engine!")
+		reportWeights(os.Stdout, cfg.weightsSrc)
+		fmt.Println()
+		fmt.Printf("  [0] Instellingen  (threads: %d, seed: %s)\n", cfg.numThreads, seedLabel(cfg.seed))
+		fmt.Println("  [1] Spelen  - Engine suggereert zetten voor jou")
+		fmt.Println("  [2] Analyse - Bekijk een gespeeld spel opnieuw")
+		fmt.Println("  [3] Simuleer - Kijk hoe de engine tegen zichzelf speelt")
+		fmt.Println("  [4] Snelle analyse - Plak een volledige partij in één keer")
+		fmt.Println("  [5] Weight Tuner - Optimaliseer de AI gewichten (krachtige PC)")
+		if _, err := os.Stat(autosavePath); err == nil {
+			fmt.Println("  [6] Hervatten - Speel de onderbroken partij verder")
+		} else {
+			fmt.Println("  [6] Hervatten - Laad een opgeslagen partij")
+		}
+		fmt.Println()
+		modeStr := reader.ReadLine("Kies modus (0-6): ")
+		mode, _ := strconv.Atoi(modeStr)
+		switch mode {
+		case 0:
+			cfg = settingsMenu(reader, cfg)
+		case 1:
+			playMode(reader, cfg)
+			return
+		case 2:
+			analyzeMode(reader, cfg)
+			return
+		case 3:
+			simulateMode(reader, cfg)
+			return
+		case 4:
+			quickAnalyzeMode(reader, cfg)
+			return
+		case 5:
+			weightTunerMode(reader, cfg)
+			return
+		case 6:
+			resumeMode(reader, cfg)
+			return
+		default:
+			playMode(reader, cfg)
+			return
+		}
+	}
+}
+
+func settingsMenu(reader *Reader, cfg settings) settings {
+	PrintHeader("Instellingen")
+	fmt.Printf("Huidige threads: %d\n", cfg.numThreads)
+	fmt.Println()
+	fmt.Println("Threads bepalen hoeveel parallelle ISMCTS-bomen tegelijk draaien.")
+	fmt.Println("Meer threads = sterkere engine bij dezelfde iteraties.")
+	fmt.Println("  1  = sequentieel (origineel gedrag)")
+	fmt.Println("  2  = standaard (goed evenwicht, aanbevolen)")
+	fmt.Println("  4+ = sterker maar meer CPU-gebruik")
+	fmt.Println()
+	if n, err := reader.ReadInt(fmt.Sprintf("Aantal threads (huidige: %d): ", cfg.numThreads)); err == nil && n >= 1 {
+		if n > 64 {
+			n = 64
+		}
+		cfg.numThreads = n
+		fmt.Printf("✅ Threads ingesteld op %d.\n\n", n)
+	} else {
+		fmt.Printf("Ongewijzigd (%d threads).\n\n", cfg.numThreads)
+	}
+	fmt.Println("Met een vaste seed geeft de engine bij dezelfde invoer exact dezelfde")
+	fmt.Println("zetten (zolang er geen tijdslimiet is) en denkt ze niet vooruit tijdens")
+	fmt.Println("de zetten van tegenstanders. 0 = elke keer anders.")
+	fmt.Println()
+	if n, err := reader.ReadInt(fmt.Sprintf("Seed (huidige: %s): ", seedLabel(cfg.seed))); err == nil && n >= 0 {
+		cfg.seed = int64(n)
+		fmt.Printf("✅ Seed ingesteld op %s.\n\n", seedLabel(cfg.seed))
+	} else {
+		fmt.Printf("Ongewijzigd (seed %s).\n\n", seedLabel(cfg.seed))
+	}
+	if profiles, err := engine.Profiles(); err == nil && len(profiles) > 0 {
+		fmt.Printf("Profielen: %v\n", profiles)
+	}
+	if spec := reader.ReadLine(fmt.Sprintf("Gewichten (profielnaam of bestand, huidige: %s; leeg = ongewijzigd): ", cfg.weightsSrc)); spec != "" {
+		if w, src, err := engine.ResolveWeights(spec); err != nil {
+			fmt.Printf("❌ %v\n\n", err)
+		} else {
+			cfg.weights, cfg.weightsSrc = w, src
+			reportWeights(os.Stdout, src)
+			fmt.Println()
+		}
+	}
+	return cfg
+}
+
+func seedLabel(seed int64) string {
+	if seed == 0 {
+		return "willekeurig"
+	}
+	return strconv.FormatInt(seed, 10)
+}
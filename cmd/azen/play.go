@@ -0,0 +1,480 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/engine"
+	"github.com/azen-engine/game"
+	"github.com/azen-engine/knowledge"
+	"github.com/azen-engine/session"
+)
+
+func handleGok(input string, g *session.Game) (bool, string) {
+	lower := strings.ToLower(strings.TrimSpace(input))
+	if !strings.HasPrefix(lower, "gok") {
+		return false, ""
+	}
+	tracker, myPlayer, numPlayers := g.Tracker, g.MyPlayer, g.NumPlayers
+	rest := strings.TrimSpace(input[3:])
+	if rest == "" {
+		var sb strings.Builder
+		sb.WriteString("🔍 Huidige vermoedens:\n")
+		any := false
+		for p := 0; p < numPlayers; p++ {
+			if p == myPlayer {
+				continue
+			}
+			susp := tracker.Suspicions[p]
+			excl := tracker.Exclusions[p]
+			if len(susp) > 0 {
+				sb.WriteString(fmt.Sprintf("  Speler %d heeft:      %s\n", p+1, cards.CardsToString(susp)))
+				any = true
+			}
+			if len(excl) > 0 {
+				var parts []string
+				for r, cnt := range excl {
+					for i := 0; i < cnt; i++ {
+						parts = append(parts, (cards.Card{Rank: r}).RankStr())
+					}
+				}
+				sb.WriteString(fmt.Sprintf("  Speler %d heeft NIET: %s\n", p+1, strings.Join(parts, " ")))
+				any = true
+			}
+		}
+		if !any {
+			sb.WriteString("  (geen vermoedens ingevoerd)\n")
+		}
+		return true, sb.String()
+	}
+	parts := strings.SplitN(rest, ":", 2)
+	if len(parts) != 2 {
+		return true, "⚠️  Formaat: gok 2:KK  of  gok 2:clear  of  gok"
+	}
+	playerNum, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || playerNum < 1 || playerNum > numPlayers {
+		return true, fmt.Sprintf("⚠️  Ongeldig spelernummer: %s", parts[0])
+	}
+	targetID := playerNum - 1
+	if targetID == myPlayer {
+		return true, "⚠️  Je hoeft geen vermoeden in te voeren voor jezelf."
+	}
+	arg := strings.TrimSpace(parts[1])
+	if strings.ToLower(arg) == "clear" {
+		g.ClearSuspicions(targetID)
+		g.ClearExclusions(targetID)
+		return true, fmt.Sprintf("🔍 Alle vermoedens voor Speler %d gewist.", playerNum)
+	}
+	isNegative := strings.HasPrefix(arg, "-")
+	if isNegative {
+		arg = arg[1:]
+	}
+	parsed, err := cards.ParseCards(arg)
+	if err != nil {
+		return true, fmt.Sprintf("⚠️  Kaarten niet herkend: %v", err)
+	}
+	if isNegative {
+		msg := fmt.Sprintf("🚫 Speler %d heeft NIET: %s", playerNum, cards.CardsToString(parsed))
+		if err := g.Exclude(targetID, parsed); err != nil {
+			msg += fmt.Sprintf("\n   ⚠️  %v", err)
+		}
+		return true, msg
+	}
+	err = g.Suspect(targetID, parsed)
+	msg := fmt.Sprintf("🔍 Gok Speler %d heeft: %s  (totaal vermoeden: %s)",
+		playerNum, cards.CardsToString(parsed), cards.CardsToString(g.Tracker.Suspicions[targetID]))
+	if err != nil {
+		msg += fmt.Sprintf("\n   ⚠️  %v", err)
+	}
+	return true, msg
+}
+
+// printGameStatus toont de spelstatus met vermoedens voor tegenstanders.
+// Vervangt gs.StatusString() in speelmodus zodat gok-info zichtbaar is.
+func printGameStatus(gs *game.GameState, tracker *knowledge.KnowledgeTracker, myPlayer int) {
+	fmt.Printf("=== AZEN (%d spelers) ===\n", gs.NumPlayers)
+	medals := []string{"🥇", "🥈", "🥉", "4e"}
+	for i := range gs.Hands {
+		marker := "  "
+		switch {
+		case gs.Finished[i]:
+			rank := gs.PlayerRank(i)
+			if rank >= 0 && rank < len(medals) {
+				marker = medals[rank] + " "
+			} else {
+				marker = "✓  "
+			}
+		case i == gs.CurrentTurn:
+			marker = "▶  "
+		}
+
+		count := gs.Hands[i].Count()
+		var handDisplay string
+
+		if i == myPlayer {
+			h := gs.Hands[i].Clone()
+			h.Sort()
+			handDisplay = h.String()
+		} else {
+			susp := tracker.Suspicions[i]
+			var parts []string
+			for _, c := range susp {
+				parts = append(parts, c.RankStr())
+			}
+			remaining := count - len(susp)
+			if remaining < 0 {
+				remaining = 0
+			}
+			for j := 0; j < remaining; j++ {
+				parts = append(parts, "?")
+			}
+			handDisplay = strings.Join(parts, " ")
+		}
+
+		fmt.Printf("%sP%d [%2d kaarten]: %s\n", marker, i+1, count, handDisplay)
+	}
+
+	if gs.Round.IsOpen {
+		fmt.Println("Ronde: OPEN (speel alles)")
+	} else {
+		rankStr := (cards.Card{Rank: gs.Round.TableRank}).RankStr()
+		fmt.Printf("Ronde: %dx kaarten, rank %s verslaan\n", gs.Round.Count, rankStr)
+	}
+	if gs.GameOver && len(gs.Ranking) > 0 {
+		fmt.Printf("🏆 Speler %d WINT!\n", gs.Ranking[0]+1)
+	}
+	fmt.Println()
+}
+
+func playMode(reader *Reader, cfg settings) {
+	PrintHeader("Speel Modus")
+	numPlayers := 2
+	if n, err := reader.ReadInt("Aantal spelers (2/3/4): "); err == nil && n >= 2 && n <= 4 {
+		numPlayers = n
+	}
+	myPlayer := 0
+	if p, err := reader.ReadInt("Jouw spelernummer (1-" + strconv.Itoa(numPlayers) + "): "); err == nil && p >= 1 && p <= numPlayers {
+		myPlayer = p - 1
+	}
+	var myCards []cards.Card
+	cardCounts := make([]int, numPlayers)
+	for i := 0; i < numPlayers; i++ {
+		cardCounts[i] = 18
+		if n, err := reader.ReadInt(fmt.Sprintf("Aantal startkaarten voor Speler %d (standaard 18): ", i+1)); err == nil && n > 0 {
+			cardCounts[i] = n
+		}
+		if i == myPlayer {
+			fmt.Println("\nVoer jouw kaarten in (komma, spatie of aaneengesloten):")
+			fmt.Println("  Voorbeeld: KK3XJ19Q25  of  K,K,3,X,J  of  K K 3 X J")
+			fmt.Println("  Typ 'help' voor uitleg.")
+			fmt.Println()
+			for {
+				input := reader.ReadLine("Jouw kaarten: ")
+				if strings.ToLower(input) == "help" {
+					PrintHelp()
+					continue
+				}
+				parsed, err := cards.ParseCards(input)
+				if err != nil {
+					fmt.Printf("Fout: %v\n", err)
+					continue
+				}
+				if len(parsed) != cardCounts[i] {
+					fmt.Printf("Verwacht %d kaarten, kreeg %d. Probeer opnieuw.\n", cardCounts[i], len(parsed))
+					continue
+				}
+				myCards = parsed
+				break
+			}
+			fmt.Println("\n\nJouw hand:")
+			PrintCards(cards.NewHand(myCards))
+		}
+	}
+	if numPlayers == 2 {
+		fmt.Println("\nMet 2 spelers zijn 18 kaarten niet in spel (engine houdt hiermee rekening).")
+	}
+	iters := 10000
+	if n, err := reader.ReadInt("Engine-iteraties per zet (standaard 10000, meer = nauwkeuriger maar trager): "); err == nil && n > 0 {
+		iters = n
+	}
+	engConfig := cfg.engineConfig(numPlayers)
+	engConfig.Iterations = iters
+	engConfig.MaxTime = 0
+	engConfig.NumWorkers = cfg.numThreads
+	engConfig.Seed = cfg.seed
+	start := 0
+	startStr := reader.ReadLine("Wie begint? (spelernummer of 'ik'): ")
+	if strings.ToLower(startStr) == "ik" || strings.ToLower(startStr) == "me" {
+		start = myPlayer
+	} else if p, err := strconv.Atoi(startStr); err == nil && p >= 1 && p <= numPlayers {
+		start = p - 1
+	}
+	g, err := session.Open(session.Setup{
+		Players: numPlayers,
+		Seat:    myPlayer,
+		Start:   start,
+		Hand:    myCards,
+		Counts:  cardCounts,
+	})
+	if err != nil {
+		fmt.Printf("Fout: %v\n", err)
+		return
+	}
+	playGame(reader, g, engConfig)
+}
+
+// resumeMode hervat een opgeslagen partij, standaard de autosave.
+func resumeMode(reader *Reader, cfg settings) {
+	PrintHeader("Partij Hervatten")
+	path := reader.ReadLine(fmt.Sprintf("Bestand (leeg = %s): ", autosavePath))
+	if path == "" {
+		path = autosavePath
+	}
+	engConfig := cfg.engineConfig(2)
+	g, saved, err := loadPlay(path, &engConfig)
+	if err != nil {
+		fmt.Printf("Fout: %v\n", err)
+		return
+	}
+	fmt.Printf("📂 Partij van %s geladen (%d spelers, jij bent Speler %d, %d zetten gespeeld).\n",
+		saved.Format("2006-01-02 15:04"), g.NumPlayers, g.MyPlayer+1, len(g.GS.History))
+	playGame(reader, g, engConfig)
+}
+
+// playGame speelt een partij met engine-hulp. Na elke zet wordt ze in
+// autosavePath bewaard; 'save' en 'load' schrijven of lezen een ander bestand.
+func playGame(reader *Reader, g *session.Game, engConfig engine.Config) {
+	myPlayer := g.MyPlayer
+	eng := engine.NewEngine(engConfig)
+	fmt.Printf("Engine-seed: %d (zet deze seed in Instellingen om de suggesties te herhalen)\n", eng.Seed())
+	autosave := func() {
+		if err := savePlay(autosavePath, g, engConfig); err != nil {
+			fmt.Printf("⚠️  Autosave mislukt: %v\n", err)
+		}
+	}
+	// fileCommand verwerkt 'save [bestand]' en 'load [bestand]'. loaded meldt
+	// dat g vervangen is en het bord opnieuw getoond moet worden.
+	fileCommand := func(input string) (handled, loaded bool) {
+		cmd, path, _ := strings.Cut(strings.TrimSpace(input), " ")
+		path = strings.TrimSpace(path)
+		if path == "" {
+			path = autosavePath
+		}
+		switch strings.ToLower(cmd) {
+		case "save":
+			if err := savePlay(path, g, engConfig); err != nil {
+				fmt.Printf("⚠️  Opslaan mislukt: %v\n", err)
+			} else {
+				fmt.Printf("💾 Partij opgeslagen in %s\n", path)
+			}
+			return true, false
+		case "load":
+			ng, _, err := loadPlay(path, &engConfig)
+			if err != nil {
+				fmt.Printf("⚠️  Laden mislukt: %v\n", err)
+				return true, false
+			}
+			g, myPlayer = ng, ng.MyPlayer
+			eng = engine.NewEngine(engConfig)
+			fmt.Printf("📂 Partij geladen uit %s\n\n", path)
+			return true, true
+		}
+		return false, false
+	}
+	fmt.Printf("\n🎮 Spel gestart! Typ 'help' voor commando's, 'gok 2:KK' voor vermoedens, 'rethink' om opnieuw te berekenen, 'undo'/'redo' om zetten terug te nemen of opnieuw te doen.\n")
+	fmt.Printf("💾 Na elke zet bewaard in %s; 'save [bestand]' / 'load [bestand]' om zelf op te slaan of te laden.\n\n", autosavePath)
+	for !g.GS.GameOver {
+		autosave()
+		printGameStatus(g.GS, g.Tracker, myPlayer)
+		if g.GS.CurrentTurn == myPlayer {
+			PrintSubHeader("Jouw beurt")
+			PrintCards(g.GS.Hands[myPlayer])
+			fmt.Println("\n🤔 Engine denkt na...")
+			bestMove, eval := eng.BestMove(g.GS, g.Tracker)
+			printInherited(eval)
+			if eval.ForcedWinDepth > 0 {
+				fmt.Printf("\n♟️  Gedwongen winst in %d beurt(en)!\n", eval.ForcedWinDepth)
+				fmt.Printf("💡 Engine suggereert: %s\n\n", game.FormatMove(bestMove))
+			} else {
+				fmt.Printf("\n💡 Engine suggereert: %s (winst: %s)\n\n",
+					game.FormatMove(bestMove), FormatScore(eval.Score))
+			}
+			for {
+				input := reader.ReadLine("Jouw zet (of 'hint'/'rethink'/'help'/'hand'/'status'/'moves'/'gok'/'undo'/'redo'): ")
+				lower := strings.ToLower(input)
+				if lower == "undo" {
+					undoMove(g)
+					break
+				}
+				if lower == "redo" {
+					redoMove(g)
+					break
+				}
+				if handled, loaded := fileCommand(input); handled {
+					if loaded {
+						break
+					}
+					continue
+				}
+				switch lower {
+				case "help":
+					PrintHelp()
+					continue
+				case "hand":
+					PrintCards(g.GS.Hands[myPlayer])
+					continue
+				case "status":
+					printGameStatus(g.GS, g.Tracker, myPlayer)
+					continue
+				case "rethink":
+					fmt.Println("\n🤔 Engine herdenkt de situatie...")
+					bestMove, eval = eng.BestMove(g.GS, g.Tracker)
+					printInherited(eval)
+					if eval.ForcedWinDepth > 0 {
+						fmt.Printf("\n♟️  Gedwongen winst in %d beurt(en)!\n", eval.ForcedWinDepth)
+						fmt.Printf("💡 Nieuwe suggestie: %s\n\n", game.FormatMove(bestMove))
+					} else {
+						fmt.Printf("\n💡 Nieuwe suggestie: %s (winst: %s)\n\n",
+							game.FormatMove(bestMove), FormatScore(eval.Score))
+					}
+					continue
+				case "hint":
+					fmt.Printf("💡 Suggestie: %s (winst: %s)\n",
+						game.FormatMove(bestMove), FormatScore(eval.Score))
+					continue
+				case "moves":
+					PrintMoveOptions(g.GS.GetLegalMoves(), 20)
+					continue
+				case "quit", "exit":
+					fmt.Println("Tot ziens!")
+					os.Exit(0)
+				}
+				if handled, msg := handleGok(input, g); handled {
+					fmt.Println(msg)
+					autosave()
+					continue
+				}
+				if enterMove(g, input, myPlayer, "✅ Gespeeld") {
+					break
+				}
+			}
+		} else {
+			playerNum := g.GS.CurrentTurn + 1
+			oppID := g.GS.CurrentTurn
+			PrintSubHeader(fmt.Sprintf("Beurt van Speler %d", playerNum))
+			for {
+				// Terwijl we op de zet van de tegenstander wachten, denkt de
+				// engine vooruit. Met een vaste seed niet: dan hingen de
+				// suggesties af van hoe lang het invoeren duurt.
+				stopPonder := func() {}
+				if engConfig.Seed == 0 {
+					stopPonder = eng.Ponder(g.GS, g.Tracker)
+				}
+				input := reader.ReadLine(fmt.Sprintf("Zet van Speler %d (of '-' voor pas, 'gok' voor vermoeden, 'undo'/'redo'): ", playerNum))
+				stopPonder()
+				lower := strings.ToLower(strings.TrimSpace(input))
+				if lower == "help" {
+					PrintHelp()
+					continue
+				}
+				if lower == "quit" || lower == "exit" {
+					fmt.Println("Tot ziens!")
+					os.Exit(0)
+				}
+				if lower == "undo" {
+					undoMove(g)
+					break
+				}
+				if lower == "redo" {
+					redoMove(g)
+					break
+				}
+				if handled, loaded := fileCommand(input); handled {
+					if loaded {
+						break
+					}
+					continue
+				}
+				if handled, msg := handleGok(input, g); handled {
+					fmt.Println(msg)
+					autosave()
+					continue
+				}
+				if enterMove(g, input, oppID, fmt.Sprintf("📝 Speler %d speelde", playerNum)) {
+					break
+				}
+			}
+		}
+	}
+	// Een afgelopen partij valt niet meer te hervatten.
+	os.Remove(autosavePath)
+	PrintHeader("Spel Voorbij!")
+	printRanking(g.GS)
+	rec := g.Record()
+	rec.Meta.Seed = eng.Seed()
+	rec.Meta.Iterations = engConfig.Iterations
+	offerSave(reader, rec)
+}
+
+// printInherited meldt hoeveel van de zoektocht uit de vorige beurt kwam.
+func printInherited(eval engine.MoveEval) {
+	if eval.Inherited > 0 {
+		fmt.Printf("♻️  %d bezoeken overgenomen uit de vorige zoektocht\n", eval.Inherited)
+	}
+}
+
+// enterMove leest een zet van speler pid ("K K", "p", "0 / 5 5") en past hem
+// toe. Bij een ongeldige zet blijft de partij ongewijzigd en wordt false
+// teruggegeven, zodat de invoer opnieuw gevraagd kan worden.
+func enterMove(g *session.Game, input string, pid int, label string) bool {
+	move, followStr, hasFollow, err := parseMoveInput(input, pid)
+	if err != nil {
+		fmt.Printf("Fout: %v\n", err)
+		return false
+	}
+	var follow game.Move
+	if hasFollow {
+		if follow, _, _, err = parseMoveInput(followStr, pid); err != nil {
+			fmt.Printf("Fout in vervolg-zet: %v\n", err)
+			return false
+		}
+	}
+	if err := g.Apply(move); err != nil {
+		fmt.Printf("Ongeldige zet: %v\n", err)
+		return false
+	}
+	if !hasFollow || g.GS.GameOver || g.GS.CurrentTurn != pid {
+		fmt.Printf("%s: %s\n\n", label, game.FormatMove(move))
+		return true
+	}
+	if err := g.ApplyFollow(follow); err != nil {
+		// De hoofdzet blijft staan: de vervolg-zet wordt apart opnieuw gevraagd.
+		fmt.Printf("%s: %s\n⚠️  Ongeldige vervolg-zet: %v\n\n", label, game.FormatMove(move), err)
+		return true
+	}
+	fmt.Printf("%s: %s / %s\n\n", label, game.FormatMove(move), game.FormatMove(follow))
+	return true
+}
+
+// undoMove neemt de laatst ingevoerde zet terug.
+func undoMove(g *session.Game) {
+	undone, err := g.Undo()
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+		return
+	}
+	fmt.Printf("↩️  Teruggenomen: Speler %d: %s\n\n", undone[0].PlayerID+1, movesLabel(undone))
+}
+
+// redoMove voert de laatst teruggenomen zet opnieuw in.
+func redoMove(g *session.Game) {
+	redone, err := g.Redo()
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+		return
+	}
+	fmt.Printf("↪️  Opnieuw: Speler %d: %s\n\n", redone[0].PlayerID+1, movesLabel(redone))
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/azen-engine/engine"
+	"github.com/azen-engine/gameio"
+	"github.com/azen-engine/session"
+)
+
+// autosavePath is waar de speelmodus na elke zet de partij bewaart, zodat
+// een afgebroken Termux-sessie hervat kan worden: autosave.json naast de
+// gewichten (engine.WeightsDir), of in de huidige map als die onbekend is.
+var autosavePath = func() string {
+	dir, err := engine.WeightsDir()
+	if err != nil {
+		return "azen-autosave.json"
+	}
+	return filepath.Join(dir, "autosave.json")
+}()
+
+// playSaveVersion is de versie van het bestandsformaat van playSave.
+const playSaveVersion = 1
+
+// playSave is een opgeslagen speelpartij: de sessie (beginsituatie, zetten
+// en vermoedens) en de engine-instellingen.
+type playSave struct {
+	Version int              `json:"version"`
+	Saved   time.Time        `json:"saved"`
+	Engine  engineSettings   `json:"engine"`
+	Game    session.Snapshot `json:"game"`
+}
+
+// engineSettings zijn de instellingen van engine.Config die bij een partij
+// horen; de gewichten komen uit het actieve gewichtenbestand. Seed is de
+// seed die de gebruiker koos: 0 blijft 0, zodat een hervatte partij zonder
+// vaste seed ook daarna geen vaste seed heeft.
+type engineSettings struct {
+	Iterations   int     `json:"iterations"`
+	MaxTimeMs    int64   `json:"max_time_ms,omitempty"`
+	ExploreConst float64 `json:"explore_const"`
+	Workers      int     `json:"workers"`
+	Seed         int64   `json:"seed"`
+	Omniscient   bool    `json:"omniscient,omitempty"`
+	Backprop     string  `json:"backprop"`
+	Algorithm    string  `json:"algorithm"`
+	ReuseTree    bool    `json:"reuse_tree"`
+}
+
+// newEngineSettings neemt de instellingen over uit cfg.
+func newEngineSettings(cfg engine.Config) engineSettings {
+	return engineSettings{
+		Iterations:   cfg.Iterations,
+		MaxTimeMs:    cfg.MaxTime.Milliseconds(),
+		ExploreConst: cfg.ExploreConst,
+		Workers:      cfg.NumWorkers,
+		Seed:         cfg.Seed,
+		Omniscient:   cfg.OmniscientMode,
+		Backprop:     cfg.Backprop.String(),
+		Algorithm:    cfg.Algorithm.String(),
+		ReuseTree:    cfg.ReuseTree,
+	}
+}
+
+// apply zet de instellingen in cfg.
+func (es engineSettings) apply(cfg *engine.Config) error {
+	backprop, err := engine.ParseBackprop(es.Backprop)
+	if err != nil {
+		return err
+	}
+	algorithm, err := engine.ParseAlgorithm(es.Algorithm)
+	if err != nil {
+		return err
+	}
+	cfg.Iterations = es.Iterations
+	cfg.MaxTime = time.Duration(es.MaxTimeMs) * time.Millisecond
+	cfg.ExploreConst = es.ExploreConst
+	cfg.NumWorkers = es.Workers
+	cfg.Seed = es.Seed
+	cfg.OmniscientMode = es.Omniscient
+	cfg.Backprop = backprop
+	cfg.Algorithm = algorithm
+	cfg.ReuseTree = es.ReuseTree
+	return nil
+}
+
+// savePlay schrijft de partij naar path. Er wordt eerst naar een tijdelijk
+// bestand geschreven, zodat een onderbreking de vorige save niet vernielt.
+func savePlay(path string, g *session.Game, engConfig engine.Config) error {
+	ps := playSave{
+		Version: playSaveVersion,
+		Saved:   time.Now(),
+		Engine:  newEngineSettings(engConfig),
+		Game:    g.Snapshot(),
+	}
+	data, err := json.MarshalIndent(ps, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// loadPlay leest een partij die savePlay schreef en zet de engine-instellingen
+// in engConfig. De gewichten in engConfig blijven behouden.
+func loadPlay(path string, engConfig *engine.Config) (*session.Game, time.Time, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	var ps playSave
+	if err := json.Unmarshal(data, &ps); err != nil {
+		return nil, time.Time{}, fmt.Errorf("%s: %v", path, err)
+	}
+	if ps.Version != playSaveVersion {
+		return nil, time.Time{}, fmt.Errorf("%s: onbekende versie %d", path, ps.Version)
+	}
+	g, err := session.Restore(ps.Game)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("%s: %v", path, err)
+	}
+	cfg := engine.DefaultConfig(g.NumPlayers)
+	cfg.Weights = engConfig.Weights
+	if err := ps.Engine.apply(&cfg); err != nil {
+		return nil, time.Time{}, fmt.Errorf("%s: %v", path, err)
+	}
+	*engConfig = cfg
+	return g, ps.Saved, nil
+}
+
+// offerSave vraagt of een afgelopen partij bewaard moet worden.
+func offerSave(reader *Reader, rec *gameio.Record) {
+	path := reader.ReadLine("Partij opslaan? (bestand .json, .jsonl = toevoegen, .log = tekstlog, .csv/.md/.html = export; leeg = niet): ")
+	if path == "" {
+		return
+	}
+	if err := saveRecord(path, rec); err != nil {
+		fmt.Printf("⚠️  Opslaan mislukt: %v\n", err)
+		return
+	}
+	fmt.Printf("💾 Partij opgeslagen in %s\n", path)
+}
+
+// recordFormat is het formaat dat bij de extensie van path hoort: log, jsonl,
+// csv, md, html of anders json.
+func recordFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".log", ".txt":
+		return "log"
+	case ".jsonl":
+		return "jsonl"
+	case ".csv":
+		return "csv"
+	case ".md":
+		return "md"
+	case ".html", ".htm":
+		return "html"
+	}
+	return "json"
+}
+
+// saveRecord schrijft rec naar path in het formaat van de extensie: .log of
+// .txt als tekstlog, .jsonl achteraan toegevoegd, .csv, .md en .html als
+// export met de beoordelingen, anders als JSON.
+func saveRecord(path string, rec *gameio.Record) error {
+	switch f := recordFormat(path); f {
+	case "log":
+		log, err := rec.ToLog()
+		if err != nil {
+			return fmt.Errorf("%v (gebruik .json)", err)
+		}
+		return gameio.SaveGame(path, log)
+	case "jsonl":
+		return gameio.AppendRecord(path, rec)
+	case "csv", "md", "html":
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		err = writeExport(file, f, rec)
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+		return err
+	}
+	return gameio.SaveRecord(path, rec)
+}
+
+// writeExport schrijft rec als csv, md of html.
+func writeExport(w io.Writer, format string, rec *gameio.Record) error {
+	switch format {
+	case "csv":
+		return gameio.WriteCSV(w, rec)
+	case "md":
+		return gameio.WriteMarkdown(w, rec)
+	case "html":
+		return gameio.WriteHTML(w, rec)
+	}
+	return fmt.Errorf("onbekend exportformaat %q", format)
+}
+
+// loadRecord leest de eerste partij uit path (tekstlog, JSON of JSONL).
+func loadRecord(path string) (*gameio.Record, error) {
+	recs, err := gameio.LoadRecords(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(recs) == 0 {
+		return nil, fmt.Errorf("%s: geen partij", path)
+	}
+	return recs[0], nil
+}
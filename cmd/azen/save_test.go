@@ -0,0 +1,81 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/engine"
+	"github.com/azen-engine/session"
+)
+
+func TestSavePlayRoundTrip(t *testing.T) {
+	hand, _ := cards.ParseCards("3 3 4 5 5 7 8 9 X X J Q K K 1 2 2 0")
+	// Speler 0 heeft weinig kaarten, zodat zijn pass een passrecord geeft.
+	g, err := session.Open(session.Setup{Players: 2, Seat: 1, Start: 0, Hand: hand, Counts: []int{10, 18}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"4", "5", ""} {
+		m, err := session.ParseMove(g.GS.CurrentTurn, s, s == "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := g.Apply(m); err != nil {
+			t.Fatalf("%q: %v", s, err)
+		}
+	}
+	q, _ := cards.ParseCards("Q")
+	j, _ := cards.ParseCards("J J")
+	if err := g.Suspect(0, q); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Exclude(0, j); err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Tracker.PassRecords[0]) == 0 {
+		t.Fatal("geen passrecord na de pass van speler 0")
+	}
+
+	for _, seed := range []int64{0, 42} {
+		cfg := engine.DefaultConfig(2)
+		cfg.Iterations = 1234
+		cfg.MaxTime = 1500 * time.Millisecond
+		cfg.ExploreConst = 0.9
+		cfg.NumWorkers = 3
+		cfg.Seed = seed
+		cfg.OmniscientMode = true
+		cfg.Backprop = engine.BackpropMaxN
+		cfg.Algorithm = engine.AlgorithmMO
+		cfg.ReuseTree = false
+		path := filepath.Join(t.TempDir(), "autosave.json")
+		if err := savePlay(path, g, cfg); err != nil {
+			t.Fatal(err)
+		}
+
+		var got engine.Config
+		got.Weights = cfg.Weights
+		loaded, _, err := loadPlay(path, &got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, cfg) {
+			t.Errorf("seed %d: engine-instellingen\n%+v\nverwacht\n%+v", seed, got, cfg)
+		}
+		if loaded.MyPlayer != 1 || loaded.GS.CurrentTurn != g.GS.CurrentTurn {
+			t.Errorf("seed %d: speler %d, aan zet %d", seed, loaded.MyPlayer, loaded.GS.CurrentTurn)
+		}
+		for name, pair := range map[string][2]any{
+			"Suspicions":  {loaded.Tracker.Suspicions, g.Tracker.Suspicions},
+			"Exclusions":  {loaded.Tracker.Exclusions, g.Tracker.Exclusions},
+			"PassRecords": {loaded.Tracker.PassRecords, g.Tracker.PassRecords},
+			"HandCounts":  {loaded.Tracker.HandCounts, g.Tracker.HandCounts},
+		} {
+			if !reflect.DeepEqual(pair[0], pair[1]) {
+				t.Errorf("seed %d: %s %v, verwacht %v", seed, name, pair[0], pair[1])
+			}
+		}
+	}
+}
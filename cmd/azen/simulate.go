@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/azen-engine/engine"
+	"github.com/azen-engine/game"
+	"github.com/azen-engine/gameio"
+	"github.com/azen-engine/knowledge"
+)
+
+func simulateMode(reader *Reader, cfg settings) {
+	PrintHeader("Simulatie Modus")
+	fmt.Println("Kijk hoe de engine tegen zichzelf speelt!")
+	fmt.Println()
+	numPlayers := 2
+	if n, err := reader.ReadInt("Aantal spelers (2/3/4): "); err == nil && n >= 2 && n <= 4 {
+		numPlayers = n
+	}
+	sims := 1000
+	if s, err := reader.ReadInt("Engine-simulaties per zet (standaard 1000): "); err == nil && s > 0 {
+		sims = s
+	}
+	engConfig := cfg.engineConfig(numPlayers)
+	engConfig.Iterations = sims
+	engConfig.NumWorkers = cfg.numThreads
+	_, rec := runSimulation(numPlayers, engConfig, resolveSeed(cfg.seed))
+	offerSave(reader, rec)
+}
+
+// runSimulation laat engConfig tegen zichzelf spelen en print het verloop.
+// seed bepaalt zowel de deal als de engines: dezelfde seed (en geen
+// tijdslimiet) speelt exact dezelfde partij. Geeft de eindstand en de
+// partij als record (met de score van elke zet) terug.
+func runSimulation(numPlayers int, engConfig engine.Config, seed int64) (*game.GameState, *gameio.Record) {
+	rng := rand.New(rand.NewSource(seed))
+	gs := game.NewGame(numPlayers, rng, 0)
+	rec := gameio.NewRecord(gs)
+	rec.Meta.Source = "simulate"
+	rec.Meta.Seed = seed
+	rec.Meta.Iterations = engConfig.Iterations
+	fmt.Printf("\nSeed: %d\n", seed)
+	fmt.Println("\nStarthanden:")
+	for i := 0; i < numPlayers; i++ {
+		fmt.Printf("Speler %d: %s\n", i+1, gs.Hands[i])
+	}
+	fmt.Println()
+	trackers := make([]*knowledge.KnowledgeTracker, numPlayers)
+	engines := make([]*engine.Engine, numPlayers)
+	for i := 0; i < numPlayers; i++ {
+		trackers[i] = knowledge.NewKnowledgeTracker(numPlayers, i, gs.Hands[i], gs.DeadCards)
+		engConfig.Seed = rng.Int63()
+		engines[i] = engine.NewEngine(engConfig)
+	}
+	prevFinished := 0
+	moveNum := 0
+	for !gs.GameOver {
+		moveNum++
+		playerID := gs.CurrentTurn
+		eng := engines[playerID]
+		bestMove, eval := eng.BestMove(gs, trackers[playerID])
+		fmt.Printf("Zet %d | Speler %d: %s (score: %.1f%%) | Kaarten:",
+			moveNum, playerID+1, game.FormatMove(bestMove), eval.Score*100)
+		for i := 0; i < numPlayers; i++ {
+			if gs.Finished[i] {
+				fmt.Printf(" P%d:✓", i+1)
+			} else {
+				fmt.Printf(" P%d:%d", i+1, gs.Hands[i].Count())
+			}
+		}
+		fmt.Println()
+		if bestMove.IsPass {
+			for i := 0; i < numPlayers; i++ {
+				trackers[i].RecordPass(bestMove.PlayerID, gs.Round)
+			}
+		}
+		gs.ApplyMove(bestMove)
+		for i := 0; i < numPlayers; i++ {
+			trackers[i].RecordMove(bestMove)
+		}
+		if t := rec.AddMove(bestMove); t.Follow == "" {
+			t.Eval = &gameio.Eval{
+				Best:           game.FormatMove(bestMove),
+				BestScore:      eval.Score,
+				Score:          eval.Score,
+				Visits:         eval.Visits,
+				ForcedWinDepth: eval.ForcedWinDepth,
+			}
+		}
+		nowFinished := len(gs.Ranking)
+		if nowFinished > prevFinished {
+			medals := []string{"🥇", "🥈", "🥉", "4e"}
+			for pos := prevFinished; pos < nowFinished && !gs.GameOver; pos++ {
+				m := ""
+				if pos < len(medals) {
+					m = medals[pos]
+				}
+				fmt.Printf("  %s Speler %d eindigt op plaats %d!\n",
+					m, gs.Ranking[pos]+1, pos+1)
+			}
+			prevFinished = nowFinished
+		}
+		if moveNum > 600 {
+			fmt.Println("Spel overschreed 600 zetten, gestopt.")
+			break
+		}
+	}
+	if gs.GameOver {
+		PrintHeader("Spel Voorbij!")
+		printRanking(gs)
+	}
+	rec.Finish(gs)
+	return gs, rec
+}
+
+func printRanking(gs *game.GameState) {
+	medals := []string{"🥇", "🥈", "🥉", "4️⃣ "}
+	labels := []string{"wint!", "wordt 2e", "wordt 3e", "wordt 4e (verliezer)"}
+	for i, pid := range gs.Ranking {
+		m := ""
+		if i < len(medals) {
+			m = medals[i]
+		}
+		lbl := ""
+		if i < len(labels) {
+			lbl = labels[i]
+		}
+		if i == len(gs.Ranking)-1 && gs.NumPlayers > 2 {
+			lbl = "verliest 💀"
+		}
+		fmt.Printf("%s Speler %d %s\n", m, pid+1, lbl)
+	}
+}
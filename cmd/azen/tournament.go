@@ -0,0 +1,203 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azen-engine/engine"
+	"github.com/azen-engine/tournament"
+)
+
+func runTournament(args []string) error {
+	fs := flag.NewFlagSet("tournament", flag.ContinueOnError)
+	var ef engineFlags
+	ef.register(fs, 2000)
+	deals := fs.Int("deals", 20, "aantal deals; elke deal speelt elke opstelling in elke zitplaatsrotatie")
+	jobs := fs.Int("jobs", 0, "partijen tegelijk (0 = CPU's / workers)")
+	maxMoves := fs.Int("maxmoves", 0, "partij afbreken na zoveel zetten (0 = 1000)")
+	perDeal := fs.Bool("perdeal", false, "punten per deal tonen")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Gebruik: azen tournament [flags] <deelnemer> <deelnemer>...")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Deelnemer: naam[:optie=waarde,...], bv. basis:iters=2000 sterk:iters=8000,explore=1.0")
+		fmt.Fprintln(fs.Output(), "Opties: policy=engine|greedy|random, iters, time, explore, workers, weights, omniscient, backprop=auto|maxn|paranoid, algo=det|so|mo, reuse=true|false (standaard true)")
+		fmt.Fprintln(fs.Output(), "Ontbrekende opties nemen de waarde van de flags hieronder.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		return fmt.Errorf("minstens 2 deelnemers nodig (zie azen tournament -h)")
+	}
+	base, err := ef.config()
+	if err != nil {
+		return err
+	}
+	var entrants []tournament.Entrant
+	for _, spec := range fs.Args() {
+		e, err := parseEntrant(spec, base)
+		if err != nil {
+			return err
+		}
+		entrants = append(entrants, e)
+	}
+	if *jobs <= 0 {
+		*jobs = max(runtime.NumCPU()/max(ef.workers, 1), 1)
+	}
+	seed := resolveSeed(ef.seed)
+	t := &tournament.Tournament{
+		Entrants: entrants,
+		Players:  ef.players,
+		Deals:    *deals,
+		Seed:     seed,
+		Jobs:     *jobs,
+		MaxMoves: *maxMoves,
+	}
+	start := time.Now()
+	t.Progress = func(done, total int) {
+		fmt.Fprintf(os.Stderr, "\rPartij %d/%d (%s)", done, total, time.Since(start).Round(time.Second))
+		if done == total {
+			fmt.Fprintln(os.Stderr)
+		}
+	}
+	fmt.Printf("Toernooi: %d deelnemers, %d spelers per tafel, %d deals, seed %d\n",
+		len(entrants), ef.players, *deals, seed)
+	res, err := t.Run()
+	if err != nil {
+		return err
+	}
+	printStandings(res)
+	printDuplicate(res, *perDeal)
+	return nil
+}
+
+// parseEntrant leest een deelnemer "naam[:optie=waarde,...]". Engine-opties
+// vertrekken van base.
+func parseEntrant(spec string, base engine.Config) (tournament.Entrant, error) {
+	name, opts, _ := strings.Cut(spec, ":")
+	if name == "" {
+		return tournament.Entrant{}, fmt.Errorf("deelnemer %q: naam ontbreekt", spec)
+	}
+	cfg := base
+	policy := "engine"
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "" {
+			continue
+		}
+		key, val, hasVal := strings.Cut(opt, "=")
+		var err error
+		switch key {
+		case "policy":
+			policy = val
+		case "iters":
+			cfg.Iterations, err = strconv.Atoi(val)
+		case "time":
+			cfg.MaxTime, err = time.ParseDuration(val)
+		case "explore":
+			cfg.ExploreConst, err = strconv.ParseFloat(val, 64)
+		case "workers":
+			cfg.NumWorkers, err = strconv.Atoi(val)
+		case "weights":
+			cfg.Weights, _, err = engine.ResolveWeights(val)
+		case "algo":
+			cfg.Algorithm, err = engine.ParseAlgorithm(val)
+		case "backprop":
+			cfg.Backprop, err = engine.ParseBackprop(val)
+		case "omniscient":
+			cfg.OmniscientMode = true
+			if hasVal {
+				cfg.OmniscientMode, err = strconv.ParseBool(val)
+			}
+		case "reuse":
+			cfg.ReuseTree = true
+			if hasVal {
+				cfg.ReuseTree, err = strconv.ParseBool(val)
+			}
+		default:
+			return tournament.Entrant{}, fmt.Errorf("deelnemer %s: onbekende optie %q", name, key)
+		}
+		if err != nil {
+			return tournament.Entrant{}, fmt.Errorf("deelnemer %s: %s: %v", name, key, err)
+		}
+	}
+	switch policy {
+	case "engine":
+		return tournament.EngineEntrant(name, cfg), nil
+	case "greedy":
+		return tournament.GreedyEntrant(name), nil
+	case "random":
+		return tournament.RandomEntrant(name), nil
+	}
+	return tournament.Entrant{}, fmt.Errorf("deelnemer %s: onbekende policy %q (engine, greedy, random)", name, policy)
+}
+
+// printStandings toont de stand: winstpercentage en gemiddelde plaats met
+// 95%-intervallen, en de Elo-rating.
+func printStandings(res *tournament.Results) {
+	aborted := 0
+	for _, m := range res.Matches {
+		if m.Ranking == nil {
+			aborted++
+		}
+	}
+	PrintSubHeader(fmt.Sprintf("Stand na %d partijen", len(res.Matches)))
+	fmt.Printf("%-14s %8s %7s %15s %8s %7s %6s\n", "Deelnemer", "Partijen", "Winst", "95%-interval", "Plaats", "±95%", "Elo")
+	for _, s := range res.Standings() {
+		fmt.Printf("%-14s %8d %6.1f%% %6.1f%%–%5.1f%% %8.2f %7.2f %6.0f\n",
+			s.Name, s.Games, s.WinRate*100, s.WinLow*100, s.WinHigh*100, s.AvgPlace, s.PlaceErr, s.Elo)
+	}
+	if aborted > 0 {
+		fmt.Printf("\n⚠️  %d partijen afgebroken (te veel zetten), niet meegeteld.\n", aborted)
+	}
+}
+
+// printDuplicate toont de duplicate-uitslag: per deelnemer het gemiddelde
+// verschil met het dealgemiddelde, en per paar deelnemers de vergelijking
+// deal per deal.
+func printDuplicate(res *tournament.Results, perDeal bool) {
+	PrintSubHeader(fmt.Sprintf("Duplicate over %d deals (punten: 1 = winst, 0 = laatste)", len(res.Deals)))
+	if perDeal {
+		fmt.Printf("%-6s", "Deal")
+		for _, name := range res.Entrants {
+			fmt.Printf(" %10.10s", name)
+		}
+		fmt.Println()
+		for d, row := range res.DealPoints() {
+			fmt.Printf("%-6d", d+1)
+			for _, p := range row {
+				fmt.Printf(" %10.2f", p)
+			}
+			fmt.Println()
+		}
+		fmt.Println()
+	}
+	fmt.Printf("%-14s %6s %7s %8s %7s\n", "Deelnemer", "Deals", "Punten", "+/-deal", "±95%")
+	for _, s := range res.Duplicate() {
+		fmt.Printf("%-14s %6d %7.3f %+8.3f %7.3f\n", s.Name, s.Deals, s.Score, s.Plus, s.Err)
+	}
+	fmt.Println()
+	for a := range res.Entrants {
+		for b := a + 1; b < len(res.Entrants); b++ {
+			h := res.HeadToHead(a, b)
+			if h.Deals == 0 {
+				continue
+			}
+			verdict := "niet significant"
+			switch {
+			case h.Significant():
+				verdict = "significant"
+			case h.Deals < tournament.MinSignificantDeals:
+				verdict = fmt.Sprintf("te weinig deals; minstens %d", tournament.MinSignificantDeals)
+			}
+			fmt.Printf("%s – %s: %d deals (%d beter, %d gelijk, %d slechter), verschil %+.3f ± %.3f (%s)\n",
+				h.A, h.B, h.Deals, h.Wins, h.Ties, h.Losses, h.Diff, h.Err, verdict)
+		}
+	}
+}
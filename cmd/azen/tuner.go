@@ -0,0 +1,184 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/azen-engine/engine"
+	"github.com/azen-engine/tuner"
+)
+
+// Weight tuner v3 — kandidaten van een optimizer (CMA-ES, SPSA of mutatie)
+// tegen een basislijn op duplicate-deals.
+func weightTunerMode(reader *Reader, cfg settings) {
+	PrintHeader("Weight Tuner v3 — Duplicate Edition")
+	fmt.Println("Elke kandidaat speelt tegen de beste gewichten tot nu toe, op dezelfde deals")
+	fmt.Println("met gewisselde plaatsen. Enkel een significante verbetering wordt bewaard.")
+	fmt.Println()
+
+	p := tunerParams{
+		threads: cfg.numThreads,
+		jobs:    max(runtime.NumCPU()/max(cfg.numThreads, 1), 1),
+		seed:    resolveSeed(cfg.seed),
+	}
+	var err error
+	if p.weightsPath, err = tuneWeightsPath(cfg.weightsSrc.Path); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+	p.checkpoint = checkpointPath(p.weightsPath)
+	if _, err := os.Stat(p.checkpoint); err == nil {
+		p.resume = reader.ReadYesNo("Onderbroken tuning-run gevonden. Verder gaan?")
+	}
+	if !p.resume {
+		p.optimizer = reader.ReadLine("Optimizer (" + strings.Join(tuner.Optimizers, "/") + ", leeg = " + tuner.Optimizers[0] + "): ")
+		if p.optimizer == "" {
+			p.optimizer = tuner.Optimizers[0]
+		}
+	}
+	p.deals, _ = reader.ReadInt("Deals per kandidaat (aanbevolen 300-600, elke deal 2 partijen): ")
+	if p.deals < 50 {
+		p.deals = 400
+	}
+	p.generations, _ = reader.ReadInt("Aantal generaties (aanbevolen 25-60): ")
+	if p.generations < 10 {
+		p.generations = 35
+	}
+	p.iters, _ = reader.ReadInt("Iteraties per zet (aanbevolen 8000-15000): ")
+	if p.iters < 2000 {
+		p.iters = 10000
+	}
+
+	if err := runTuner(p); err != nil {
+		fmt.Printf("❌ %v\n", err)
+	}
+}
+
+// tunerParams zijn de instellingen van één tuning-run.
+type tunerParams struct {
+	deals       int // deals per kandidaat
+	validate    int // deals voor de eindcontrole (0 = deals)
+	generations int
+	optimizer   string
+	candidates  int // kandidaten per generatie (0 = standaard van de optimizer)
+	iters       int // engine-iteraties per zet
+	threads     int
+	jobs        int
+	maxMoves    int
+	baseline    tuner.Baseline
+	weightsPath string // startgewichten; een verbetering wordt hier opgeslagen
+	checkpoint  string // voortgang na elke generatie
+	resume      bool   // verder vanaf checkpoint
+	seed        int64  // bepaalt mutaties, deals en engines
+}
+
+// checkpointPath is het standaard checkpoint naast het gewichtenbestand.
+func checkpointPath(weightsPath string) string {
+	return strings.TrimSuffix(weightsPath, ".json") + ".tune.json"
+}
+
+func runTuner(p tunerParams) error {
+	// Bestaat het bestand nog niet, dan start de tuner van de standaard-
+	// gewichten en maakt hij het aan bij een verbetering.
+	start := engine.DefaultWeights()
+	if _, err := os.Stat(p.weightsPath); !errors.Is(err, fs.ErrNotExist) {
+		w, src, err := engine.ResolveWeights(p.weightsPath)
+		if err != nil {
+			return fmt.Errorf("gewichten laden: %v", err)
+		}
+		for _, warn := range src.Warnings {
+			fmt.Printf("⚠️  %s: %s\n", p.weightsPath, warn)
+		}
+		start = w
+	}
+	var cp *tuner.Checkpoint
+	var err error
+	if p.resume {
+		if cp, err = tuner.LoadCheckpoint(p.checkpoint); err != nil {
+			return err
+		}
+		p.seed = cp.Seed
+		p.optimizer = cp.Optimizer
+		if p.baseline, err = tuner.ParseBaseline(cp.Baseline); err != nil {
+			return err
+		}
+	}
+
+	config := engine.DefaultConfig(2)
+	config.Iterations = p.iters
+	config.NumWorkers = p.threads
+	config.Weights = start
+	config.OmniscientMode = true
+	t := &tuner.Tuner{
+		Engine:         config,
+		Optimizer:      p.optimizer,
+		Generations:    p.generations,
+		Candidates:     p.candidates,
+		Deals:          p.deals,
+		Validate:       p.validate,
+		Baseline:       p.baseline,
+		Seed:           p.seed,
+		Jobs:           p.jobs,
+		MaxMoves:       p.maxMoves,
+		CheckpointFile: p.checkpoint,
+		Out:            os.Stdout,
+		Progress:       tuneProgress(p.generations),
+	}
+
+	fmt.Printf("\n🚀 Start TUNER v3\n")
+	fmt.Printf("Optimizer: %s | Deals: %d | Generaties: %d | Iters: %d | Basislijn: %s | Seed: %d\n",
+		p.optimizer, p.deals, p.generations, p.iters, p.baseline, p.seed)
+	fmt.Printf("Parallel: %d partijen tegelijk × %d workers per zoektocht\n", max(p.jobs, 1), p.threads)
+	fmt.Printf("Gewichten: %s | Checkpoint: %s\n\n", p.weightsPath, p.checkpoint)
+
+	o, err := t.Run(cp)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n🏆 TUNING AFGEROND!\n")
+	if v := o.Validation; v.Deals > 0 {
+		fmt.Printf("Controle: %+.3f ± %.3f punten per deal (%d beter, %d gelijk, %d slechter)\n",
+			v.Diff, v.Err, v.Wins, v.Ties, v.Losses)
+	}
+	if !o.Improved() {
+		fmt.Printf("Niet significant beter: %s blijft ongewijzigd.\n", p.weightsPath)
+		if o.Best != o.Start {
+			fmt.Printf("De beste kandidaat staat in %s.\n", p.checkpoint)
+		}
+		return nil
+	}
+	if err := engine.SaveWeights(o.Best, p.weightsPath); err != nil {
+		return err
+	}
+	fmt.Printf("Gewichten opgeslagen in %s\n", p.weightsPath)
+	fmt.Println("Je kunt nu direct met de verbeterde AI spelen.")
+	return nil
+}
+
+// tuneProgress toont de voortgang van een tuning-run op stderr, met een
+// schatting van de resterende tijd uit de gemiddelde duur per partij sinds
+// de start (met alle parallelle partijen meegerekend).
+func tuneProgress(generations int) func(gen, done, total int) {
+	start := time.Now()
+	played := 0
+	return func(gen, done, total int) {
+		played++
+		label := "Controle"
+		remaining := total - done
+		if gen > 0 {
+			label = fmt.Sprintf("Generatie %d/%d", gen, generations)
+			remaining += (generations - gen) * total
+		}
+		eta := time.Since(start) / time.Duration(played) * time.Duration(remaining)
+		fmt.Fprintf(os.Stderr, "\r%s: partij %d/%d | nog ~%s (klaar rond %s)   ",
+			label, done, total, eta.Round(time.Second), time.Now().Add(eta).Format("15:04"))
+		if done == total {
+			fmt.Fprintln(os.Stderr)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/azen-engine/engine"
+)
+
+// reportWeights meldt welke gewichten actief zijn, met de waarschuwingen
+// over het bestand.
+func reportWeights(w io.Writer, src engine.WeightsSource) {
+	fmt.Fprintf(w, "Gewichten: %s\n", src)
+	for _, warn := range src.Warnings {
+		fmt.Fprintf(w, "⚠️  %s\n", warn)
+	}
+}
+
+// tuneWeightsPath is het gewichtenbestand dat de tuner leest en bij een
+// verbetering overschrijft: het bestand dat spec kiest, of weights.json in
+// de configmap als er nog geen is. Zo gebruikt de engine na het tunen
+// meteen de nieuwe gewichten.
+func tuneWeightsPath(spec string) (string, error) {
+	src, err := engine.WeightsPath(spec)
+	if err != nil {
+		return "", err
+	}
+	if src.Path != "" {
+		return src.Path, nil
+	}
+	dir, err := engine.WeightsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "weights.json"), nil
+}
+
+func runWeights(args []string) error {
+	fs := flag.NewFlagSet("weights", flag.ContinueOnError)
+	spec := fs.String("weights", "", "gewichtenbestand of profielnaam (standaard zoals de andere commando's)")
+	save := fs.String("save", "", "bewaar de gewichten als dit profiel")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	// Een ongeldige profielnaam valt op voordat er iets getoond wordt.
+	var savePath string
+	if *save != "" {
+		var err error
+		if savePath, err = engine.ProfilePath(*save); err != nil {
+			return err
+		}
+	}
+	w, src, err := engine.ResolveWeights(*spec)
+	if err != nil {
+		return err
+	}
+	reportWeights(os.Stdout, src)
+	if dir, err := engine.WeightsDir(); err == nil {
+		fmt.Printf("Configmap: %s\n", dir)
+	}
+	fmt.Println()
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+
+	if savePath != "" {
+		if err := engine.SaveWeights(w, savePath); err != nil {
+			return err
+		}
+		fmt.Printf("\nProfiel %s bewaard in %s\n", *save, savePath)
+	}
+	profiles, err := engine.Profiles()
+	if err != nil {
+		return err
+	}
+	if len(profiles) > 0 {
+		fmt.Println()
+		fmt.Println("Profielen (gebruik -weights <naam>):")
+		for _, name := range profiles {
+			fmt.Printf("  %s\n", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,754 @@
+// Package engine bevat de IS-MCTS zoekmachine, de heuristieken voor de
+// rollouts en de tunebare gewichten.
+package engine
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/game"
+	"github.com/azen-engine/knowledge"
+)
+
+type Config struct {
+	Iterations     int
+	MaxTime        time.Duration
+	ExploreConst   float64
+	NumPlayers     int
+	Weights        Weights
+	OmniscientMode bool
+	NumWorkers     int
+	// Seed bepaalt alle toevalskeuzes van de zoektocht (determinisatie,
+	// rollouts, worker-seeds). 0 = tijdsafhankelijk. Met een vaste seed en
+	// zonder MaxTime geeft de eerste BestMove van een nieuwe engine telkens
+	// exact hetzelfde resultaat. Met ReuseTree hangen volgende aanroepen ook
+	// af van de bewaarde boom; die is enkel reproduceerbaar bij dezelfde
+	// reeks aanroepen en zonder Ponder.
+	Seed int64
+	// Backprop bepaalt hoe uitslagen door de boom teruglopen (standaard
+	// paranoid bij 2 spelers en max^n bij meer).
+	Backprop Backprop
+	// Algorithm is de zoekmethode (standaard de gedeterminiseerde boom).
+	Algorithm Algorithm
+	// ReuseTree bewaart de zoekboom tussen BestMove-aanroepen: is de
+	// volgende stand een vervolg van de vorige voor dezelfde speler, dan
+	// zoekt de engine verder in de deelboom van de gespeelde zetten.
+	ReuseTree bool
+}
+
+// Algorithm is de variant van IS-MCTS die BestMove gebruikt.
+type Algorithm int
+
+const (
+	// AlgorithmDeterminized: één boom met concrete zetten; elke iteratie
+	// loopt door de boom alsof de getrokken determinisatie de echte stand is.
+	AlgorithmDeterminized Algorithm = iota
+	// AlgorithmSO: single-observer IS-MCTS met informatieset-knopen en
+	// beschikbaarheidstellingen (zie ismcts.go).
+	AlgorithmSO
+	// AlgorithmMO: multiple-observer IS-MCTS, één boom per speler.
+	AlgorithmMO
+)
+
+func (a Algorithm) String() string {
+	switch a {
+	case AlgorithmSO:
+		return "so"
+	case AlgorithmMO:
+		return "mo"
+	}
+	return "det"
+}
+
+// ParseAlgorithm leest "det", "so" of "mo".
+func ParseAlgorithm(s string) (Algorithm, error) {
+	switch s {
+	case "det":
+		return AlgorithmDeterminized, nil
+	case "so":
+		return AlgorithmSO, nil
+	case "mo":
+		return AlgorithmMO, nil
+	}
+	return 0, fmt.Errorf("onbekend algoritme %q (det, so, mo)", s)
+}
+
+// Backprop is de manier waarop de zoektocht de tegenstanders modelleert.
+type Backprop int
+
+const (
+	// BackpropAuto: paranoid bij 2 spelers, max^n bij meer. Met één
+	// tegenstander is zijn belang het omgekeerde van het onze; max^n zou er
+	// enkel een niet-constante-som evaluatie per speler aan toevoegen.
+	BackpropAuto Backprop = iota
+	// BackpropMaxN: elke knoop houdt een beloning per speler bij en de
+	// speler aan zet kiest voor zijn eigen beloning.
+	BackpropMaxN
+	// BackpropParanoid: alle tegenstanders spelen samen tegen ons; de boom
+	// kent enkel onze beloning en tegenstanders minimaliseren die.
+	BackpropParanoid
+)
+
+func (b Backprop) String() string {
+	switch b {
+	case BackpropMaxN:
+		return "maxn"
+	case BackpropParanoid:
+		return "paranoid"
+	}
+	return "auto"
+}
+
+// ParseBackprop leest "auto", "maxn" of "paranoid".
+func ParseBackprop(s string) (Backprop, error) {
+	switch s {
+	case "auto":
+		return BackpropAuto, nil
+	case "maxn":
+		return BackpropMaxN, nil
+	case "paranoid":
+		return BackpropParanoid, nil
+	}
+	return 0, fmt.Errorf("onbekende backprop %q (auto, maxn, paranoid)", s)
+}
+
+// maxN meldt of de zoektocht in een partij van numPlayers spelers een
+// beloning per speler bijhoudt.
+func (c Config) maxN(numPlayers int) bool {
+	switch c.Backprop {
+	case BackpropMaxN:
+		return true
+	case BackpropParanoid:
+		return false
+	}
+	return numPlayers > 2
+}
+
+// DefaultConfig geeft de standaardinstellingen met de ingebouwde gewichten.
+// Het leest geen bestanden; ResolveWeights kiest een gewichtenbestand.
+func DefaultConfig(numPlayers int) Config {
+	return Config{
+		Iterations:   10000,
+		MaxTime:      0,
+		ExploreConst: 1.4,
+		NumPlayers:   numPlayers,
+		Weights:      DefaultWeights(),
+		NumWorkers:   2,
+		ReuseTree:    true,
+	}
+}
+
+type Engine struct {
+	Config Config
+	// OnProgress wordt tijdens BestMove periodiek aangeroepen met de huidige
+	// beste zet en de statistieken per wortelzet (nil = geen voortgang).
+	// Search geeft dezelfde tussenstanden op een kanaal.
+	OnProgress func(best game.Move, eval MoveEval)
+
+	rng     *rand.Rand
+	seed    int64
+	stopped atomic.Bool
+	tree    *searchTree // boom van de vorige BestMove (zie ReuseTree)
+}
+
+// progressInterval is hoe vaak OnProgress minstens aangeroepen wordt.
+const progressInterval = 250 * time.Millisecond
+
+func NewEngine(cfg Config) *Engine {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Engine{
+		Config: cfg,
+		rng:    rand.New(rand.NewSource(seed)),
+		seed:   seed,
+	}
+}
+
+// Seed geeft de seed waarmee de engine gestart is; met Config.Seed gelijk
+// aan deze waarde herhaalt een nieuwe engine dezelfde reeks zoektochten.
+func (e *Engine) Seed() int64 { return e.seed }
+
+type MoveEval struct {
+	Score          float64
+	Visits         int
+	Details        []MoveDetail
+	ForcedWinDepth int // >0 als gedwongen winst: aantal eigen beurten tot winst
+	// Inherited is het aantal bezoeken van de wortelzetten dat uit de
+	// vorige zoektocht overgenomen werd (zie Config.ReuseTree).
+	Inherited int
+}
+
+func (me MoveEval) String() string {
+	if me.Inherited > 0 {
+		return fmt.Sprintf("Win%%: %.1f%% (%d visits, %d geërfd)", me.Score*100, me.Visits, me.Inherited)
+	}
+	return fmt.Sprintf("Win%%: %.1f%% (%d visits)", me.Score*100, me.Visits)
+}
+
+type MoveDetail struct {
+	Move    game.Move
+	WinRate float64
+	Visits  int
+}
+
+func (md MoveDetail) String() string {
+	return fmt.Sprintf("  %s -> %.1f%% (%d visits)", md.Move, md.WinRate*100, md.Visits)
+}
+
+type workerResult struct {
+	visits map[string]int
+	wins   map[string]float64
+	moves  map[string]game.Move
+}
+
+func (e *Engine) runWorker(ctx context.Context, gs *game.GameState, kt *knowledge.KnowledgeTracker, iters int, seed int64, rootFiltered []game.Move, trees []*mctsNode, report func(workerResult)) workerResult {
+	workerCfg := e.Config
+	workerCfg.NumWorkers = 1
+	worker := &Engine{Config: workerCfg, rng: rand.New(rand.NewSource(seed)), seed: seed}
+	myID := gs.CurrentTurn
+	root := trees[myID]
+	hasDeadline := worker.Config.MaxTime > 0
+	deadline := time.Now().Add(worker.Config.MaxTime)
+	lastReport := time.Now()
+	for iter := 0; iter < iters; iter++ {
+		if (hasDeadline && time.Now().After(deadline)) || e.stopped.Load() || ctx.Err() != nil {
+			break
+		}
+		detGS := worker.determinize(gs, kt)
+		if detGS == nil {
+			continue
+		}
+		worker.iterate(trees, detGS, myID, rootFiltered)
+		if report != nil && time.Since(lastReport) >= progressInterval {
+			report(rootResult(root))
+			lastReport = time.Now()
+		}
+	}
+	return rootResult(root)
+}
+
+// rootResult vat de kinderen van de wortel samen per zet.
+func rootResult(root *mctsNode) workerResult {
+	res := workerResult{
+		visits: map[string]int{},
+		wins:   map[string]float64{},
+		moves:  map[string]game.Move{},
+	}
+	for _, ch := range root.children {
+		k := mkey(ch.move)
+		res.visits[k] += ch.visits
+		res.wins[k] += ch.wins
+		res.moves[k] = ch.move
+	}
+	return res
+}
+
+// Stop breekt een lopende BestMove af. De zoektocht geeft dan de beste zet
+// terug op basis van de iteraties die al gedaan zijn.
+func (e *Engine) Stop() { e.stopped.Store(true) }
+
+func (e *Engine) BestMove(gs *game.GameState, kt *knowledge.KnowledgeTracker) (game.Move, MoveEval) {
+	return e.BestMoveContext(context.Background(), gs, kt)
+}
+
+// BestMoveContext is BestMove die stopt zodra ctx afloopt: alle workers
+// houden dan op en de zoektocht geeft de beste zet van de iteraties tot dan.
+// Is er nog geen iteratie gedaan, dan volgt een heuristische legale zet
+// (zie fallbackMove), nooit een pass zonder bezoeken als er iets te spelen
+// valt.
+func (e *Engine) BestMoveContext(ctx context.Context, gs *game.GameState, kt *knowledge.KnowledgeTracker) (game.Move, MoveEval) {
+	return e.bestMove(ctx, gs, kt, e.OnProgress)
+}
+
+// SearchUpdate is een tussenstand van Search.
+type SearchUpdate struct {
+	Best game.Move
+	Eval MoveEval
+	Done bool // de zoektocht is klaar; dit is het resultaat van BestMove
+}
+
+// Search zoekt op de achtergrond zoals BestMoveContext en stuurt periodiek
+// de huidige beste zet met de statistieken per wortelzet op het kanaal. Het
+// laatste bericht heeft Done; daarna wordt het kanaal gesloten. Het kanaal
+// bewaart enkel de nieuwste stand: een trage lezer mist tussenstanden, maar
+// nooit het eindresultaat, en houdt de zoektocht niet op.
+func (e *Engine) Search(ctx context.Context, gs *game.GameState, kt *knowledge.KnowledgeTracker) <-chan SearchUpdate {
+	ch := make(chan SearchUpdate, 1)
+	send := func(u SearchUpdate) {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- u
+	}
+	go func() {
+		defer close(ch)
+		best, eval := e.bestMove(ctx, gs, kt, func(best game.Move, eval MoveEval) {
+			if e.OnProgress != nil {
+				e.OnProgress(best, eval)
+			}
+			send(SearchUpdate{Best: best, Eval: eval})
+		})
+		send(SearchUpdate{Best: best, Eval: eval, Done: true})
+	}()
+	return ch
+}
+
+// bestMove is de zoektocht achter BestMoveContext en Search; progress (nil
+// = geen) krijgt de tussenstanden.
+func (e *Engine) bestMove(ctx context.Context, gs *game.GameState, kt *knowledge.KnowledgeTracker, progress func(game.Move, MoveEval)) (game.Move, MoveEval) {
+	defer e.stopped.Store(false)
+	if win, depth := findImmediateWin(gs, e.Config.OmniscientMode); win != nil {
+		return *win, MoveEval{Score: 1.0, Visits: 1, ForcedWinDepth: depth}
+	}
+	// Filter gedomineerde wild-zetten zodat MCTS iteraties efficiënter benut worden
+	rootFiltered := filterDominatedMoves(gs.GetLegalMoves(), gs.Round)
+	numWorkers := max(e.Config.NumWorkers, 1)
+	trees, inherited := e.reuseTrees(gs, kt, gs.CurrentTurn, rootFiltered, numWorkers)
+	defer e.keepTree(gs, gs.CurrentTurn, trees)
+	if progress != nil {
+		report := progress
+		progress = func(m game.Move, eval MoveEval) {
+			eval.Inherited = inherited
+			report(m, eval)
+		}
+	}
+	if numWorkers == 1 {
+		m, eval := e.bestMoveSingle(ctx, gs, kt, rootFiltered, trees[0], progress)
+		if len(eval.Details) == 0 {
+			m = fallbackMove(gs, rootFiltered)
+		}
+		eval.Inherited = inherited
+		return m, eval
+	}
+	itersPerWorker := e.Config.Iterations / numWorkers
+	if itersPerWorker < 1 {
+		itersPerWorker = 1
+	}
+	seeds := make([]int64, numWorkers)
+	for i := range seeds {
+		seeds[i] = e.rng.Int63()
+	}
+	results := make([]workerResult, numWorkers)
+	// Voortgang: elke worker meldt periodiek zijn wortelstatistieken; de
+	// laatste stand van alle workers samen gaat naar progress.
+	var report func(idx int) func(workerResult)
+	if progress != nil {
+		var mu sync.Mutex
+		latest := make([]workerResult, numWorkers)
+		report = func(idx int) func(workerResult) {
+			return func(r workerResult) {
+				mu.Lock()
+				defer mu.Unlock()
+				latest[idx] = r
+				if m, eval := e.combineResults(gs, latest); eval.Visits > 0 {
+					progress(m, eval)
+				}
+			}
+		}
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			iters := itersPerWorker
+			if idx == numWorkers-1 {
+				iters = e.Config.Iterations - itersPerWorker*(numWorkers-1)
+			}
+			var rep func(workerResult)
+			if report != nil {
+				rep = report(idx)
+			}
+			results[idx] = e.runWorker(ctx, gs, kt, iters, seeds[idx], rootFiltered, trees[idx], rep)
+		}(w)
+	}
+	wg.Wait()
+	m, eval := e.combineResults(gs, results)
+	if len(eval.Details) == 0 {
+		m = fallbackMove(gs, rootFiltered)
+	}
+	eval.Inherited = inherited
+	return m, eval
+}
+
+// fallbackMove kiest een zet als de zoektocht geen enkele iteratie deed
+// (bv. meteen afgebroken): de beste niet-pass zet volgens QuickEvaluateMove,
+// anders de eerste zet van moves. Pass enkel als er niets anders kan.
+func fallbackMove(gs *game.GameState, moves []game.Move) game.Move {
+	var best game.Move
+	bestScore, found := 0.0, false
+	for _, m := range moves {
+		if m.IsPass {
+			continue
+		}
+		if score := QuickEvaluateMove(gs, m).Score; !found || score > bestScore {
+			best, bestScore, found = m, score, true
+		}
+	}
+	if found {
+		return best
+	}
+	if len(moves) > 0 {
+		return moves[0]
+	}
+	return game.PassMove(gs.CurrentTurn)
+}
+
+// combineResults telt de wortelstatistieken van alle workers samen en kiest
+// de beste zet (inclusief de pass-override).
+func (e *Engine) combineResults(gs *game.GameState, results []workerResult) (game.Move, MoveEval) {
+	totalVisits := map[string]int{}
+	totalWins := map[string]float64{}
+	moveMap := map[string]game.Move{}
+	for _, r := range results {
+		for k, v := range r.visits {
+			totalVisits[k] += v
+		}
+		for k, w := range r.wins {
+			totalWins[k] += w
+		}
+		for k, m := range r.moves {
+			moveMap[k] = m
+		}
+	}
+	if len(moveMap) == 0 {
+		return game.PassMove(gs.CurrentTurn), MoveEval{}
+	}
+	// Vaste volgorde: bij gelijke stand mag de map-volgorde de keuze niet
+	// bepalen, anders is een run met vaste seed niet reproduceerbaar.
+	keys := make([]string, 0, len(moveMap))
+	for k := range moveMap {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	bestKey := ""
+	bestVisits := -1
+	for _, k := range keys {
+		v := totalVisits[k]
+		if v > bestVisits {
+			bestVisits = v
+			bestKey = k
+		}
+	}
+	// OmniscientMode (analyse): selecteer op WINRATE in plaats van visits.
+	// In OmniscientMode bouwen alle workers dezelfde boom, maar PASS kan door
+	// boom-asymmetrie (bredere subtree) meer visits krijgen ondanks lagere winrate.
+	// Selectie op winrate geeft nauwkeurigere analyse-resultaten.
+	// Eis: minstens 5% van totaal bezoeken, zodat noisy low-visit zetten niet winnen.
+	if e.Config.OmniscientMode {
+		totalIters := 0
+		for _, v := range totalVisits {
+			totalIters += v
+		}
+		minVisits := totalIters / 20 // minstens 5% van totaal
+		bestWR := -1.0
+		for _, k := range keys {
+			if v := totalVisits[k]; v >= minVisits {
+				wr := totalWins[k] / float64(v)
+				if wr > bestWR {
+					bestWR = wr
+					bestKey = k
+					bestVisits = v
+				}
+			}
+		}
+	}
+	bestMove := moveMap[bestKey]
+	// Pass-override: alleen forceren als PASS duidelijk slechter is dan de beste
+	// speel-zet, EN de situatie gevaarlijk is (speler staat ver achter).
+	// Voorheen overschreef dit PASS altijd in 2-speler, zelfs als PASS objectief beter was.
+	myID := gs.CurrentTurn
+	myCards := gs.Hands[myID].Count()
+	oppCards := minOppHandCount(gs, myID)
+	if bestMove.IsPass {
+		bestNonPassKey := ""
+		bestNonPassWR := -1.0
+		for _, k := range keys {
+			if m := moveMap[k]; !m.IsPass {
+				v := totalVisits[k]
+				if v > 0 {
+					wr2 := totalWins[k] / float64(v)
+					if wr2 > bestNonPassWR {
+						bestNonPassWR = wr2
+						bestNonPassKey = k
+					}
+				}
+			}
+		}
+		passWR := 0.0
+		if bestVisits > 0 {
+			passWR = totalWins[bestKey] / float64(bestVisits)
+		}
+		// Override PASS alleen als:
+		// 1) Er een niet-pass zet is met vergelijkbare of betere winrate (minder dan 3% verschil)
+		// 2) EN de situatie gevaarlijk is (4+ kaarten achter, of 2-speler eindspel met ≤5 opp kaarten)
+		urgent := myCards-oppCards >= 4 || (activePlayerCount(gs) <= 2 && oppCards <= 5)
+		if bestNonPassKey != "" && urgent && (bestNonPassWR >= passWR-0.03) {
+			bestKey = bestNonPassKey
+			bestVisits = totalVisits[bestKey]
+			bestMove = moveMap[bestKey]
+		}
+	}
+	wr := 0.0
+	if bestVisits > 0 {
+		wr = totalWins[bestKey] / float64(bestVisits)
+	}
+	details := make([]MoveDetail, 0, len(moveMap))
+	for _, k := range keys {
+		m, v := moveMap[k], totalVisits[k]
+		w := 0.0
+		if v > 0 {
+			w = totalWins[k] / float64(v)
+		}
+		details = append(details, MoveDetail{Move: m, WinRate: w, Visits: v})
+	}
+	for i := 0; i < len(details); i++ {
+		for j := i + 1; j < len(details); j++ {
+			if details[j].Visits > details[i].Visits {
+				details[i], details[j] = details[j], details[i]
+			}
+		}
+	}
+	return bestMove, MoveEval{Score: wr, Visits: bestVisits, Details: details}
+}
+
+func (e *Engine) bestMoveSingle(ctx context.Context, gs *game.GameState, kt *knowledge.KnowledgeTracker, rootFiltered []game.Move, trees []*mctsNode, progress func(game.Move, MoveEval)) (game.Move, MoveEval) {
+	myID := gs.CurrentTurn
+	root := trees[myID]
+	hasDeadline := e.Config.MaxTime > 0
+	deadline := time.Now().Add(e.Config.MaxTime)
+	lastReport := time.Now()
+	for iter := 0; iter < e.Config.Iterations; iter++ {
+		if (hasDeadline && time.Now().After(deadline)) || e.stopped.Load() || ctx.Err() != nil {
+			break
+		}
+		detGS := e.determinize(gs, kt)
+		if detGS == nil {
+			continue
+		}
+		e.iterate(trees, detGS, myID, rootFiltered)
+		if progress != nil && time.Since(lastReport) >= progressInterval {
+			progress(e.pickBest(root, myID))
+			lastReport = time.Now()
+		}
+	}
+	bestMove, eval := e.pickBest(root, myID)
+	// Pass-override: alleen forceren als situatie urgent is en verschil klein.
+	myCards2 := gs.Hands[myID].Count()
+	oppCards2 := minOppHandCount(gs, myID)
+	urgent2 := myCards2-oppCards2 >= 4 || (activePlayerCount(gs) <= 2 && oppCards2 <= 5)
+	if bestMove.IsPass && urgent2 {
+		passWR := eval.Score
+		if m, ok := bestNonPassFromDetails(eval.Details); ok {
+			for _, d := range eval.Details {
+				if game.MovesEqual(d.Move, m) && d.WinRate >= passWR-0.03 {
+					return m, MoveEval{Score: d.WinRate, Visits: d.Visits, Details: eval.Details}
+				}
+			}
+		}
+	}
+	return bestMove, eval
+}
+
+// backprop telt de beloningen per speler op in node en zijn voorouders.
+// wins is altijd de beloning van myID (voor de wortelstatistieken); bij
+// max^n krijgt elke knoop ook de beloning van elke speler.
+func (e *Engine) backprop(node *mctsNode, rewards []float64, myID int) {
+	maxN := e.Config.maxN(len(rewards))
+	for node != nil {
+		node.visits++
+		node.wins += rewards[myID]
+		if maxN {
+			if node.rewards == nil {
+				node.rewards = make([]float64, len(rewards))
+			}
+			for p, r := range rewards {
+				node.rewards[p] += r
+			}
+		}
+		node = node.parent
+	}
+}
+
+func (e *Engine) pickBest(root *mctsNode, myID int) (game.Move, MoveEval) {
+	if len(root.children) == 0 {
+		return game.PassMove(myID), MoveEval{}
+	}
+	var bestNode *mctsNode
+	if e.Config.OmniscientMode {
+		// OmniscientMode (analyse): selecteer op winrate, niet op visits.
+		// Eis: minstens 5% van root visits, zodat noisy low-visit zetten niet winnen.
+		totalV := 0
+		for _, ch := range root.children {
+			totalV += ch.visits
+		}
+		minV := totalV / 20
+		bestWR := -1.0
+		for _, ch := range root.children {
+			if ch.visits >= minV {
+				wr := ch.wins / float64(ch.visits)
+				if wr > bestWR {
+					bestWR = wr
+					bestNode = ch
+				}
+			}
+		}
+	}
+	if bestNode == nil {
+		// Fallback (of non-OmniscientMode): selecteer op visits
+		bestV := -1
+		for _, ch := range root.children {
+			if ch.visits > bestV {
+				bestV = ch.visits
+				bestNode = ch
+			}
+		}
+	}
+	wr := 0.0
+	if bestNode.visits > 0 {
+		wr = bestNode.wins / float64(bestNode.visits)
+	}
+	details := make([]MoveDetail, len(root.children))
+	for i, ch := range root.children {
+		w := 0.0
+		if ch.visits > 0 {
+			w = ch.wins / float64(ch.visits)
+		}
+		details[i] = MoveDetail{Move: ch.move, WinRate: w, Visits: ch.visits}
+	}
+	for i := 0; i < len(details); i++ {
+		for j := i + 1; j < len(details); j++ {
+			if details[j].Visits > details[i].Visits {
+				details[i], details[j] = details[j], details[i]
+			}
+		}
+	}
+	return bestNode.move, MoveEval{Score: wr, Visits: bestNode.visits, Details: details}
+}
+
+// minOppHandCount geeft het laagste kaartaantal van actieve tegenstanders.
+func minOppHandCount(gs *game.GameState, myID int) int {
+	min := 999
+	for i, h := range gs.Hands {
+		if i != myID && !gs.Finished[i] && h.Count() < min {
+			min = h.Count()
+		}
+	}
+	if min == 999 {
+		return 0
+	}
+	return min
+}
+
+// activePlayerCount telt het aantal spelers dat nog actief is (niet gefinished, nog kaarten).
+func activePlayerCount(gs *game.GameState) int {
+	count := 0
+	for i := range gs.Hands {
+		if !gs.Finished[i] && gs.Hands[i].Count() > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// bestNonPassFromDetails geeft de non-pass zet met de hoogste win-rate uit MCTS-details.
+func bestNonPassFromDetails(details []MoveDetail) (game.Move, bool) {
+	bestWR := -1.0
+	var bestMove game.Move
+	found := false
+	for _, d := range details {
+		if !d.Move.IsPass && d.Visits > 0 && d.WinRate > bestWR {
+			bestWR = d.WinRate
+			bestMove = d.Move
+			found = true
+		}
+	}
+	return bestMove, found
+}
+
+func (e *Engine) AnalyzeMove(gs *game.GameState, kt *knowledge.KnowledgeTracker, m game.Move) MoveDetail {
+	return e.AnalyzeMoveContext(context.Background(), gs, kt, m)
+}
+
+// AnalyzeMoveContext is AnalyzeMove die stopt zodra ctx afloopt; de score
+// rust dan op de simulaties tot dan.
+func (e *Engine) AnalyzeMoveContext(ctx context.Context, gs *game.GameState, kt *knowledge.KnowledgeTracker, m game.Move) MoveDetail {
+	myID := gs.CurrentTurn
+	wins := 0.0
+	sims := 0
+	for ; sims < 1000 && ctx.Err() == nil; sims++ {
+		det := e.determinize(gs, kt)
+		if det == nil {
+			continue
+		}
+		sim := det.Clone()
+		sim.ApplyMove(m)
+		result := e.simulate(sim, myID)
+		wins += result
+	}
+	if sims == 0 {
+		return MoveDetail{Move: m}
+	}
+	return MoveDetail{Move: m, WinRate: wins / float64(sims), Visits: sims}
+}
+
+// BestFollow zoekt na een joker-reset de beste vervolg-zet: de speler die
+// best speelde opent meteen opnieuw. ok is false als best geen reset is of
+// de partij daarna voorbij is.
+func (e *Engine) BestFollow(gs *game.GameState, kt *knowledge.KnowledgeTracker, best game.Move) (follow game.Move, ok bool) {
+	return e.BestFollowContext(context.Background(), gs, kt, best)
+}
+
+// BestFollowContext is BestFollow die stopt zodra ctx afloopt.
+func (e *Engine) BestFollowContext(ctx context.Context, gs *game.GameState, kt *knowledge.KnowledgeTracker, best game.Move) (follow game.Move, ok bool) {
+	if !best.ContainsReset() {
+		return game.Move{}, false
+	}
+	sim := gs.Clone()
+	sim.ApplyMove(best)
+	if sim.GameOver || sim.CurrentTurn != best.PlayerID {
+		return game.Move{}, false
+	}
+	follow, _ = e.bestMove(ctx, sim, kt, nil)
+	return follow, true
+}
+
+// FindMoveInEval zoekt een zet op in de MoveEval-details die door BestMove zijn berekend.
+// Geeft (detail, true) terug als gevonden, anders (zero, false).
+func FindMoveInEval(eval MoveEval, m game.Move) (MoveDetail, bool) {
+	for _, d := range eval.Details {
+		if game.MovesEqual(d.Move, m) && d.Visits > 0 {
+			return d, true
+		}
+	}
+	return MoveDetail{}, false
+}
+
+func mkey(m game.Move) string {
+	if m.IsPass {
+		return "PASS"
+	}
+	sorted := make([]cards.Card, len(m.Cards))
+	copy(sorted, m.Cards)
+	for i := 0; i < len(sorted); i++ {
+		for j := i + 1; j < len(sorted); j++ {
+			if sorted[i].Rank > sorted[j].Rank || (sorted[i].Rank == sorted[j].Rank && sorted[i].Suit > sorted[j].Suit) {
+				sorted[i], sorted[j] = sorted[j], sorted[i]
+			}
+		}
+	}
+	key := ""
+	for _, c := range sorted {
+		key += c.String()
+	}
+	return key
+}
@@ -0,0 +1,137 @@
+package engine
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/game"
+	"github.com/azen-engine/knowledge"
+)
+
+// testTable is een partij met de tracker van speler 0.
+type testTable struct {
+	gs *game.GameState
+	kt *knowledge.KnowledgeTracker
+}
+
+// newEndgame maakt een partij met de gegeven handen waarin de rest van het
+// deck dood ligt; speler 0 begint en is de zoekende speler.
+func newEndgame(t testing.TB, hands ...string) *testTable {
+	t.Helper()
+	rest := cards.NewHand(cards.NewDeck().Cards)
+	hs := make([]*cards.Hand, len(hands))
+	for i, h := range hands {
+		cc, err := cards.ParseCards(h)
+		if err != nil {
+			t.Fatalf("ParseCards(%q): %v", h, err)
+		}
+		if err := rest.Remove(cc); err != nil {
+			t.Fatalf("hand %q: %v", h, err)
+		}
+		hs[i] = cards.NewHand(cc)
+	}
+	gs := game.NewGameWithHands(hs, rest.Cards, 0)
+	kt := knowledge.NewKnowledgeTracker(len(hands), 0, hs[0].Clone(), rest.Cards)
+	for p, h := range hs {
+		kt.HandCounts[p] = h.Count()
+		kt.ClearSuspicions(p)
+	}
+	return &testTable{gs: gs, kt: kt}
+}
+
+// play valideert en speelt een zet van de speler aan zet; "p" is een pass.
+func (tt *testTable) play(t testing.TB, s string) {
+	t.Helper()
+	m := game.PassMove(tt.gs.CurrentTurn)
+	if s != "p" {
+		cc, err := cards.ParseCards(s)
+		if err != nil {
+			t.Fatalf("ParseCards(%q): %v", s, err)
+		}
+		m = game.Move{PlayerID: tt.gs.CurrentTurn, Cards: cc}
+	}
+	if err := tt.gs.ValidateMove(m); err != nil {
+		t.Fatalf("P%d %q: %v", m.PlayerID, s, err)
+	}
+	if m.IsPass {
+		tt.kt.RecordPass(m.PlayerID, tt.gs.Round)
+	}
+	tt.kt.RecordMove(m)
+	tt.gs.ApplyMove(m)
+}
+
+// testConfig is een kleine, deterministische configuratie.
+func testConfig(numPlayers int) Config {
+	cfg := DefaultConfig(numPlayers)
+	cfg.Iterations = 300
+	cfg.NumWorkers = 1
+	cfg.Seed = 7
+	return cfg
+}
+
+func TestBackpropTwoPlayers(t *testing.T) {
+	var moves []game.Move
+	var evals []MoveEval
+	for _, b := range []Backprop{BackpropMaxN, BackpropParanoid} {
+		tt := newEndgame(t, "K K 5 3", "Q 9 9 4")
+		cfg := testConfig(2)
+		cfg.Backprop = b
+		m, eval := NewEngine(cfg).BestMove(tt.gs, tt.kt)
+		moves = append(moves, m)
+		evals = append(evals, eval)
+	}
+	if evals[0].Visits == 0 {
+		t.Fatalf("geen iteraties: %v", evals[0])
+	}
+	if mkey(moves[0]) != mkey(moves[1]) {
+		t.Errorf("max^n koos %v, paranoid %v", moves[0], moves[1])
+	}
+	if !reflect.DeepEqual(evals[0].Details, evals[1].Details) {
+		t.Errorf("statistieken verschillen:\nmax^n    %v\nparanoid %v", evals[0].Details, evals[1].Details)
+	}
+}
+
+func TestCancelledSearchPlays(t *testing.T) {
+	for _, workers := range []int{1, 2} {
+		tt := newEndgame(t, "K K 9 7 5 3", "Q Q 8 6 4 4")
+		tt.play(t, "3")
+		tt.play(t, "4")
+		cfg := testConfig(2)
+		cfg.NumWorkers = workers
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		m, eval := NewEngine(cfg).BestMoveContext(ctx, tt.gs, tt.kt)
+		if m.IsPass {
+			t.Errorf("%d workers: pass na afgebroken zoektocht (%v)", workers, eval)
+		}
+		if err := tt.gs.ValidateMove(m); err != nil {
+			t.Errorf("%d workers: %v: %v", workers, m, err)
+		}
+	}
+}
+
+func TestSearchClosesAfterDone(t *testing.T) {
+	tt := newEndgame(t, "K K 9 7 5 3", "Q Q 8 6 4 4")
+	want, _ := NewEngine(testConfig(2)).BestMove(tt.gs.Clone(), tt.kt.Clone())
+	var updates []SearchUpdate
+	for u := range NewEngine(testConfig(2)).Search(context.Background(), tt.gs, tt.kt) {
+		updates = append(updates, u)
+	}
+	if len(updates) == 0 {
+		t.Fatal("geen enkele update")
+	}
+	for _, u := range updates[:len(updates)-1] {
+		if u.Done {
+			t.Errorf("update met Done vóór de laatste: %v", u.Best)
+		}
+	}
+	last := updates[len(updates)-1]
+	if !last.Done {
+		t.Fatal("laatste update heeft geen Done")
+	}
+	if mkey(last.Best) != mkey(want) {
+		t.Errorf("Search gaf %v, BestMove %v", last.Best, want)
+	}
+}
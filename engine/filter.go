@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/game"
+)
+
+// filterDominatedMoves verwijdert wild+normal combinaties die gedomineerd worden door
+// naturelle zetten (geen wildcards). Een wild-zet is gedomineerd als er een naturelle zet
+// bestaat die een GELIJKE OF HOGERE effectieve rank bereikt — de wildcard is dan pure verspilling.
+//
+// Wild-zetten die een UNIEK HOGERE rank bereiken dan alle naturelle opties blijven altijd
+// beschikbaar (bijv. K+wild als QQ de hoogste naturelle zet is: rank 13 > rank 12).
+// Zo is de filter veilig bij zowel lage als hoge iteratiecounts.
+//
+// Puur-wildcardspellen (2+joker), reset-zetten (joker) en PASS worden nooit gefilterd.
+// In open rondes geen filter.
+func filterDominatedMoves(moves []game.Move, round game.RoundState) []game.Move {
+	if round.IsOpen {
+		return moves
+	}
+	tableRank := round.TableRank
+
+	// Bepaal de hoogste effectieve rank die bereikbaar is via naturelle zetten (geen wilds, geen aces)
+	maxNaturalRank := cards.Rank(0)
+	for _, m := range moves {
+		if m.IsPass {
+			continue
+		}
+		hasWild, hasReset, hasNormal := false, false, false
+		for _, c := range m.Cards {
+			if c.IsWild() {
+				hasWild = true
+			} else if c.IsReset() {
+				hasReset = true
+			} else {
+				hasNormal = true
+			}
+		}
+		if !hasWild && !hasReset && hasNormal {
+			if er := m.EffectiveRank(tableRank); er > maxNaturalRank {
+				maxNaturalRank = er
+			}
+		}
+	}
+	if maxNaturalRank == 0 {
+		return moves // Geen naturelle zetten beschikbaar — alles bewaren
+	}
+
+	filtered := make([]game.Move, 0, len(moves))
+	for _, m := range moves {
+		if m.IsPass {
+			filtered = append(filtered, m)
+			continue
+		}
+		hasWild, hasReset, hasNormal := false, false, false
+		for _, c := range m.Cards {
+			if c.IsWild() {
+				hasWild = true
+			} else if c.IsReset() {
+				hasReset = true
+			} else {
+				hasNormal = true
+			}
+		}
+		// Oversized combo filter ("/" zetten met meer kaarten dan de tabelgrootte).
+		// Voorbeeld: tafel = XX (2 kaarten), naturelle KK beschikbaar, maar engine speelt
+		// "01/55" (4 kaarten: joker+aas+5+5). De joker+aas is verspilling als KK volstaat.
+		// Regel: filter oversized special combos als een naturelle zet de tafel al verslaat.
+		// Uitzondering: als geen naturelle zet bestaat (maxNaturalRank == 0 of ≤ tableRank),
+		// dan is de oversized combo soms de enige optie → bewaren.
+		if len(m.Cards) > round.Count && (hasWild || hasReset) && maxNaturalRank > tableRank {
+			continue // gefilterd: naturelle zet is efficiënter dan deze "/" combo
+		}
+		if !hasWild {
+			filtered = append(filtered, m) // Naturelle zet (geen wildcards): altijd bewaren
+			continue
+		}
+		// Vanaf hier: zet bevat minstens één wildcard.
+
+		// Puur-wild (geen reset, geen normaal — bijv. 2+2):
+		// een naturelle zet verslaat de tafel en spaart alle wildcards → filter.
+		if !hasNormal && !hasReset {
+			continue // gefilterd: puur-wild gedomineerd door naturelle zetten
+		}
+		// Wild+joker (bijv. "2 0") EN wild+normaal (bijv. "K 2", "A 2"):
+		// beide gebruiken een wildcard. Alleen bewaren als de effectieve rank
+		// voldoende hoger is dan alle naturelle opties.
+		// Bij hoge naturelle ranks (≥10) is een 1-rank voordeel te klein:
+		// de wildcard-kost weegt niet op tegen het minimale voordeel.
+		// Eis 2+ rank voordeel zodra maxNaturalRank ≥ RankTen.
+		//
+		// Voorbeeld wild+aas "0 1" (rank 14) vs KK (rank 13, ≥10):
+		//   threshold=14, 14>14=false → gefilterd ✓ (KK volstaat!)
+		// Voorbeeld wild+aas "0 1" (rank 14) vs JJ (rank 11, ≥10):
+		//   threshold=12, 14>12=true  → bewaard  ✓ (3-rank voordeel)
+		// Voorbeeld wild+normaal "K 2" (rank 13) vs QQ (rank 12, ≥10):
+		//   threshold=13, 13>13=false → gefilterd ✓
+		// Voorbeeld wild+normaal "K 2" (rank 13) vs JJ (rank 11, ≥10):
+		//   threshold=12, 13>12=true  → bewaard  ✓
+		er := m.EffectiveRank(tableRank)
+		threshold := maxNaturalRank
+		if maxNaturalRank >= cards.RankTen {
+			threshold++ // vereist 2+ rank voordeel bij hoge naturelle ranks
+		}
+		if er > threshold {
+			filtered = append(filtered, m)
+		}
+		// Gefilterd: wildcard-voordeel te klein t.o.v. beschikbare naturelle zetten
+	}
+	return filtered
+}
@@ -0,0 +1,206 @@
+package engine
+
+import (
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/game"
+)
+
+type HandStrength struct {
+	CardCount     int
+	WildCount     int
+	AceCount      int
+	HighCardCount int
+	LonelyKings   int
+	PairCount     int
+	TripleCount   int
+	TempoScore    float64
+	OverallScore  float64
+}
+
+func EvaluateHand(hand *cards.Hand) HandStrength {
+	hs := HandStrength{CardCount: hand.Count()}
+	hs.WildCount = hand.CountWilds()
+	hs.AceCount = hand.CountRank(cards.RankAce) // Aas is nu de hoogste naturelle kaart
+	rankCounts := make(map[cards.Rank]int)
+	for _, c := range hand.Cards {
+		if !c.IsSpecial() {
+			rankCounts[c.Rank]++
+		}
+	}
+	for rank, count := range rankCounts {
+		if rank >= cards.RankJack {
+			hs.HighCardCount += count
+		}
+		if count >= 2 {
+			hs.PairCount++
+		}
+		if count >= 3 {
+			hs.TripleCount++
+		}
+		if rank == cards.RankKing {
+			hs.LonelyKings = count
+		}
+	}
+	hs.TempoScore = float64(hs.AceCount) * 2.0
+	hs.OverallScore = 100.0 - float64(hs.CardCount)*5.0
+	hs.OverallScore += float64(hs.WildCount) * 8.0
+	hs.OverallScore += float64(hs.AceCount) * 10.0
+	hs.OverallScore += float64(hs.PairCount) * 3.0
+	hs.OverallScore += float64(hs.TripleCount) * 5.0
+	kingPenalty := hs.LonelyKings - hs.WildCount
+	if kingPenalty > 0 {
+		hs.OverallScore -= float64(kingPenalty) * 6.0
+	}
+	lowCards := 0
+	for _, rank := range []cards.Rank{cards.RankThree, cards.RankFour, cards.RankFive} {
+		lowCards += rankCounts[rank]
+	}
+	if hs.AceCount == 0 && lowCards > 0 {
+		hs.OverallScore -= float64(lowCards) * 2.0
+	}
+	return hs
+}
+
+type MoveQuality struct {
+	Move             game.Move
+	Score            float64
+	Reasoning        string
+	WastesWilds      bool
+	WastesAces       bool
+	CreatesWinThreat bool
+}
+
+func QuickEvaluateMove(gs *game.GameState, move game.Move) MoveQuality {
+	mq := MoveQuality{Move: move}
+	hand := gs.Hands[move.PlayerID]
+	if move.IsPass {
+		mq.Score = 0.0
+		mq.Reasoning = "Pass"
+		return mq
+	}
+	cardsAfter := hand.Count() - len(move.Cards)
+	if cardsAfter == 0 {
+		mq.Score = 100.0
+		mq.CreatesWinThreat = true
+		mq.Reasoning = "Winning move!"
+		return mq
+	}
+	mq.Score = 50.0
+	wildsUsed := 0
+	resetsUsed := 0
+	normalsUsed := 0
+	for _, c := range move.Cards {
+		if c.IsWild() {
+			wildsUsed++
+		} else if c.IsReset() {
+			resetsUsed++
+		} else {
+			normalsUsed++
+		}
+	}
+	effectiveRank := move.EffectiveRank(gs.Round.TableRank)
+
+	// Wild-verspilling: scherpere straf naarmate de rank lager is.
+	// Wild op rank 3-5 is catastrofaal; op rank 6-9 is slecht; op 10+ is acceptabel.
+	if wildsUsed > 0 {
+		if effectiveRank <= cards.RankFive {
+			mq.Score -= float64(wildsUsed) * 12.0
+			mq.WastesWilds = true
+			mq.Reasoning = "Wastes wildcards on very low play"
+		} else if effectiveRank < cards.RankTen {
+			mq.Score -= float64(wildsUsed) * 7.0
+			mq.WastesWilds = true
+			mq.Reasoning = "Wastes wildcards on low play"
+		} else {
+			mq.Score -= float64(wildsUsed) * 2.0 // mild; wild op K is prima
+		}
+	}
+
+	if resetsUsed > 0 {
+		mq.Score += 5.0
+		mq.WastesAces = resetsUsed > 1
+		if mq.WastesAces {
+			mq.Score -= float64(resetsUsed-1) * 8.0
+			mq.Reasoning = "Uses multiple resets unnecessarily"
+		}
+	}
+
+	// Response-context: bij een response-ronde is de LAAGSTE winnende zet het best.
+	// Je wilt sterke kaarten bewaren voor later. Straf proportioneel aan "overshoot".
+	if !gs.Round.IsOpen && effectiveRank > 0 {
+		overshoot := float64(effectiveRank-gs.Round.TableRank) - 1.0
+		if overshoot < 0 {
+			overshoot = 0
+		}
+		mq.Score -= overshoot * 3.0 // bijv. Queen op een 6 tafel = -15 (overshoot 5)
+	}
+
+	// Open ronde: lage kaarten dumpen is goed — MAAR alleen als je daarna
+	// nog ≥3 kaarten overhoudt. Als je na de zet ≤2 kaarten overhoudt, is
+	// het spelen van hoge kaarten (bijv. QQQ) juist de WIN-strategie en moet
+	// de straf onderdrukt worden (anders wint "5" onterecht van "QQQ").
+	if gs.Round.IsOpen && effectiveRank > 0 && cardsAfter >= 3 {
+		rankValue := float64(effectiveRank-cards.RankThree) / float64(cards.RankAce-cards.RankThree)
+		mq.Score -= rankValue * 8.0 // hoge kaarten in open ronde = verspilling
+	}
+
+	// Meerdere kaarten tegelijk kwijtraken is goed.
+	mq.Score += float64(len(move.Cards)) * 3.0
+
+	// Paar-breek penalty: als je een paar breekt om een single te spelen, is dat slecht.
+	if normalsUsed == 1 && wildsUsed == 0 && resetsUsed == 0 {
+		for _, c := range move.Cards {
+			if hand.CountRank(c.Rank) >= 2 {
+				mq.Score -= 5.0 // breekt een cluster
+				break
+			}
+		}
+	}
+
+	// Win-threat bonus: schaalbaar naargelang hoeveel kaarten er overblijven.
+	// Hoe dichter bij winst, hoe groter de bonus — zodat QQQ (1 kaart over)
+	// duidelijk wint van 5 (3 kaarten over) in de heuristische evaluatie.
+	switch {
+	case cardsAfter == 1:
+		mq.Score += 25.0
+		mq.CreatesWinThreat = true
+	case cardsAfter == 2:
+		mq.Score += 18.0
+		mq.CreatesWinThreat = true
+	case cardsAfter == 3:
+		mq.Score += 10.0
+		mq.CreatesWinThreat = true
+	case cardsAfter == 4:
+		mq.Score += 4.0
+	}
+	if gs.Round.IsOpen && resetsUsed > 0 {
+		mq.Score += 10.0
+	}
+	return mq
+}
+
+func ShouldPass(gs *game.GameState, playerID int) bool {
+	hand := gs.Hands[playerID]
+	if hand.Count() <= 3 {
+		return false
+	}
+	if gs.Round.IsOpen {
+		return false
+	}
+	if gs.Round.TableRank <= cards.RankSix {
+		return false
+	}
+	if gs.Round.TableRank >= cards.RankKing {
+		normalCardsAbove := 0
+		for _, c := range hand.Cards {
+			// !IsSpecial() now includes Ace (natural card)
+			if !c.IsSpecial() && c.Rank > gs.Round.TableRank {
+				normalCardsAbove++
+			}
+		}
+		if normalCardsAbove == 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package engine
+
+import (
+	"math"
+
+	"github.com/azen-engine/game"
+)
+
+// IS-MCTS met informatieset-knopen (Cowling, Powley & Whitehouse,
+// "Information Set Monte Carlo Tree Search", 2012).
+//
+// Een knoop staat voor wat de spelers gezien hebben, niet voor één
+// determinisatie. Kinderen zijn sleutels van waarneembare zetten (mkey: in
+// Azen worden kaarten open gespeeld en tellen kleuren niet). In elke
+// iteratie zijn enkel de kinderen die in de getrokken determinisatie legaal
+// zijn bruikbaar; de zet wordt dan uit die determinisatie genomen, zodat
+// werelden niet door elkaar lopen. Elk bruikbaar kind telt een
+// beschikbaarheid, en UCB gebruikt die in plaats van de bezoeken van de
+// ouder: een zet die zelden mogelijk is wordt niet als onderverkend gezien.
+//
+// SO-ISMCTS bouwt één boom. MO-ISMCTS bouwt er één per speler; de speler
+// aan zet kiest in zijn eigen boom en alle bomen volgen de gespeelde zet.
+// Elke boom houdt enkel de beloning van zijn eigenaar bij (bij paranoid is
+// die van een tegenstander het omgekeerde van de onze), geen vector per
+// speler. Omdat alle zetten in Azen waarneembaar zijn, hebben de bomen
+// dezelfde vorm en kiest MO dezelfde zetten als SO; het verschil zou pas
+// tellen bij zetten die niet iedereen ziet.
+
+// newTrees geeft de wortel per speler: bij MO een eigen boom per speler,
+// anders dezelfde wortel voor iedereen.
+func (e *Engine) newTrees(numPlayers int) []*mctsNode {
+	trees := make([]*mctsNode, numPlayers)
+	root := newRoot()
+	for p := range trees {
+		if e.Config.Algorithm == AlgorithmMO {
+			trees[p] = newRoot()
+		} else {
+			trees[p] = root
+		}
+	}
+	return trees
+}
+
+// iterate voert één iteratie van de zoektocht uit op de determinisatie
+// detGS, volgens Config.Algorithm.
+func (e *Engine) iterate(trees []*mctsNode, detGS *game.GameState, myID int, rootFiltered []game.Move) {
+	if e.Config.Algorithm == AlgorithmDeterminized {
+		node, simGS := e.selectExpand(trees[myID], detGS, myID, rootFiltered)
+		e.backprop(node, e.rewards(e.rollout(simGS), myID), myID)
+		return
+	}
+	leaves, simGS := e.selectExpandIS(trees, detGS, myID, rootFiltered)
+	rewards := e.rewards(e.rollout(simGS), myID)
+	if e.Config.Algorithm != AlgorithmMO {
+		e.backprop(leaves[myID], rewards, myID)
+		return
+	}
+	maxN := e.Config.maxN(len(rewards))
+	for p, leaf := range leaves {
+		r := rewards[p]
+		if !maxN && p != myID {
+			r = 1 - rewards[myID]
+		}
+		backpropOwner(leaf, r)
+	}
+}
+
+// backpropOwner telt bij MO de beloning r van de eigenaar van de boom op in
+// node en zijn voorouders.
+func backpropOwner(node *mctsNode, r float64) {
+	for ; node != nil; node = node.parent {
+		node.visits++
+		node.wins += r
+	}
+}
+
+// selectExpandIS daalt af in alle bomen tot de speler aan zet in zijn boom
+// een onverkende zet heeft, breidt die uit en geeft het blad per speler
+// terug (bij SO voor iedereen hetzelfde).
+func (e *Engine) selectExpandIS(trees []*mctsNode, gs *game.GameState, myID int, rootFiltered []game.Move) ([]*mctsNode, *game.GameState) {
+	nodes := append([]*mctsNode(nil), trees...)
+	simGS := gs.Clone()
+	for !simGS.GameOver {
+		mover := simGS.CurrentTurn
+		node := nodes[mover]
+		var moves []game.Move
+		if node == trees[myID] && rootFiltered != nil {
+			moves = rootFiltered
+		} else {
+			moves = simGS.GetLegalMoves()
+		}
+		if len(moves) == 0 {
+			break
+		}
+		if unexplored := e.unexploredMoves(node, moves); len(unexplored) > 0 {
+			m := e.pickUnexplored(simGS, unexplored)
+			descend(nodes, m)
+			simGS.ApplyMove(m)
+			return nodes, simGS
+		}
+		// Alle legale zetten hebben een kind: kies onder de kinderen die in
+		// deze determinisatie legaal zijn.
+		legal := make(map[string]game.Move, len(moves))
+		for _, m := range moves {
+			legal[mkey(m)] = m
+		}
+		// Een MO-boom kent enkel de beloning van zijn eigenaar, de speler
+		// aan zet.
+		owner := myID
+		if e.Config.Algorithm == AlgorithmMO {
+			owner = mover
+		}
+		var best *mctsNode
+		bestScore := math.Inf(-1)
+		for _, ch := range node.children {
+			if _, ok := legal[mkey(ch.move)]; !ok {
+				continue
+			}
+			ch.avail++
+			score := math.Inf(1)
+			if ch.visits > 0 {
+				score = e.ucb(ch, ch.avail, mover, owner)
+			}
+			if best == nil || score > bestScore {
+				best, bestScore = ch, score
+			}
+		}
+		m := legal[mkey(best.move)]
+		descend(nodes, m)
+		simGS.ApplyMove(m)
+	}
+	return nodes, simGS
+}
+
+// descend zet elke boom een stap verder langs zet m en maakt het kind aan
+// waar het nog ontbreekt. Bomen die dezelfde knoop delen (SO) gaan samen.
+func descend(nodes []*mctsNode, m game.Move) {
+	next := map[*mctsNode]*mctsNode{}
+	for p, node := range nodes {
+		if ch, ok := next[node]; ok {
+			nodes[p] = ch
+			continue
+		}
+		key := mkey(m)
+		var child *mctsNode
+		for _, ch := range node.children {
+			if mkey(ch.move) == key {
+				child = ch
+				break
+			}
+		}
+		if child == nil {
+			child = &mctsNode{move: m, parent: node, playerID: m.PlayerID}
+			node.children = append(node.children, child)
+		}
+		next[node] = child
+		nodes[p] = child
+	}
+}
+
+// distinct geeft elke knoop van nodes één keer.
+func distinct(nodes []*mctsNode) []*mctsNode {
+	var out []*mctsNode
+	seen := map[*mctsNode]bool{}
+	for _, n := range nodes {
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	return out
+}
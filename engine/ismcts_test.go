@@ -0,0 +1,23 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMOMatchesSO(t *testing.T) {
+	var evals []MoveEval
+	for _, a := range []Algorithm{AlgorithmSO, AlgorithmMO} {
+		tt := newEndgame(t, "K K 5 3", "Q 9 9 4", "J 8 6 6")
+		cfg := testConfig(3)
+		cfg.Algorithm = a
+		_, eval := NewEngine(cfg).BestMove(tt.gs, tt.kt)
+		evals = append(evals, eval)
+	}
+	if evals[0].Visits == 0 {
+		t.Fatalf("geen iteraties: %v", evals[0])
+	}
+	if !reflect.DeepEqual(evals[0].Details, evals[1].Details) {
+		t.Errorf("statistieken verschillen:\nSO %v\nMO %v", evals[0].Details, evals[1].Details)
+	}
+}
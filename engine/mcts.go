@@ -0,0 +1,646 @@
+package engine
+
+import (
+	"math"
+
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/game"
+	"github.com/azen-engine/knowledge"
+)
+
+type mctsNode struct {
+	move     game.Move
+	parent   *mctsNode
+	children []*mctsNode
+	visits   int
+	wins     float64   // som van de beloningen van de zoekende speler
+	rewards  []float64 // som van de beloningen per speler (enkel bij max^n)
+	avail    int       // IS-MCTS: keren dat de zet legaal was bij selectie in de ouder
+	playerID int
+}
+
+func newRoot() *mctsNode { return &mctsNode{playerID: -1} }
+
+func (e *Engine) determinize(gs *game.GameState, kt *knowledge.KnowledgeTracker) *game.GameState {
+	if e.Config.OmniscientMode {
+		return gs.Clone()
+	}
+	det := gs.Clone()
+	possible := kt.PossibleOpponentCards()
+	e.rng.Shuffle(len(possible), func(i, j int) {
+		possible[i], possible[j] = possible[j], possible[i]
+	})
+	used := make([]bool, len(possible))
+	for p := 0; p < gs.NumPlayers; p++ {
+		if p == kt.MyPlayerID {
+			continue
+		}
+		need := kt.HandCounts[p]
+		if need < 0 {
+			need = 0
+		}
+		excluded := kt.ExcludedRanks(p)
+		suspCount := map[cards.Rank]int{}
+		for _, c := range kt.Suspicions[p] {
+			suspCount[c.Rank]++
+		}
+		assignedSusp := map[cards.Rank]int{}
+		var tier1, tier2, tier3 []int
+		for i, c := range possible {
+			if used[i] {
+				continue
+			}
+			if assignedSusp[c.Rank] < suspCount[c.Rank] {
+				tier1 = append(tier1, i)
+				assignedSusp[c.Rank]++
+			} else if !excluded[c.Rank] {
+				tier2 = append(tier2, i)
+			} else {
+				tier3 = append(tier3, i)
+			}
+		}
+		// Sterke-kaarten-bias: ga er vanuit dat de tegenstander hoge naturelle kaarten
+		// en wildcards bezit (assen en tweetjes) — in alle spelfasen.
+		// Joker (reset) wordt NIET meer geprioriteerd: er zijn slechts 2 jokers in het
+		// hele deck. Statistisch heeft de tegenstander ~76% kans op minstens één joker
+		// als jij er geen hebt — dat is te laag om stelselmatig te assumeren.
+		// Aas (4 exemplaren) en Twee/wildcard (4 exemplaren) hebben wél een hoge
+		// kans (~84%) → die worden wel geprioriteerd in de determinisatie.
+		// Motivatie: spelers bewaren hun sterkste naturelle kaarten tot laat; het derde
+		// niet-gedealde pakje bevat relatief meer zwakke kaarten, dus de echte hand
+		// heeft verhoudingsgewijs meer sterke kaarten dan een willekeurige steekproef.
+		if need > 0 {
+			var strongFront, rest []int
+			for _, idx := range tier2 {
+				r := possible[idx].Rank
+				// Bias: Aas (hoogste naturelle kaart) en Twee (wildcard) vooraan
+				if r == cards.RankAce || r == cards.RankTwo {
+					strongFront = append(strongFront, idx)
+				} else {
+					rest = append(rest, idx)
+				}
+			}
+			tier2 = append(strongFront, rest...)
+		}
+		ordered := append(append(tier1, tier2...), tier3...)
+		if len(ordered) < need {
+			return nil
+		}
+		hand := make([]cards.Card, need)
+		for i := 0; i < need; i++ {
+			idx := ordered[i]
+			hand[i] = possible[idx]
+			used[idx] = true
+		}
+		det.Hands[p] = cards.NewHand(hand)
+	}
+	return det
+}
+
+// selectExpand voert de selectie- en expansiefase van MCTS uit.
+// rootFiltered: gefilterde zetten voor het root-knooppunt (nil = gebruik alle zetten).
+func (e *Engine) selectExpand(node *mctsNode, gs *game.GameState, myID int, rootFiltered []game.Move) (*mctsNode, *game.GameState) {
+	simGS := gs.Clone()
+	for !simGS.GameOver {
+		// Bij het root-knooppunt (parent == nil) enkel de gefilterde zetten aanbieden;
+		// dieper in de boom altijd alle legale zetten gebruiken.
+		var moves []game.Move
+		if node.parent == nil && rootFiltered != nil {
+			moves = rootFiltered
+		} else {
+			moves = simGS.GetLegalMoves()
+		}
+		if len(moves) == 0 {
+			break
+		}
+		unexplored := e.unexploredMoves(node, moves)
+		if len(unexplored) > 0 {
+			m := e.pickUnexplored(simGS, unexplored)
+			child := &mctsNode{move: m, parent: node, playerID: m.PlayerID}
+			node.children = append(node.children, child)
+			simGS.ApplyMove(m)
+			return child, simGS
+		}
+		best := e.ucb1Select(node, simGS.CurrentTurn, myID)
+		if best == nil {
+			break
+		}
+		simGS.ApplyMove(best.move)
+		node = best
+	}
+	return node, simGS
+}
+
+// pickUnexplored kiest welke onverkende zet eerst uitgebreid wordt.
+func (e *Engine) pickUnexplored(gs *game.GameState, unexplored []game.Move) game.Move {
+	// Move ordering: kies de best-beoordeelde onverkende zet
+	// i.p.v. willekeurig. QuickEvaluateMove geeft heuristische score.
+	m := unexplored[0]
+	if len(unexplored) > 1 {
+		bestScore := -999.0
+		for _, um := range unexplored {
+			var sc float64
+			if um.IsPass {
+				sc = -1.0
+			} else {
+				sc = QuickEvaluateMove(gs, um).Score
+			}
+			// Kleine random tiebreak zodat gelijke zetten niet altijd dezelfde volgorde hebben
+			sc += e.rng.Float64() * 0.5
+			if sc > bestScore {
+				bestScore = sc
+				m = um
+			}
+		}
+	}
+	return m
+}
+
+func (e *Engine) unexploredMoves(node *mctsNode, moves []game.Move) []game.Move {
+	explored := map[string]bool{}
+	for _, ch := range node.children {
+		explored[mkey(ch.move)] = true
+	}
+	var result []game.Move
+	for _, m := range moves {
+		if !explored[mkey(m)] {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// ucb1Select kiest het kind van node voor speler mover. Bij max^n telt de
+// eigen beloning van mover; bij paranoid minimaliseert elke tegenstander
+// de beloning van myID.
+func (e *Engine) ucb1Select(node *mctsNode, mover, myID int) *mctsNode {
+	var best *mctsNode
+	bestScore := math.Inf(-1)
+	for _, ch := range node.children {
+		if ch.visits == 0 {
+			return ch
+		}
+		if score := e.ucb(ch, node.visits, mover, myID); score > bestScore {
+			bestScore = score
+			best = ch
+		}
+	}
+	return best
+}
+
+// ucb is de UCB1-score van ch voor mover; n is het aantal keer dat ch
+// gekozen had kunnen worden (de bezoeken van de ouder, of bij IS-MCTS de
+// beschikbaarheid van ch).
+func (e *Engine) ucb(ch *mctsNode, n, mover, myID int) float64 {
+	var exploit float64
+	switch {
+	case ch.rewards != nil: // max^n
+		exploit = ch.rewards[mover] / float64(ch.visits)
+	case mover == myID:
+		exploit = ch.wins / float64(ch.visits)
+	default:
+		exploit = 1.0 - ch.wins/float64(ch.visits)
+	}
+	return exploit + e.Config.ExploreConst*math.Sqrt(math.Log(float64(n))/float64(ch.visits))
+}
+
+// simulate speelt een rollout vanaf gs en geeft de beloning van myID.
+func (e *Engine) simulate(gs *game.GameState, myID int) float64 {
+	return e.reward(e.rollout(gs), myID)
+}
+
+// rollout speelt vanaf een kopie van gs verder met de rollout-policy, tot
+// het einde van de partij of de zettenlimiet, en geeft de eindstand.
+func (e *Engine) rollout(gs *game.GameState) *game.GameState {
+	sim := gs.Clone()
+	// Adaptieve rollout-limiet: bij weinig kaarten altijd tot GameOver uitspelen.
+	// Bij veel kaarten: max 200 zetten (meer dan genoeg, voorkomt oneindige loops).
+	totalCards := 0
+	for _, h := range sim.Hands {
+		totalCards += h.Count()
+	}
+	maxSteps := 200
+	if totalCards <= 12 {
+		maxSteps = 800 // bij weinig kaarten ALTIJD tot GameOver, geen evalPos-afbreking
+	}
+	for i := 0; i < maxSteps && !sim.GameOver; i++ {
+		moves := sim.GetLegalMoves()
+		if len(moves) == 0 {
+			break
+		}
+		// Greedy/random rollout: QuickEvaluateMove kiest de best-beoordeelde zet
+		// (overshoot-penalty, wild-straf, paar-breek, win-threats). De random
+		// component (smartRandom) zorgt voor diversiteit.
+		// OmniscientMode: 70/30 greedy/random (sterkere heuristiek bij bekende handen)
+		// Play mode: 40/60 greedy/random (meer diversiteit bij onbekende handen)
+		var m game.Move
+		greedyThreshold := 0.4
+		if e.Config.OmniscientMode {
+			greedyThreshold = 0.7
+		}
+		if e.rng.Float64() < greedyThreshold {
+			m = moves[0]
+			bestQScore := -999.0
+			for _, alt := range moves {
+				var sc float64
+				if alt.IsPass {
+					sc = -1.0
+				} else {
+					sc = QuickEvaluateMove(sim, alt).Score
+				}
+				if sc > bestQScore {
+					bestQScore = sc
+					m = alt
+				}
+			}
+		} else {
+			m = e.smartRandom(moves, sim)
+		}
+		sim.ApplyMove(m)
+	}
+	return sim
+}
+
+// reward is de beloning van speler p in de eindstand van een rollout: de
+// plaats als de partij uit is, anders de evaluatie van de stelling.
+func (e *Engine) reward(sim *game.GameState, p int) float64 {
+	if sim.GameOver {
+		return positionScore(sim, p)
+	}
+	return e.evalPos(sim, p)
+}
+
+// rewards geeft de beloning per speler. Zonder max^n is enkel die van myID
+// nodig; de rest blijft 0.
+func (e *Engine) rewards(sim *game.GameState, myID int) []float64 {
+	r := make([]float64, sim.NumPlayers)
+	maxN := e.Config.maxN(sim.NumPlayers)
+	for p := range r {
+		if p == myID || maxN {
+			r[p] = e.reward(sim, p)
+		}
+	}
+	return r
+}
+
+func positionScore(gs *game.GameState, myID int) float64 {
+	numP := gs.NumPlayers
+	if numP <= 1 {
+		return 1.0
+	}
+	rank := gs.PlayerRank(myID)
+	if rank < 0 {
+		return 0.0
+	}
+	return float64(numP-1-rank) / float64(numP-1)
+}
+
+func (e *Engine) smartRandom(moves []game.Move, gs *game.GameState) game.Move {
+	wts := e.Config.Weights
+	handCount := gs.Hands[gs.CurrentTurn].Count()
+
+	// Directe win move altijd spelen
+	for _, m := range moves {
+		if !m.IsPass && len(m.Cards) == handCount {
+			return m
+		}
+	}
+
+	var plays []game.Move
+	var pass game.Move
+	for _, m := range moves {
+		if m.IsPass {
+			pass = m
+		} else {
+			plays = append(plays, m)
+		}
+	}
+	if len(plays) == 0 {
+		return pass
+	}
+
+	curHand := gs.Hands[gs.CurrentTurn]
+	curWilds := curHand.CountRank(cards.RankTwo)    // alleen 2 is wildcard
+	curResets := curHand.CountRank(cards.RankJoker) // joker is reset-kaart
+	specialRatio := 0.0
+	if handCount > 0 {
+		specialRatio = float64(curWilds+curResets) / float64(handCount)
+	}
+
+	// PASS CHANCE
+	passChance := wts.PassBase + specialRatio*wts.PassSpecialFactor
+
+	// Early-game pass bonus: alleen bij 3+ spelers.
+	// In 2-speler is passen altijd gevaarlijk (tegenstander krijgt open ronde), dus nooit verhogen.
+	if handCount >= 8 && gs.ActivePlayerCount() > 2 {
+		for i, h := range gs.Hands {
+			if i != gs.CurrentTurn && !gs.Finished[i] {
+				if handCount <= h.Count() {
+					passChance += wts.EarlyGamePassFactor
+				}
+				break
+			}
+		}
+	}
+
+	// Achterlig-penalty
+	minOpp := 999
+	for i, h := range gs.Hands {
+		if i != gs.CurrentTurn && !gs.Finished[i] && h.Count() < minOpp {
+			minOpp = h.Count()
+		}
+	}
+	if handCount > minOpp {
+		diff := handCount - minOpp
+		switch {
+		case diff >= 7:
+			passChance *= 0.03
+		case diff >= 6:
+			passChance *= 0.08
+		case diff >= 5:
+			passChance *= 0.15
+		case diff >= 4:
+			passChance *= 0.25
+		case diff >= 3:
+			passChance *= 0.42
+		case diff >= 2:
+			passChance *= 0.72
+		}
+	}
+
+	// Late-game threat
+	if minOpp <= 5 && !gs.Round.IsOpen {
+		hasBeater := false
+		for _, c := range curHand.Cards {
+			// Joker (reset) is altijd een "beater" - reset de ronde en open opnieuw
+			if c.IsWild() || c.IsReset() || (!c.IsSpecial() && c.Rank > gs.Round.TableRank) {
+				hasBeater = true
+				break
+			}
+		}
+		if hasBeater {
+			passChance = 0.02
+		}
+	}
+
+	// 2-speler: PASS is in ALLE fases gevaarlijk — tegenstander krijgt vrije open ronde.
+	// Vroeg/midspel: 65% minder passen. Eindspel (<9 kaarten): 90% minder passen.
+	{
+		activePlayers2 := 0
+		for i, h := range gs.Hands {
+			if !gs.Finished[i] && h.Count() > 0 {
+				activePlayers2++
+			}
+		}
+		if activePlayers2 <= 2 {
+			if minOpp < 9 {
+				passChance *= 0.10 // eindspel: vrijwel altijd fataal
+			} else {
+				passChance *= 0.35 // vroeg/midspel: ook gevaarlijk in 2-speler
+			}
+		}
+	}
+
+	if e.rng.Float64() < passChance {
+		return pass
+	}
+
+	// === SPEEL-KEUZE ===
+	acePlayFactor := wts.AcePlayFactor
+	wildPlayFactor := wts.WildPlayFactor
+	synergyPenalty := wts.SynergyPenalty
+
+	weights := make([]float64, len(plays))
+	total := 0.0
+	for i, m := range plays {
+		w := 1.0
+		wilds := 0
+		resets := 0
+		effective := m.EffectiveRank(gs.Round.TableRank)
+
+		for _, c := range m.Cards {
+			if c.IsWild() {
+				wilds++
+			} else if c.IsReset() {
+				resets++
+			}
+		}
+
+		w *= math.Pow(acePlayFactor, float64(resets))
+		w *= math.Pow(wildPlayFactor, float64(wilds))
+
+		// === WILD-VERSPIJLING STRAF ===
+		if wilds > 0 && handCount >= 12 { // nog veel kaarten
+			if effective <= cards.RankFive { // extreem lage beat (zoals 3)
+				w *= 0.09 // bijna onmogelijk maken
+			} else if effective <= cards.RankEight {
+				w *= 0.28
+			} else if effective <= cards.RankTen {
+				w *= 0.40 // nieuw: rank 9-10 ook extra straf
+			} else if effective <= cards.RankQueen {
+				w *= 0.40 // aangescherpt: 0.60→0.40 (Q0 minder aantrekkelijk)
+			}
+			// King+wild: geen extra straf (K is terecht moeilijk anders te spelen)
+		} else if wilds > 0 {
+			if effective <= cards.RankSix {
+				w *= 0.25
+			} else if effective <= cards.RankNine {
+				w *= 0.45 // iets aangescherpt: 0.50→0.45
+			}
+		}
+		// =====================================================
+
+		// Bonus voor dumpen van lage normale kaarten (4 4 krijgt voorkeur).
+		// ONDERDRUK in eindspel (≤2 kaarten over): dan geldt STRATEGIE, niet dumporde.
+		// Bijv. {3,K,K}: KK spelen (cardsAfter=1) is beter dan 3 spelen (cardsAfter=2).
+		cardsAfterM := handCount - len(m.Cards)
+		if wilds == 0 && resets == 0 && len(m.Cards) >= 1 && cardsAfterM > 2 {
+			lowest := m.Cards[0].Rank
+			if lowest <= cards.RankFive {
+				w *= 1.60
+			} else if lowest <= cards.RankEight {
+				w *= 1.30
+			}
+		}
+
+		// Singleton dump vs cluster-breek: bij een single-antwoord op een single tafel,
+		// sterk voorkeur voor LAGE kaarten waarvan je er maar 1 hebt (geïsoleerde kaarten).
+		// Straf als je een paar of triple moet breken voor een enkele kaart.
+		// MAAR: hoge singletons (Aas, Heer) zijn waardevol en moeten bewaard worden!
+		if wilds == 0 && resets == 0 && !gs.Round.IsOpen &&
+			len(m.Cards) == 1 && gs.Round.Count == 1 {
+			rankCount := curHand.CountRank(effective)
+			if rankCount == 1 {
+				if effective >= cards.RankAce {
+					w *= 0.30 // Aas-singleton: BEWAREN, niet dumpen — uniek sterk
+				} else if effective >= cards.RankKing {
+					w *= 0.60 // Heer-singleton: mild bewaren
+				} else {
+					w *= 2.2 // lage singleton: wil je kwijt
+				}
+			} else if rankCount >= 2 {
+				w *= 0.55 // breekt paar of triple: minder wenselijk
+			}
+		}
+
+		if resets > 0 {
+			if gs.Round.IsOpen {
+				w *= 5.5 // joker reset in open ronde: extreem sterk
+			} else {
+				w *= 2.9 // joker reset als antwoord: sterk maar kostbaar
+			}
+		}
+		if resets > 0 && wilds > 0 && gs.Round.IsOpen {
+			w *= 2.1
+		}
+
+		if resets > 0 && wilds > 0 {
+			w *= synergyPenalty
+		}
+
+		for _, c := range m.Cards {
+			// !IsSpecial() includes Ace now (natural card), but Ace has high rank
+			// The formula rewards LOW ranks; Ace (14) gets slight penalty = correct
+			if !c.IsSpecial() {
+				w *= 1.0 + wts.RankPreference*(13.0-float64(c.Rank))
+			}
+		}
+
+		// Response-overshoot: in response-rondes de LAAGSTE winnende zet prefereren.
+		// KK op een X-tafel is verspilling als JJ of QQ ook wint.
+		// Exponentiële afname: elke rank boven het minimum kost ~15% gewicht.
+		if !gs.Round.IsOpen && wilds == 0 && resets == 0 && effective > gs.Round.TableRank {
+			overshoot := float64(effective-gs.Round.TableRank) - 1.0
+			if overshoot > 0 {
+				w *= math.Pow(0.85, overshoot)
+			}
+		}
+
+		// Aas-bescherming: Aas niet verspillen op lage tafel als goedkopere opties bestaan.
+		// De Aas is het ultieme wapen tegen Heer/Aas van de tegenstander.
+		if effective == cards.RankAce && !gs.Round.IsOpen && gs.Round.TableRank <= cards.RankJack {
+			w *= 0.15
+		}
+
+		// Near-win bonus: als je na deze zet ≤3 kaarten overhoudt, verhoog het gewicht
+		// sterk. Dit overstijgt rank-voorkeur en dump-bonussen in de eindspelfase.
+		// Bijv. QQQ (cardsAfter=1) wint terecht van 5 (cardsAfter=3) bij {5,Q,Q,Q}.
+		switch cardsAfterM {
+		case 1:
+			w *= 6.0
+		case 2:
+			w *= 3.0
+		case 3:
+			w *= 1.5
+		}
+
+		weights[i] = w
+		total += w
+	}
+
+	r := e.rng.Float64() * total
+	cum := 0.0
+	for i, w := range weights {
+		cum += w
+		if r <= cum {
+			return plays[i]
+		}
+	}
+	return plays[len(plays)-1]
+}
+
+func (e *Engine) evalPos(gs *game.GameState, myID int) float64 {
+	if gs.Finished[myID] {
+		return positionScore(gs, myID)
+	}
+	myCount := gs.Hands[myID].Count()
+	if myCount == 0 {
+		return 1.0
+	}
+	wts := e.Config.Weights
+	minOpp := 999
+	for i, h := range gs.Hands {
+		if i != myID && !gs.Finished[i] && h.Count() < minOpp {
+			minOpp = h.Count()
+		}
+	}
+	if minOpp == 999 {
+		minOpp = 0
+	}
+	score := 0.5 + float64(minOpp-myCount)*wts.CardDiffWeight
+
+	// Urgentiepenalty: bij 3+ kaarten achter een extra niet-lineaire straf.
+	gap := myCount - minOpp
+	if gap >= 3 {
+		score -= math.Pow(float64(gap), 1.2) * wts.UrgencyPenalty // exponent voor sterker effect bij grote gap
+	}
+
+	hand := gs.Hands[myID]
+	wilds := hand.CountRank(cards.RankTwo)    // alleen 2 is wildcard
+	resets := hand.CountRank(cards.RankJoker) // joker is reset-kaart
+	score += float64(resets) * wts.AceBonus   // joker is nu de reset = voormalige aas-bonus
+	score += float64(wilds) * wts.WildBonus
+	if resets > 0 && wilds > 0 {
+		score += float64(min(resets, wilds)) * wts.SynergyBonus
+	}
+	kings := hand.CountRank(cards.RankKing)
+	if kings > 0 && wilds == 0 && resets == 0 {
+		score -= float64(kings) * wts.KingPenalty
+	}
+	queens := hand.CountRank(cards.RankQueen)
+	if queens > 0 && wilds == 0 && resets == 0 {
+		score -= float64(queens) * wts.QueenPenalty
+	}
+	// Geïsoleerde lage kaarten (3-7): moeilijk te dumpen als single
+	for r := cards.RankThree; r <= cards.RankSeven; r++ {
+		if hand.CountRank(r) == 1 && wilds == 0 {
+			score -= wts.IsolatedLowPenalty
+		}
+	}
+	// Geïsoleerde midden-kaarten (8-X): ook lastig, maar iets minder erg
+	for r := cards.RankEight; r <= cards.RankTen; r++ {
+		if hand.CountRank(r) == 1 && wilds == 0 {
+			score -= wts.IsolatedLowPenalty * 0.5
+		}
+	}
+	for r := cards.RankThree; r <= cards.RankAce; r++ {
+		cnt := hand.CountRank(r)
+		if cnt >= 2 {
+			score += float64(cnt-1) * wts.ClusterBonus
+			// Hoge paren zijn meer waard: een paar Aces is veel sterker dan paar 3-en.
+			// Bonus gebaseerd op rank (3=0.0, Ace=1.0) × 0.04 per extra kaart.
+			rankFactor := float64(r-cards.RankThree) / float64(cards.RankAce-cards.RankThree)
+			score += float64(cnt-1) * rankFactor * 0.04
+		}
+	}
+	// Sluitende combinatie: joker + pair/triple in hand ≤ 5 kaarten = bijna zekere win.
+	// De joker reset de ronde, daarna dump je het pair in 1 zet.
+	if resets > 0 && myCount <= 5 {
+		for r := cards.RankThree; r <= cards.RankAce; r++ {
+			cnt := hand.CountRank(r)
+			if cnt >= 2 && cnt+resets >= myCount {
+				// Joker + pair/triple = alle kaarten in 2 zetten
+				score += 0.15
+				break
+			}
+		}
+	}
+	// Tempo: open ronde op je beurt is een voordeel, maar proportioneel —
+	// niet zo groot dat het kaartdifferentieel overschaduwt.
+	// Oude waarde (4.0x = 0.34 + 0.45 voor Joker = 0.79!) was absurd groot
+	// en maakte dat PASS kunstmatig goed scoorde in MCTS rollouts,
+	// omdat rolloutevaluaties met open-ronde-posities altijd ~1.0 teruggaven.
+	if gs.Round.IsOpen && gs.CurrentTurn == myID {
+		score += wts.TempoBonus * 1.2 // 0.085*1.2 = ~0.10 (was 0.34)
+		if hand.CountResets() > 0 {
+			score += 0.08 // was 0.45 — joker+tempo is sterk maar niet allesbepalend
+		}
+	}
+	if score < 0 {
+		score = 0
+	}
+	if score > 1 {
+		score = 1
+	}
+	return score
+}
@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+
+	"github.com/azen-engine/game"
+	"github.com/azen-engine/knowledge"
+)
+
+// ponderFactor begrenst het vooruitdenken op zoveel keer Config.Iterations,
+// zodat een tegenstander die lang nadenkt de boom niet onbeperkt laat
+// groeien.
+const ponderFactor = 10
+
+// Ponder zoekt op de achtergrond verder terwijl een tegenstander van
+// kt.MyPlayerID aan zet is. De boom heeft dan de zetten van de tegenstander
+// aan de wortel, zodat na zijn echte zet de deelboom al warm is en de
+// volgende BestMove daar verder zoekt (zie Config.ReuseTree).
+//
+// gs en kt worden gekopieerd en mogen meteen weer gewijzigd worden. stop
+// breekt het vooruitdenken af en wacht tot alle workers klaar zijn; roep het
+// aan vóór de volgende BestMove of Ponder. Zonder ReuseTree, als de partij
+// voorbij is of als we zelf aan zet zijn, doet Ponder niets.
+func (e *Engine) Ponder(gs *game.GameState, kt *knowledge.KnowledgeTracker) (stop func()) {
+	if !e.Config.ReuseTree || kt == nil || gs.GameOver || gs.CurrentTurn == kt.MyPlayerID {
+		return func() {}
+	}
+	gs, kt = gs.Clone(), kt.Clone()
+	myID := kt.MyPlayerID
+	numWorkers := max(e.Config.NumWorkers, 1)
+	trees, _ := e.reuseTrees(gs, kt, myID, nil, numWorkers)
+	limit := max(ponderFactor*e.Config.Iterations/numWorkers, 1)
+
+	var halt atomic.Bool
+	var wg sync.WaitGroup
+	for _, t := range trees {
+		workerCfg := e.Config
+		workerCfg.NumWorkers = 1
+		seed := e.rng.Int63()
+		worker := &Engine{Config: workerCfg, rng: rand.New(rand.NewSource(seed)), seed: seed}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < limit && !halt.Load(); i++ {
+				if detGS := worker.determinize(gs, kt); detGS != nil {
+					worker.iterate(t, detGS, myID, nil)
+				}
+			}
+		}()
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			halt.Store(true)
+			wg.Wait()
+			e.keepTree(gs, myID, trees)
+		})
+	}
+}
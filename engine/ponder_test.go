@@ -0,0 +1,28 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPonderHandsTreeToBestMove(t *testing.T) {
+	tt := newEndgame(t, "K K 9 7 5 3", "Q Q 8 6 4 4")
+	e := NewEngine(testConfig(2))
+	tt.play(t, "3")
+	stop := e.Ponder(tt.gs, tt.kt)
+	time.Sleep(100 * time.Millisecond)
+	stop()
+	tt.play(t, "p")
+	if _, eval := e.BestMove(tt.gs, tt.kt); eval.Inherited == 0 {
+		t.Errorf("BestMove na vooruitdenken erfde niets: %v", eval)
+	}
+}
+
+func TestPonderOnOwnTurn(t *testing.T) {
+	tt := newEndgame(t, "K K 9 7 5 3", "Q Q 8 6 4 4")
+	e := NewEngine(testConfig(2))
+	e.Ponder(tt.gs, tt.kt)()
+	if e.tree != nil {
+		t.Error("vooruitdenken op eigen beurt bewaarde een boom")
+	}
+}
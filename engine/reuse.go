@@ -0,0 +1,241 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/game"
+	"github.com/azen-engine/knowledge"
+)
+
+// searchTree is de zoekboom van de vorige BestMove, om in de volgende
+// verder te gebruiken (Config.ReuseTree).
+type searchTree struct {
+	history   []string // zetten tot de wortel, zie historyKey
+	deal      string   // beginhand van myID en dode kaarten, zie dealKey
+	myID      int
+	algorithm Algorithm
+	backprop  Backprop
+	workers   [][]*mctsNode // per worker de wortel per speler (zie newTrees)
+}
+
+// historyKey identificeert een gespeelde zet: speler en waarneembare zet.
+func historyKey(m game.Move) string {
+	return fmt.Sprintf("%d:%s", m.PlayerID, mkey(m))
+}
+
+// dealKey identificeert de deal vanuit myID: per rank de kaarten van zijn
+// beginhand (de huidige hand plus wat hij al speelde) en de dode kaarten.
+// Twee partijen met dezelfde zetten maar een andere deal krijgen zo niet
+// elkaars boom.
+func dealKey(gs *game.GameState, myID int) string {
+	hand := rankCounts(gs.Hands[myID].Cards)
+	for _, m := range gs.History {
+		if m.PlayerID == myID {
+			for _, c := range m.Cards {
+				hand[c.Rank]++
+			}
+		}
+	}
+	return fmt.Sprint(gs.NumPlayers, hand, rankCounts(gs.DeadCards))
+}
+
+// rankCounts telt de kaarten per rank.
+func rankCounts(cc []cards.Card) map[cards.Rank]int {
+	counts := map[cards.Rank]int{}
+	for _, c := range cc {
+		counts[c.Rank]++
+	}
+	return counts
+}
+
+// keepTree bewaart de bomen van de zoektocht van myID op gs voor de
+// volgende BestMove.
+func (e *Engine) keepTree(gs *game.GameState, myID int, workers [][]*mctsNode) {
+	if !e.Config.ReuseTree {
+		e.tree = nil
+		return
+	}
+	history := make([]string, len(gs.History))
+	for i, m := range gs.History {
+		history[i] = historyKey(m)
+	}
+	e.tree = &searchTree{
+		history:   history,
+		deal:      dealKey(gs, myID),
+		myID:      myID,
+		algorithm: e.Config.Algorithm,
+		backprop:  e.Config.Backprop,
+		workers:   workers,
+	}
+}
+
+// reuseTrees geeft per worker de bomen om mee verder te zoeken voor myID,
+// met het aantal geërfde bezoeken. De vorige boom wordt gebruikt als gs er
+// een vervolg van is voor dezelfde speler: de wortel schuift dan op langs de
+// zetten die sindsdien gespeeld zijn (ook passen van tegenstanders), en
+// takken die niet meer mogelijk zijn worden gesnoeid, met hun bezoeken.
+// Anders beginnen alle workers met een nieuwe boom.
+func (e *Engine) reuseTrees(gs *game.GameState, kt *knowledge.KnowledgeTracker, myID int, rootFiltered []game.Move, numWorkers int) ([][]*mctsNode, int) {
+	prev := e.tree
+	e.tree = nil
+	if trees, ok := prev.advance(e, gs, myID, numWorkers); ok {
+		pool := e.unknownCards(gs, kt, myID)
+		mine := rankCounts(gs.Hands[myID].Cards)
+		inherited := 0
+		for _, t := range trees {
+			for _, n := range distinct(t) {
+				if rootFiltered != nil {
+					pruneRoot(n, rootFiltered)
+				}
+				pruneUnavailable(n, myID, pool, mine)
+			}
+			for _, ch := range t[myID].children {
+				inherited += ch.visits
+			}
+		}
+		return trees, inherited
+	}
+	trees := make([][]*mctsNode, numWorkers)
+	for w := range trees {
+		trees[w] = e.newTrees(gs.NumPlayers)
+	}
+	return trees, 0
+}
+
+// advance loopt de bomen af langs de zetten van gs die na de vorige
+// zoektocht gespeeld zijn. ok is false als de boom niet bruikbaar is.
+func (t *searchTree) advance(e *Engine, gs *game.GameState, myID, numWorkers int) (trees [][]*mctsNode, ok bool) {
+	if t == nil || t.myID != myID || len(t.workers) != numWorkers ||
+		t.algorithm != e.Config.Algorithm || t.backprop != e.Config.Backprop ||
+		len(gs.History) < len(t.history) || t.deal != dealKey(gs, myID) {
+		return nil, false
+	}
+	for i, k := range t.history {
+		if historyKey(gs.History[i]) != k {
+			return nil, false
+		}
+	}
+	for _, m := range gs.History[len(t.history):] {
+		key := mkey(m)
+		for _, nodes := range t.workers {
+			next := map[*mctsNode]*mctsNode{}
+			for p, n := range nodes {
+				ch, seen := next[n]
+				if !seen {
+					ch = childByKey(n, key)
+					next[n] = ch
+				}
+				if ch == nil {
+					return nil, false
+				}
+				nodes[p] = ch
+			}
+		}
+	}
+	for _, nodes := range t.workers {
+		for _, n := range distinct(nodes) {
+			n.parent = nil
+			n.playerID = -1
+		}
+	}
+	return t.workers, true
+}
+
+// childByKey geeft het kind van n met zet key (nil = geen).
+func childByKey(n *mctsNode, key string) *mctsNode {
+	for _, ch := range n.children {
+		if mkey(ch.move) == key {
+			return ch
+		}
+	}
+	return nil
+}
+
+// pruneRoot verwijdert de wortelzetten die nu niet meer aangeboden worden.
+func pruneRoot(root *mctsNode, moves []game.Move) {
+	allowed := map[string]bool{}
+	for _, m := range moves {
+		allowed[mkey(m)] = true
+	}
+	kept := root.children[:0]
+	for _, ch := range root.children {
+		if allowed[mkey(ch.move)] {
+			kept = append(kept, ch)
+		} else {
+			discount(root, ch)
+		}
+	}
+	root.children = kept
+}
+
+// pruneUnavailable verwijdert de takken waarin tegenstanders van myID samen
+// meer kaarten van een rank spelen dan er nog onbekend zijn (pool), of myID
+// meer dan hij nog in de hand heeft (mine): die werelden bestaan niet meer
+// na wat sinds de vorige zoektocht gezien is.
+func pruneUnavailable(n *mctsNode, myID int, pool, mine map[cards.Rank]int) {
+	kept := n.children[:0]
+	for _, ch := range n.children {
+		if ch.move.IsPass {
+			pruneUnavailable(ch, myID, pool, mine)
+			kept = append(kept, ch)
+			continue
+		}
+		counts := pool
+		if ch.playerID == myID {
+			counts = mine
+		}
+		fits := true
+		for _, c := range ch.move.Cards {
+			counts[c.Rank]--
+			if counts[c.Rank] < 0 {
+				fits = false
+			}
+		}
+		if fits {
+			pruneUnavailable(ch, myID, pool, mine)
+			kept = append(kept, ch)
+		} else {
+			discount(n, ch)
+		}
+		for _, c := range ch.move.Cards {
+			counts[c.Rank]++
+		}
+	}
+	n.children = kept
+}
+
+// discount haalt de bezoeken en beloningen van het verwijderde kind ch af
+// van n en zijn voorouders.
+func discount(n, ch *mctsNode) {
+	for ; n != nil; n = n.parent {
+		n.visits -= ch.visits
+		n.wins -= ch.wins
+		for p := range n.rewards {
+			if p < len(ch.rewards) {
+				n.rewards[p] -= ch.rewards[p]
+			}
+		}
+	}
+}
+
+// unknownCards telt per rank de kaarten die de tegenstanders van myID nog
+// kunnen hebben: hun echte handen in OmniscientMode, anders wat kt onbekend
+// acht.
+func (e *Engine) unknownCards(gs *game.GameState, kt *knowledge.KnowledgeTracker, myID int) map[cards.Rank]int {
+	pool := map[cards.Rank]int{}
+	if e.Config.OmniscientMode || kt == nil {
+		for p, h := range gs.Hands {
+			if p != myID {
+				for _, c := range h.Cards {
+					pool[c.Rank]++
+				}
+			}
+		}
+		return pool
+	}
+	for _, c := range kt.PossibleOpponentCards() {
+		pool[c.Rank]++
+	}
+	return pool
+}
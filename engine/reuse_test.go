@@ -0,0 +1,101 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/game"
+)
+
+// checkVisits faalt als een knoop minder bezoeken heeft dan zijn kinderen
+// samen, bv. omdat gesnoeide takken niet afgetrokken werden.
+func checkVisits(t *testing.T, n *mctsNode) {
+	t.Helper()
+	sum := 0
+	for _, ch := range n.children {
+		sum += ch.visits
+		checkVisits(t, ch)
+	}
+	if sum > n.visits {
+		t.Errorf("%v: %d bezoeken, kinderen samen %d", n.move, n.visits, sum)
+	}
+}
+
+func TestReuseAfterMoves(t *testing.T) {
+	tt := newEndgame(t, "K K 9 7 5 3", "Q Q 8 6 4 4")
+	e := NewEngine(testConfig(2))
+	m, _ := e.BestMove(tt.gs, tt.kt)
+	tt.play(t, mkey(m))
+	tt.play(t, "p")
+
+	rootFiltered := filterDominatedMoves(tt.gs.GetLegalMoves(), tt.gs.Round)
+	trees, inherited := e.reuseTrees(tt.gs, tt.kt, 0, rootFiltered, 1)
+	if inherited == 0 {
+		t.Fatalf("na %v en pass geen boom overgenomen", m)
+	}
+	root, sum := trees[0][0], 0
+	for _, ch := range root.children {
+		if err := tt.gs.ValidateMove(ch.move); err != nil {
+			t.Errorf("wortelzet %v: %v", ch.move, err)
+		}
+		sum += ch.visits
+	}
+	if sum != inherited {
+		t.Errorf("inherited = %d, wortelzetten samen %d", inherited, sum)
+	}
+	checkVisits(t, root)
+
+	e.keepTree(tt.gs, 0, trees)
+	_, eval := e.BestMove(tt.gs, tt.kt)
+	if eval.Inherited != inherited {
+		t.Errorf("BestMove meldt %d geërfde bezoeken, verwacht %d", eval.Inherited, inherited)
+	}
+}
+
+func TestReuseNeedsSameDeal(t *testing.T) {
+	e := NewEngine(testConfig(2))
+	tt := newEndgame(t, "K K 9 7 5 3", "Q Q 8 6 4 4")
+	e.BestMove(tt.gs, tt.kt)
+	other := newEndgame(t, "K Q 9 7 5 3", "K Q 8 6 4 4")
+	if _, eval := e.BestMove(other.gs, other.kt); eval.Inherited != 0 {
+		t.Errorf("andere deal erfde %d bezoeken", eval.Inherited)
+	}
+	if _, eval := e.BestMove(other.gs, other.kt); eval.Inherited == 0 {
+		t.Error("zelfde deal erfde niets")
+	}
+}
+
+func TestPruneUnavailable(t *testing.T) {
+	node := func(parent *mctsNode, pid int, s string, visits int, wins float64) *mctsNode {
+		cc, err := cards.ParseCards(s)
+		if err != nil {
+			t.Fatalf("ParseCards(%q): %v", s, err)
+		}
+		n := &mctsNode{move: game.Move{PlayerID: pid, Cards: cc}, parent: parent, playerID: pid, visits: visits, wins: wins}
+		parent.children = append(parent.children, n)
+		return n
+	}
+	root := &mctsNode{playerID: -1, visits: 8, wins: 3}
+	node(root, 0, "K K", 4, 2)
+	five := node(root, 0, "5", 3, 1)
+	node(five, 1, "1 1", 2, 1)
+	node(five, 1, "1", 1, 0)
+
+	mine := map[cards.Rank]int{cards.RankKing: 1, cards.RankFive: 1}
+	pool := map[cards.Rank]int{cards.RankAce: 1}
+	pruneUnavailable(root, 0, pool, mine)
+
+	if len(root.children) != 1 || root.children[0] != five {
+		t.Fatalf("wortelzetten %v, verwacht enkel 5", root.children)
+	}
+	if len(five.children) != 1 || len(five.children[0].move.Cards) != 1 {
+		t.Fatalf("antwoorden op 5: %v, verwacht enkel 1", five.children)
+	}
+	if root.visits != 2 || root.wins != 0 || five.visits != 1 || five.wins != 0 {
+		t.Errorf("bezoeken na snoeien: wortel %d/%.0f, 5 %d/%.0f; verwacht 2/0 en 1/0",
+			root.visits, root.wins, five.visits, five.wins)
+	}
+	if mine[cards.RankKing] != 1 || pool[cards.RankAce] != 1 {
+		t.Errorf("tellingen niet hersteld: mine %v, pool %v", mine, pool)
+	}
+}
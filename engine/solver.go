@@ -0,0 +1,149 @@
+package engine
+
+import (
+	"github.com/azen-engine/game"
+)
+
+// findImmediateWin zoekt naar een gegarandeerde winnende zet ("schaakmat")
+// via minimax. Bij ≤12 totale kaarten doorzoekt het ALLE mogelijke antwoorden
+// van de tegenstander. Retourneert de eerste zet van het winnende pad en het
+// aantal eigen beurten tot winst (1 = directe win, 2 = 2-staps combo, etc.).
+func findImmediateWin(gs *game.GameState, knownHands bool) (*game.Move, int) {
+	pid := gs.CurrentTurn
+	handCount := gs.Hands[pid].Count()
+	moves := gs.GetLegalMoves()
+
+	// Snelle check: 1-zet win (geen Clone nodig)
+	for _, m := range moves {
+		if !m.IsPass && len(m.Cards) == handCount {
+			mv := m
+			return &mv, 1
+		}
+	}
+
+	// Minimax forced-win zoektocht is alleen betrouwbaar als alle handen
+	// bekend zijn (OmniscientMode / analyse). In play-mode bevatten de
+	// tegenstander-handen placeholder-kaarten (rank 0) die nooit kunnen
+	// antwoorden, waardoor elke zet vals als gedwongen winst wordt gezien.
+	if !knownHands {
+		return nil, 0
+	}
+
+	// Minimax forced-win zoektocht voor diepere schaakmatten
+	totalCards := 0
+	for _, h := range gs.Hands {
+		totalCards += h.Count()
+	}
+	if totalCards > 12 {
+		return nil, 0
+	}
+	// Adaptieve diepte: bij meer kaarten minder diep zoeken (bredere boom)
+	maxDepth := totalCards * 3
+	if totalCards <= 8 {
+		maxDepth = totalCards * 4
+	}
+	nodes := 0
+
+	// Probeer niet-pass zetten eerst (sneller naar winst)
+	bestDepth := -1
+	var bestMove *game.Move
+	for _, m := range moves {
+		if m.IsPass {
+			continue
+		}
+		sim := gs.Clone()
+		sim.ApplyMove(m)
+		if sim.GameOver && sim.Winner == pid {
+			mv := m
+			return &mv, 1
+		}
+		d := forcedWinDepth(sim, pid, maxDepth-1, &nodes, 500000)
+		if d >= 0 {
+			myMoves := d + 1 // +1 voor deze zet
+			if bestMove == nil || myMoves < bestDepth {
+				bestDepth = myMoves
+				mv := m
+				bestMove = &mv
+			}
+		}
+	}
+	if bestMove != nil {
+		return bestMove, bestDepth
+	}
+	return nil, 0
+}
+
+// forcedWinDepth bepaalt via minimax het aantal eigen beurten tot gedwongen
+// winst. Retourneert -1 als geen forced win, of ≥0 (het aantal resterende
+// eigen beurten). Bij onze beurt telt elke zet als +1. Bij tegenstander telt
+// het niet mee (hun zet kost ons geen beurt), maar we nemen het worst-case pad.
+func forcedWinDepth(gs *game.GameState, myID int, depth int, nodes *int, maxNodes int) int {
+	*nodes++
+	if *nodes > maxNodes {
+		return -1
+	}
+	if gs.GameOver {
+		if gs.Winner == myID {
+			return 0
+		}
+		return -1
+	}
+	if depth <= 0 {
+		return -1
+	}
+
+	moves := gs.GetLegalMoves()
+
+	if gs.CurrentTurn == myID {
+		// Onze beurt: zoek de snelste geforceerde winst
+		best := -1
+		// Probeer speel-zetten eerst
+		for _, m := range moves {
+			if m.IsPass {
+				continue
+			}
+			sim := gs.Clone()
+			sim.ApplyMove(m)
+			d := forcedWinDepth(sim, myID, depth-1, nodes, maxNodes)
+			if d >= 0 {
+				total := d + 1 // +1 want wij speelden een zet
+				if best < 0 || total < best {
+					best = total
+				}
+			}
+		}
+		if best >= 0 {
+			return best
+		}
+		// PASS alleen in response-rondes
+		if !gs.Round.IsOpen {
+			for _, m := range moves {
+				if !m.IsPass {
+					continue
+				}
+				sim := gs.Clone()
+				sim.ApplyMove(m)
+				d := forcedWinDepth(sim, myID, depth-1, nodes, maxNodes)
+				if d >= 0 {
+					return d // PASS kost ons geen "beurt" in de telling
+				}
+			}
+		}
+		return -1
+	}
+
+	// Tegenstander: ALLE zetten moeten naar onze winst leiden, neem worst-case
+	worst := 0
+	for _, m := range moves {
+		sim := gs.Clone()
+		sim.ApplyMove(m)
+		d := forcedWinDepth(sim, myID, depth-1, nodes, maxNodes)
+		if d < 0 {
+			return -1 // tegenstander heeft een ontsnapping
+		}
+		if d > worst {
+			worst = d // worst-case pad (tegenstander vertraagt maximaal)
+		}
+	}
+	return worst
+}
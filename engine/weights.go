@@ -0,0 +1,245 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type Weights struct {
+	AceBonus            float64 `json:"ace_bonus"`
+	WildBonus           float64 `json:"wild_bonus"`
+	SynergyBonus        float64 `json:"synergy_bonus"`
+	CardDiffWeight      float64 `json:"card_diff_weight"`
+	KingPenalty         float64 `json:"king_penalty"`
+	QueenPenalty        float64 `json:"queen_penalty"`
+	IsolatedLowPenalty  float64 `json:"isolated_low_penalty"`
+	ClusterBonus        float64 `json:"cluster_bonus"`
+	TempoBonus          float64 `json:"tempo_bonus"`
+	AcePlayFactor       float64 `json:"ace_play_factor"`
+	WildPlayFactor      float64 `json:"wild_play_factor"`
+	SynergyPenalty      float64 `json:"synergy_penalty"`
+	RankPreference      float64 `json:"rank_preference"`
+	PassBase            float64 `json:"pass_base"`
+	PassSpecialFactor   float64 `json:"pass_special_factor"`
+	PassBehindFactor    float64 `json:"pass_behind_factor"`
+	UrgencyPenalty      float64 `json:"urgency_penalty"`
+	EarlyGamePassFactor float64 `json:"early_game_pass_factor"`
+}
+
+func DefaultWeights() Weights {
+	return Weights{
+		AceBonus:            0.33,
+		WildBonus:           0.38, // verhoogd: 0.21→0.38 (wildcard bewaren loont meer)
+		SynergyBonus:        0.20, // verhoogd: 0.12→0.20 (ace+wild synergie beter gewaardeerd)
+		CardDiffWeight:      0.085,
+		KingPenalty:         0.05,
+		QueenPenalty:        0.032,
+		IsolatedLowPenalty:  0.042,
+		ClusterBonus:        0.038,
+		TempoBonus:          0.085,
+		AcePlayFactor:       0.52,
+		WildPlayFactor:      0.33, // verlaagd: 0.41→0.33 (simulaties spelen wilds spaarzamer)
+		SynergyPenalty:      0.40,
+		RankPreference:      0.11,
+		PassBase:            0.085,
+		PassSpecialFactor:   0.225,
+		PassBehindFactor:    0.31,
+		UrgencyPenalty:      0.09,
+		EarlyGamePassFactor: 0.32, // nieuwe parameter: bonus voor early-game pass bij gelijke handen
+	}
+}
+
+func LoadWeights(path string) (Weights, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return DefaultWeights(), err
+	}
+	w := DefaultWeights()
+	if err := json.Unmarshal(data, &w); err != nil {
+		return DefaultWeights(), err
+	}
+	return w, nil
+}
+
+func SaveWeights(w Weights, path string) error {
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// WeightsEnv is de omgevingsvariabele die het gewichtenbestand kiest als er
+// geen -weights gegeven is: een pad of een profielnaam.
+const WeightsEnv = "AZEN_WEIGHTS"
+
+// legacyWeightsPath is de oude, vaste Termux-locatie. Ze wordt nog gelezen
+// zodat bestaande installaties hun gewichten niet kwijt zijn.
+const legacyWeightsPath = "storage/shared/Documents/weights.json"
+
+// WeightsDir is de map met het standaard gewichtenbestand (weights.json) en
+// de profielen (profiles/<naam>.json): $XDG_CONFIG_HOME/azen, of het
+// equivalent op macOS en Windows.
+func WeightsDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "azen"), nil
+}
+
+// ProfilePath is het bestand van gewichtenprofiel name. Een naam met een
+// map-scheidingsteken of ".." is ongeldig, zodat een profiel altijd in
+// profiles/ blijft.
+func ProfilePath(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return "", fmt.Errorf("ongeldige profielnaam %q", name)
+	}
+	dir, err := WeightsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "profiles", name+".json"), nil
+}
+
+// Profiles geeft de namen van de bewaarde profielen, alfabetisch.
+func Profiles() ([]string, error) {
+	dir, err := WeightsDir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "profiles", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, strings.TrimSuffix(filepath.Base(f), ".json"))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// WeightsSource zegt welk gewichtenbestand actief is en waarom.
+type WeightsSource struct {
+	Path     string   // "" = ingebouwde standaardgewichten
+	Origin   string   // hoe het bestand gekozen werd
+	Warnings []string // afwijkingen van het schema, zie CheckWeights
+}
+
+func (s WeightsSource) String() string {
+	if s.Path == "" {
+		return "ingebouwde standaardgewichten"
+	}
+	return fmt.Sprintf("%s (%s)", s.Path, s.Origin)
+}
+
+// WeightsPath bepaalt het gewichtenbestand voor spec, de waarde van
+// -weights. Een spec zonder map en zonder extensie is een profielnaam. De
+// volgorde is: spec, $AZEN_WEIGHTS, weights.json in WeightsDir en de oude
+// Termux-locatie. Een expliciet gekozen bestand (spec of omgeving) hoeft
+// nog niet te bestaan, zodat de tuner het kan aanmaken; de andere tellen
+// enkel mee als ze bestaan. Zonder bestand is Path leeg.
+func WeightsPath(spec string) (WeightsSource, error) {
+	origin := "opgegeven"
+	if spec == "" {
+		spec, origin = os.Getenv(WeightsEnv), "$"+WeightsEnv
+	}
+	if spec != "" {
+		path := spec
+		if !strings.ContainsAny(spec, `/\`) && filepath.Ext(spec) == "" {
+			var err error
+			if path, err = ProfilePath(spec); err != nil {
+				return WeightsSource{}, err
+			}
+			origin += ", profiel " + spec
+		}
+		return WeightsSource{Path: path, Origin: origin}, nil
+	}
+	if dir, err := WeightsDir(); err == nil {
+		path := filepath.Join(dir, "weights.json")
+		if _, err := os.Stat(path); err == nil {
+			return WeightsSource{Path: path, Origin: "configmap"}, nil
+		}
+	}
+	if _, err := os.Stat(legacyWeightsPath); err == nil {
+		return WeightsSource{
+			Path:     legacyWeightsPath,
+			Origin:   "oude Termux-locatie",
+			Warnings: []string{"verouderde locatie; verplaats het bestand naar weights.json in de configmap of zet " + WeightsEnv},
+		}, nil
+	}
+	return WeightsSource{Origin: "standaard"}, nil
+}
+
+// ResolveWeights laadt de gewichten volgens WeightsPath en controleert het
+// schema. Zonder bestand zijn het de standaardgewichten. Een expliciet
+// gekozen bestand dat niet bestaat is een fout.
+func ResolveWeights(spec string) (Weights, WeightsSource, error) {
+	src, err := WeightsPath(spec)
+	if err != nil || src.Path == "" {
+		return DefaultWeights(), src, err
+	}
+	data, err := os.ReadFile(src.Path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			err = fmt.Errorf("gewichten %s: bestand bestaat niet", src)
+		}
+		return DefaultWeights(), src, err
+	}
+	warnings, err := CheckWeights(data)
+	if err != nil {
+		return DefaultWeights(), src, fmt.Errorf("gewichten %s: %v", src.Path, err)
+	}
+	src.Warnings = append(src.Warnings, warnings...)
+	w := DefaultWeights()
+	if err := json.Unmarshal(data, &w); err != nil {
+		return DefaultWeights(), src, fmt.Errorf("gewichten %s: %v", src.Path, err)
+	}
+	return w, src, nil
+}
+
+// CheckWeights vergelijkt de velden van een gewichtenbestand met Weights.
+// Onbekende velden worden genegeerd en ontbrekende krijgen hun
+// standaardwaarde; beide komen terug als waarschuwing, zodat een tikfout of
+// een bestand van een oudere versie opvalt.
+func CheckWeights(data []byte) ([]string, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	known := map[string]bool{}
+	var missing, unknown []string
+	t := reflect.TypeOf(Weights{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		known[name] = true
+		if _, ok := fields[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	for name := range fields {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	var warnings []string
+	if len(missing) > 0 {
+		warnings = append(warnings, "ontbrekende velden, standaardwaarde gebruikt: "+strings.Join(missing, ", "))
+	}
+	if len(unknown) > 0 {
+		warnings = append(warnings, "onbekende velden genegeerd: "+strings.Join(unknown, ", "))
+	}
+	return warnings, nil
+}
@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeWeights schrijft standaardgewichten met AceBonus ace naar path.
+func writeWeights(t *testing.T, path string, ace float64) {
+	t.Helper()
+	w := DefaultWeights()
+	w.AceBonus = ace
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveWeights(w, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveWeightsOrder(t *testing.T) {
+	// De oude Termux-locatie is relatief aan de huidige map.
+	tmp := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(tmp); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmp, "config"))
+	t.Setenv(WeightsEnv, "")
+
+	check := func(spec string, wantPath string, wantAce float64) {
+		t.Helper()
+		w, src, err := ResolveWeights(spec)
+		if err != nil {
+			t.Fatalf("ResolveWeights(%q): %v", spec, err)
+		}
+		if src.Path != wantPath || w.AceBonus != wantAce {
+			t.Fatalf("ResolveWeights(%q) = %s met ace %v, verwacht %q met ace %v", spec, src, w.AceBonus, wantPath, wantAce)
+		}
+	}
+	check("", "", DefaultWeights().AceBonus)
+
+	writeWeights(t, legacyWeightsPath, 0.1)
+	check("", legacyWeightsPath, 0.1)
+	if _, src, _ := ResolveWeights(""); len(src.Warnings) == 0 {
+		t.Error("geen waarschuwing voor de oude Termux-locatie")
+	}
+
+	config := filepath.Join(tmp, "config", "azen", "weights.json")
+	writeWeights(t, config, 0.2)
+	check("", config, 0.2)
+
+	env := filepath.Join(tmp, "env.json")
+	writeWeights(t, env, 0.3)
+	t.Setenv(WeightsEnv, env)
+	check("", env, 0.3)
+
+	profile := filepath.Join(tmp, "config", "azen", "profiles", "snel.json")
+	writeWeights(t, profile, 0.4)
+	check("snel", profile, 0.4)
+
+	if _, _, err := ResolveWeights(filepath.Join(tmp, "ontbreekt.json")); err == nil {
+		t.Error("geen fout voor een gekozen bestand dat niet bestaat")
+	}
+}
+
+func TestProfilePathRejectsDirectories(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	for _, name := range []string{"snel", "v2-snel", "", "../weights", "a/b", `a\b`, "..", "a..b"} {
+		path, err := ProfilePath(name)
+		valid := name == "snel" || name == "v2-snel"
+		if (err == nil) != valid {
+			t.Errorf("ProfilePath(%q) = %q, %v", name, path, err)
+		}
+		if valid && !strings.HasSuffix(path, filepath.Join("profiles", name+".json")) {
+			t.Errorf("ProfilePath(%q) = %q", name, path)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package game
+
+import (
+	"math/rand"
+
+	"github.com/azen-engine/cards"
+)
+
+// Deal is een verdeling van de kaarten: de starthanden, de dode kaarten en
+// de startspeler. Dezelfde deal kan meermaals gespeeld worden, bv. met de
+// spelers van plaats gewisseld (duplicate), telkens met exact dezelfde
+// kaarten.
+type Deal struct {
+	Hands [][]cards.Card
+	Dead  []cards.Card
+	Start int
+}
+
+// NewDeal schudt en deelt zoals NewGame, met een willekeurige startspeler.
+func NewDeal(numPlayers int, rng *rand.Rand) Deal {
+	start := rng.Intn(numPlayers)
+	gs := NewGame(numPlayers, rng, start)
+	d := Deal{Dead: gs.DeadCards, Start: start}
+	for _, h := range gs.Hands {
+		d.Hands = append(d.Hands, h.Cards)
+	}
+	return d
+}
+
+// NewGame begint een partij op deze deal. De partij krijgt eigen kopieën
+// van de kaarten; de deal zelf blijft onveranderd.
+func (d Deal) NewGame() *GameState {
+	hands := make([]*cards.Hand, len(d.Hands))
+	for i, h := range d.Hands {
+		hands[i] = cards.NewHand(h)
+	}
+	dead := append([]cards.Card(nil), d.Dead...)
+	return NewGameWithHands(hands, dead, d.Start)
+}
@@ -0,0 +1,64 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/azen-engine/cards"
+)
+
+type Move struct {
+	PlayerID int
+	Cards    []cards.Card
+	IsPass   bool
+}
+
+func PassMove(playerID int) Move {
+	return Move{PlayerID: playerID, IsPass: true}
+}
+
+func (m Move) String() string {
+	if m.IsPass {
+		return fmt.Sprintf("P%d: PASS", m.PlayerID)
+	}
+	return fmt.Sprintf("P%d: %s", m.PlayerID, cards.CardsToString(m.Cards))
+}
+
+func (m Move) ContainsReset() bool {
+	for _, c := range m.Cards {
+		if c.IsReset() {
+			return true
+		}
+	}
+	return false
+}
+
+func (m Move) EffectiveRank(tableRank cards.Rank) cards.Rank {
+	best := cards.Rank(0)
+	for _, c := range m.Cards {
+		if !c.IsSpecial() && c.Rank > best {
+			best = c.Rank
+		}
+	}
+	if best == 0 {
+		return tableRank
+	}
+	return best
+}
+
+func FormatMove(m Move) string {
+	if m.IsPass {
+		return "PASS"
+	}
+	sorted := make([]cards.Card, len(m.Cards))
+	copy(sorted, m.Cards)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Rank < sorted[j].Rank
+	})
+	parts := make([]string, len(sorted))
+	for i, c := range sorted {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,256 @@
+package game
+
+import (
+	"strings"
+
+	"github.com/azen-engine/cards"
+)
+
+func (gs *GameState) GetLegalMoves() []Move {
+	if gs.GameOver {
+		return nil
+	}
+	pid := gs.CurrentTurn
+	hand := gs.Hands[pid]
+	moves := []Move{PassMove(pid)}
+	if gs.Round.IsOpen {
+		moves = append(moves, genOpenMoves(pid, hand)...)
+	} else {
+		moves = append(moves, genResponseMoves(pid, hand, gs.Round)...)
+	}
+	return moves
+}
+
+func genOpenMoves(pid int, hand *cards.Hand) []Move {
+	var moves []Move
+	byRank := map[cards.Rank][]cards.Card{}
+	for _, c := range hand.Cards {
+		byRank[c.Rank] = append(byRank[c.Rank], c)
+	}
+	wilds := gatherWilds(hand)
+	resets := gatherResets(hand)
+
+	for _, rank := range cards.NormalRanks() {
+		normals := byRank[rank]
+		if len(normals) == 0 {
+			continue
+		}
+		// Een open mag elk aantal kaarten tellen. Kaarten van dezelfde rank
+		// zijn uitwisselbaar (zetten worden op rank vergeleken), dus per
+		// aantal normale kaarten en wildcards volstaat één combinatie; zo
+		// blijft ook een hand met veel tweeën goedkoop.
+		for total := 1; total <= len(normals)+len(wilds); total++ {
+			for numNorm := imax(1, total-len(wilds)); numNorm <= imin(len(normals), total); numNorm++ {
+				numWild := total - numNorm
+				merged := append(append([]cards.Card{}, normals[:numNorm]...), wilds[:numWild]...)
+				moves = append(moves, Move{PlayerID: pid, Cards: merged})
+			}
+		}
+	}
+
+	for total := 1; total <= len(wilds); total++ {
+		moves = append(moves, Move{PlayerID: pid, Cards: append([]cards.Card{}, wilds[:total]...)})
+	}
+
+	moves = append(moves, genResetMoves(pid, resets, wilds)...)
+	return dedup(moves)
+}
+
+func genResponseMoves(pid int, hand *cards.Hand, round RoundState) []Move {
+	var moves []Move
+	need := round.Count
+	tableRank := round.TableRank
+	wilds := gatherWilds(hand)
+	resets := gatherResets(hand)
+
+	for _, rank := range cards.NormalRanks() {
+		if rank <= tableRank {
+			continue
+		}
+		normals := hand.GetByRank(rank)
+		if len(normals) == 0 {
+			continue
+		}
+		for numNorm := imax(1, need-len(wilds)); numNorm <= imin(len(normals), need); numNorm++ {
+			numWild := need - numNorm
+			if numWild < 0 || numWild > len(wilds) {
+				continue
+			}
+			nCombos := combos(normals, numNorm)
+			if numWild == 0 {
+				for _, nc := range nCombos {
+					moves = append(moves, Move{PlayerID: pid, Cards: nc})
+				}
+			} else {
+				wCombos := combos(wilds, numWild)
+				for _, nc := range nCombos {
+					for _, wc := range wCombos {
+						merged := append(append([]cards.Card{}, nc...), wc...)
+						moves = append(moves, Move{PlayerID: pid, Cards: merged})
+					}
+				}
+			}
+		}
+	}
+
+	if len(wilds) >= need {
+		for _, wc := range combos(wilds, need) {
+			moves = append(moves, Move{PlayerID: pid, Cards: wc})
+		}
+	}
+
+	moves = append(moves, genResetResponseMoves(pid, resets, wilds, need)...)
+	return dedup(moves)
+}
+
+func genResetMoves(pid int, resets, wilds []cards.Card) []Move {
+	var moves []Move
+	for numReset := 1; numReset <= len(resets); numReset++ {
+		for numWild := 0; numWild <= len(wilds); numWild++ {
+			merged := append(append([]cards.Card{}, resets[:numReset]...), wilds[:numWild]...)
+			moves = append(moves, Move{PlayerID: pid, Cards: merged})
+		}
+	}
+	return moves
+}
+
+func genResetResponseMoves(pid int, resets, wilds []cards.Card, need int) []Move {
+	var moves []Move
+	for numReset := 1; numReset <= imin(len(resets), need); numReset++ {
+		numWild := need - numReset
+		if numWild > len(wilds) {
+			continue
+		}
+		rCombos := combos(resets, numReset)
+		if numWild == 0 {
+			for _, rc := range rCombos {
+				moves = append(moves, Move{PlayerID: pid, Cards: rc})
+			}
+		} else {
+			wCombos := combos(wilds, numWild)
+			for _, rc := range rCombos {
+				for _, wc := range wCombos {
+					merged := append(append([]cards.Card{}, rc...), wc...)
+					moves = append(moves, Move{PlayerID: pid, Cards: merged})
+				}
+			}
+		}
+	}
+	return moves
+}
+
+func gatherSpecials(hand *cards.Hand) []cards.Card {
+	var sp []cards.Card
+	for _, c := range hand.Cards {
+		if c.IsSpecial() {
+			sp = append(sp, c)
+		}
+	}
+	return sp
+}
+
+func gatherWilds(hand *cards.Hand) []cards.Card {
+	var wilds []cards.Card
+	for _, c := range hand.Cards {
+		if c.IsWild() {
+			wilds = append(wilds, c)
+		}
+	}
+	return wilds
+}
+
+func gatherResets(hand *cards.Hand) []cards.Card {
+	var resets []cards.Card
+	for _, c := range hand.Cards {
+		if c.IsReset() {
+			resets = append(resets, c)
+		}
+	}
+	return resets
+}
+
+func combos(arr []cards.Card, k int) [][]cards.Card {
+	if k <= 0 || k > len(arr) {
+		if k == 0 {
+			return [][]cards.Card{{}}
+		}
+		return nil
+	}
+	var result [][]cards.Card
+	var helper func(start int, curr []cards.Card)
+	helper = func(start int, curr []cards.Card) {
+		if len(curr) == k {
+			c := make([]cards.Card, k)
+			copy(c, curr)
+			result = append(result, c)
+			return
+		}
+		remaining := k - len(curr)
+		for i := start; i <= len(arr)-remaining; i++ {
+			helper(i+1, append(curr, arr[i]))
+		}
+	}
+	helper(0, nil)
+	return result
+}
+
+func dedup(moves []Move) []Move {
+	seen := map[string]bool{}
+	var result []Move
+	for _, m := range moves {
+		key := moveKey(m)
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+func moveKey(m Move) string {
+	if m.IsPass {
+		return "PASS"
+	}
+	sorted := make([]cards.Card, len(m.Cards))
+	copy(sorted, m.Cards)
+	for i := 0; i < len(sorted); i++ {
+		for j := i + 1; j < len(sorted); j++ {
+			if sorted[i].Rank > sorted[j].Rank || (sorted[i].Rank == sorted[j].Rank && sorted[i].Suit > sorted[j].Suit) {
+				sorted[i], sorted[j] = sorted[j], sorted[i]
+			}
+		}
+	}
+	parts := make([]string, len(sorted))
+	for i, c := range sorted {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, "|")
+}
+
+func MovesEqual(a, b Move) bool {
+	if a.IsPass && b.IsPass {
+		return true
+	}
+	if a.IsPass != b.IsPass {
+		return false
+	}
+	if len(a.Cards) != len(b.Cards) {
+		return false
+	}
+	return moveKey(a) == moveKey(b)
+}
+
+// imin/imax vermijden conflict met Go 1.21+ builtins min/max
+func imin(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func imax(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
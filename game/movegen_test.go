@@ -0,0 +1,233 @@
+package game
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/azen-engine/cards"
+)
+
+// moveSet zet zetten om naar gesorteerde FormatMove-strings.
+func moveSet(moves []Move) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, m := range moves {
+		s := FormatMove(m)
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func wantSet(t *testing.T, got []Move, want ...string) {
+	t.Helper()
+	sort.Strings(want)
+	gs := moveSet(got)
+	if strings.Join(gs, ", ") != strings.Join(want, ", ") {
+		t.Errorf("zetten:\n got  %q\n want %q", gs, want)
+	}
+}
+
+func TestGenOpenMoves(t *testing.T) {
+	hand := cards.NewHand(cc(t, "5 5 2"))
+	wantSet(t, genOpenMoves(0, hand), "5", "5 5", "5 2", "5 5 2", "2")
+}
+
+func TestGenOpenMovesWithReset(t *testing.T) {
+	hand := cards.NewHand(cc(t, "K 2 0"))
+	wantSet(t, genOpenMoves(0, hand), "K", "K 2", "2", "0", "2 0")
+}
+
+func TestGenOpenMovesAllSizes(t *testing.T) {
+	hand := cards.NewHand(cc(t, "4 4 4 4 2 2 2 2"))
+	sizes := map[int]int{}
+	for _, m := range genOpenMoves(0, hand) {
+		sizes[len(m.Cards)]++
+	}
+	// n kaarten: elke verdeling over vieren (minstens één) en tweeën, plus
+	// n tweeën alleen.
+	want := map[int]int{1: 2, 2: 3, 3: 4, 4: 5, 5: 4, 6: 3, 7: 2, 8: 1}
+	for n, w := range want {
+		if sizes[n] != w {
+			t.Errorf("%d zetten van %d kaarten, want %d", sizes[n], n, w)
+		}
+	}
+}
+
+func TestGenResponseMoves(t *testing.T) {
+	hand := cards.NewHand(cc(t, "5 8 8 9 2 0"))
+	round := RoundState{Count: 2, TableRank: cards.RankSeven}
+	wantSet(t, genResponseMoves(0, hand, round), "8 8", "8 2", "9 2", "2 0")
+}
+
+func TestGenResponseMovesOnlyWilds(t *testing.T) {
+	hand := cards.NewHand(cc(t, "3 2 2"))
+	round := RoundState{Count: 2, TableRank: cards.RankAce}
+	wantSet(t, genResponseMoves(0, hand, round), "2 2")
+}
+
+func TestGenResponseMovesNone(t *testing.T) {
+	hand := cards.NewHand(cc(t, "3 4 K"))
+	round := RoundState{Count: 1, TableRank: cards.RankAce}
+	if moves := genResponseMoves(0, hand, round); len(moves) != 0 {
+		t.Errorf("verwacht geen zetten, kreeg %q", moveSet(moves))
+	}
+}
+
+func TestGenResetMoves(t *testing.T) {
+	resets := cc(t, "0 0")
+	wilds := cc(t, "2")
+	wantSet(t, genResetMoves(0, resets, wilds), "0", "2 0", "0 0", "2 0 0")
+}
+
+func TestGenResetResponseMoves(t *testing.T) {
+	resets := cc(t, "0 0")
+	wilds := cc(t, "2")
+	wantSet(t, genResetResponseMoves(0, resets, wilds, 3), "2 0 0")
+	wantSet(t, genResetResponseMoves(0, resets, wilds, 1), "0")
+}
+
+func TestGetLegalMovesAlwaysHasPass(t *testing.T) {
+	gs := newTestGame(t, "3 4", "5 6")
+	moves := gs.GetLegalMoves()
+	if len(moves) == 0 || !moves[0].IsPass || moves[0].PlayerID != 0 {
+		t.Fatalf("eerste zet moet pass van speler 0 zijn: %v", moves)
+	}
+}
+
+// ─── Property tests ──────────────────────────────────────────────────────────
+
+// randomGame speelt een partij met willekeurige legale zetten en roept check
+// na elke zet aan. Geeft het aantal zetten terug.
+func randomGame(t *testing.T, numPlayers int, seed int64, check func(gs *GameState, m Move)) int {
+	t.Helper()
+	rng := rand.New(rand.NewSource(seed))
+	gs := NewGame(numPlayers, rng, rng.Intn(numPlayers))
+	const maxMoves = 20000
+	n := 0
+	for !gs.GameOver {
+		if n >= maxMoves {
+			t.Fatalf("seed %d: partij na %d zetten niet afgelopen", seed, maxMoves)
+		}
+		moves := gs.GetLegalMoves()
+		if len(moves) == 0 {
+			t.Fatalf("seed %d: geen legale zetten voor speler %d", seed, gs.CurrentTurn)
+		}
+		m := moves[rng.Intn(len(moves))]
+		gs.ApplyMove(m)
+		n++
+		check(gs, m)
+	}
+	return n
+}
+
+func TestPropertyLegalMovesValidate(t *testing.T) {
+	for players := 2; players <= 4; players++ {
+		for seed := int64(1); seed <= 20; seed++ {
+			rng := rand.New(rand.NewSource(seed))
+			gs := NewGame(players, rng, 0)
+			for step := 0; step < 2000 && !gs.GameOver; step++ {
+				moves := gs.GetLegalMoves()
+				for _, m := range moves {
+					if m.PlayerID != gs.CurrentTurn {
+						t.Fatalf("%dp seed %d: zet %s voor speler %d, beurt is aan %d", players, seed, m, m.PlayerID, gs.CurrentTurn)
+					}
+					if err := gs.ValidateMove(m); err != nil {
+						t.Fatalf("%dp seed %d: legale zet %s valideert niet: %v (ronde %+v)", players, seed, m, err, gs.Round)
+					}
+				}
+				gs.ApplyMove(moves[rng.Intn(len(moves))])
+			}
+		}
+	}
+}
+
+func TestPropertyLegalMovesUnique(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	gs := NewGame(3, rng, 0)
+	for !gs.GameOver {
+		moves := gs.GetLegalMoves()
+		seen := map[string]bool{}
+		for _, m := range moves {
+			k := moveKey(m)
+			if seen[k] {
+				t.Fatalf("dubbele zet %s", m)
+			}
+			seen[k] = true
+		}
+		gs.ApplyMove(moves[rng.Intn(len(moves))])
+	}
+}
+
+// Elke kaart zit op elk moment in precies één van: een hand, Played of
+// DeadCards.
+func TestPropertyCardConservation(t *testing.T) {
+	for players := 2; players <= 4; players++ {
+		deck := cards.NewDeck()
+		if players == 4 {
+			deck = cards.NewMultiDeck(2)
+		}
+		want := map[cards.Rank]int{}
+		for _, c := range deck.Cards {
+			want[c.Rank]++
+		}
+		for seed := int64(1); seed <= 20; seed++ {
+			randomGame(t, players, seed, func(gs *GameState, m Move) {
+				got := map[cards.Rank]int{}
+				for _, h := range gs.Hands {
+					for _, c := range h.Cards {
+						got[c.Rank]++
+					}
+				}
+				for _, c := range gs.Played {
+					got[c.Rank]++
+				}
+				for _, c := range gs.DeadCards {
+					got[c.Rank]++
+				}
+				for r, n := range want {
+					if got[r] != n {
+						t.Fatalf("%dp seed %d na %s: rank %s komt %d keer voor, want %d", players, seed, m, cards.Card{Rank: r}, got[r], n)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestPropertyGamesTerminate(t *testing.T) {
+	for players := 2; players <= 4; players++ {
+		for seed := int64(1); seed <= 50; seed++ {
+			randomGame(t, players, seed, func(gs *GameState, m Move) {})
+		}
+	}
+}
+
+// Aan het einde staat iedereen precies één keer in de ranking en is de
+// winnaar de eerste.
+func TestPropertyFinalRanking(t *testing.T) {
+	for players := 2; players <= 4; players++ {
+		for seed := int64(1); seed <= 20; seed++ {
+			var final *GameState
+			randomGame(t, players, seed, func(gs *GameState, m Move) { final = gs })
+			if len(final.Ranking) != players {
+				t.Fatalf("%dp seed %d: ranking %v", players, seed, final.Ranking)
+			}
+			seen := map[int]bool{}
+			for _, p := range final.Ranking {
+				if seen[p] || p < 0 || p >= players {
+					t.Fatalf("%dp seed %d: ongeldige ranking %v", players, seed, final.Ranking)
+				}
+				seen[p] = true
+			}
+			if final.Winner != final.Ranking[0] {
+				t.Errorf("%dp seed %d: winnaar %d, ranking %v", players, seed, final.Winner, final.Ranking)
+			}
+		}
+	}
+}
@@ -0,0 +1,325 @@
+// Package game implementeert de spelregels van AZEN: de spelstatus, het
+// valideren en toepassen van zetten en het genereren van legale zetten.
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/azen-engine/cards"
+)
+
+type RoundState struct {
+	Count        int
+	TableRank    cards.Rank
+	IsOpen       bool
+	LastPlayerID int
+	ConsecPasses int
+}
+
+type GameState struct {
+	NumPlayers  int
+	Hands       []*cards.Hand
+	CurrentTurn int
+	Round       RoundState
+	Played      []cards.Card
+	History     []Move
+	GameOver    bool
+	Winner      int
+	Ranking     []int
+	Finished    []bool
+	DeadCards   []cards.Card
+}
+
+func NewGame(numPlayers int, rng *rand.Rand, startPlayer int) *GameState {
+	numDecks := 1
+	if numPlayers == 4 {
+		numDecks = 2
+	}
+	var deck *cards.Deck
+	if numDecks == 1 {
+		deck = cards.NewDeck()
+	} else {
+		deck = cards.NewMultiDeck(numDecks)
+	}
+	deck.Shuffle(rng)
+	hands, remaining := deck.Deal(numPlayers, 18)
+	return &GameState{
+		NumPlayers:  numPlayers,
+		Hands:       hands,
+		CurrentTurn: startPlayer,
+		Round:       RoundState{IsOpen: true},
+		Winner:      -1,
+		Finished:    make([]bool, numPlayers),
+		DeadCards:   remaining,
+	}
+}
+
+func NewGameWithHands(hands []*cards.Hand, dead []cards.Card, startPlayer int) *GameState {
+	return &GameState{
+		NumPlayers:  len(hands),
+		Hands:       hands,
+		CurrentTurn: startPlayer,
+		Round:       RoundState{IsOpen: true},
+		Winner:      -1,
+		Finished:    make([]bool, len(hands)),
+		DeadCards:   dead,
+	}
+}
+
+func (gs *GameState) Clone() *GameState {
+	n := &GameState{
+		NumPlayers:  gs.NumPlayers,
+		CurrentTurn: gs.CurrentTurn,
+		Round:       gs.Round,
+		GameOver:    gs.GameOver,
+		Winner:      gs.Winner,
+	}
+	n.Hands = make([]*cards.Hand, len(gs.Hands))
+	for i, h := range gs.Hands {
+		n.Hands[i] = h.Clone()
+	}
+	n.Played = make([]cards.Card, len(gs.Played))
+	copy(n.Played, gs.Played)
+	n.History = make([]Move, len(gs.History))
+	copy(n.History, gs.History)
+	n.DeadCards = make([]cards.Card, len(gs.DeadCards))
+	copy(n.DeadCards, gs.DeadCards)
+	n.Finished = make([]bool, len(gs.Finished))
+	copy(n.Finished, gs.Finished)
+	n.Ranking = make([]int, len(gs.Ranking))
+	copy(n.Ranking, gs.Ranking)
+	return n
+}
+
+func (gs *GameState) ActivePlayerCount() int {
+	count := 0
+	for _, f := range gs.Finished {
+		if !f {
+			count++
+		}
+	}
+	return count
+}
+
+func (gs *GameState) nextActiveTurn(fromPID int) int {
+	for i := 1; i <= gs.NumPlayers; i++ {
+		next := (fromPID + i) % gs.NumPlayers
+		if !gs.Finished[next] {
+			return next
+		}
+	}
+	return fromPID
+}
+
+func (gs *GameState) passThreshold() int {
+	active := gs.ActivePlayerCount()
+	if gs.Finished[gs.Round.LastPlayerID] {
+		return active
+	}
+	return active - 1
+}
+
+func (gs *GameState) PlayerRank(pid int) int {
+	for i, p := range gs.Ranking {
+		if p == pid {
+			return i
+		}
+	}
+	return -1
+}
+
+func (gs *GameState) finishPlayer(pid int) bool {
+	gs.Finished[pid] = true
+	gs.Ranking = append(gs.Ranking, pid)
+	if gs.Winner == -1 {
+		gs.Winner = pid
+	}
+	if gs.ActivePlayerCount() <= 1 {
+		for i, f := range gs.Finished {
+			if !f {
+				gs.Ranking = append(gs.Ranking, i)
+				gs.Finished[i] = true
+				break
+			}
+		}
+		gs.GameOver = true
+		return true
+	}
+	return false
+}
+
+func (gs *GameState) ValidateMove(m Move) error {
+	if gs.GameOver {
+		return fmt.Errorf("game is over")
+	}
+	if m.PlayerID != gs.CurrentTurn {
+		return fmt.Errorf("not player %d's turn (current: %d)", m.PlayerID, gs.CurrentTurn)
+	}
+	if m.IsPass {
+		return nil
+	}
+	if len(m.Cards) == 0 {
+		return fmt.Errorf("must play at least one card (or pass)")
+	}
+	hand := gs.Hands[m.PlayerID]
+	tmpHand := hand.Clone()
+	if err := tmpHand.Remove(m.Cards); err != nil {
+		return fmt.Errorf("cards not in hand: %v", err)
+	}
+	if gs.Round.IsOpen {
+		return gs.validateOpenPlay(m)
+	}
+	return gs.validateResponsePlay(m)
+}
+
+func (gs *GameState) validateOpenPlay(m Move) error {
+	hasReset, hasNormal, normalRank, err := classifyCards(m.Cards)
+	if err != nil {
+		return err
+	}
+	if hasReset && hasNormal {
+		return fmt.Errorf("een joker (0) mag enkel samen met een 2 of andere joker gespeeld worden, niet met normale kaarten")
+	}
+	_ = normalRank
+	return nil
+}
+
+func (gs *GameState) validateResponsePlay(m Move) error {
+	hasReset, hasNormal, normalRank, err := classifyCards(m.Cards)
+	if err != nil {
+		return err
+	}
+	if hasReset && hasNormal {
+		return fmt.Errorf("een joker (0) mag enkel samen met een 2 of andere joker gespeeld worden, niet met normale kaarten")
+	}
+	if len(m.Cards) != gs.Round.Count {
+		return fmt.Errorf("moet exact %d kaart(en) spelen (gespeeld: %d)", gs.Round.Count, len(m.Cards))
+	}
+	if normalRank != 0 && normalRank <= gs.Round.TableRank {
+		return fmt.Errorf("rank %d verslaat tafel-rank %d niet", normalRank, gs.Round.TableRank)
+	}
+	return nil
+}
+
+func classifyCards(cc []cards.Card) (hasReset bool, hasNormal bool, normalRank cards.Rank, err error) {
+	for _, c := range cc {
+		if c.IsReset() {
+			hasReset = true
+		} else if c.IsWild() {
+			// wildcards zijn neutraal
+		} else {
+			// Normale kaarten: 3..K en Aas (1 is nu de hoogste naturelle rank)
+			hasNormal = true
+			if normalRank == 0 {
+				normalRank = c.Rank
+			} else if c.Rank != normalRank {
+				err = fmt.Errorf("alle normale kaarten moeten dezelfde rank hebben")
+				return
+			}
+		}
+	}
+	return
+}
+
+func (gs *GameState) ApplyMove(m Move) {
+	gs.History = append(gs.History, m)
+	pid := m.PlayerID
+
+	if m.IsPass {
+		gs.Round.ConsecPasses++
+		if gs.Round.ConsecPasses >= gs.passThreshold() {
+			lastPID := gs.Round.LastPlayerID
+			gs.Round = RoundState{IsOpen: true, LastPlayerID: lastPID}
+			if gs.Finished[lastPID] {
+				gs.CurrentTurn = gs.nextActiveTurn(lastPID)
+			} else {
+				gs.CurrentTurn = lastPID
+			}
+			return
+		}
+		gs.CurrentTurn = gs.nextActiveTurn(pid)
+		return
+	}
+
+	gs.Hands[pid].Remove(m.Cards)
+	gs.Played = append(gs.Played, m.Cards...)
+
+	if gs.Hands[pid].IsEmpty() {
+		if gs.finishPlayer(pid) {
+			return
+		}
+		if m.ContainsReset() {
+			gs.Round = RoundState{IsOpen: true, LastPlayerID: pid}
+			gs.CurrentTurn = gs.nextActiveTurn(pid)
+		} else {
+			gs.Round = RoundState{
+				Count:        len(m.Cards),
+				TableRank:    m.EffectiveRank(gs.Round.TableRank),
+				IsOpen:       false,
+				LastPlayerID: pid,
+				ConsecPasses: 0,
+			}
+			gs.CurrentTurn = gs.nextActiveTurn(pid)
+		}
+		return
+	}
+
+	if m.ContainsReset() {
+		gs.Round = RoundState{IsOpen: true, LastPlayerID: pid}
+		gs.CurrentTurn = pid
+		return
+	}
+
+	effectiveRank := m.EffectiveRank(gs.Round.TableRank)
+	if gs.Round.IsOpen {
+		gs.Round = RoundState{
+			Count:        len(m.Cards),
+			TableRank:    effectiveRank,
+			IsOpen:       false,
+			LastPlayerID: pid,
+			ConsecPasses: 0,
+		}
+	} else {
+		gs.Round.TableRank = effectiveRank
+		gs.Round.LastPlayerID = pid
+		gs.Round.ConsecPasses = 0
+	}
+	gs.CurrentTurn = gs.nextActiveTurn(pid)
+}
+
+func (gs *GameState) StatusString() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("=== AZEN (%d players) ===\n", gs.NumPlayers))
+	medals := []string{"🥇", "🥈", "🥉", "4e"}
+	for i, h := range gs.Hands {
+		marker := "  "
+		switch {
+		case gs.Finished[i]:
+			rank := gs.PlayerRank(i)
+			if rank >= 0 && rank < len(medals) {
+				marker = medals[rank] + " "
+			} else {
+				marker = "✓  "
+			}
+		case i == gs.CurrentTurn:
+			marker = "▶  "
+		}
+		sb.WriteString(fmt.Sprintf("%sP%d [%2d cards]: %s\n", marker, i, h.Count(), h))
+	}
+	if gs.Round.IsOpen {
+		sb.WriteString("Round: OPEN (play anything)\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("Round: %dx cards, beat rank %s\n", gs.Round.Count, fmtRank(gs.Round.TableRank)))
+	}
+	if gs.GameOver && len(gs.Ranking) > 0 {
+		sb.WriteString(fmt.Sprintf("🏆 Player %d WINS!\n", gs.Ranking[0]))
+		if len(gs.Ranking) == gs.NumPlayers {
+			sb.WriteString(fmt.Sprintf("💀 Player %d verliest.\n", gs.Ranking[len(gs.Ranking)-1]))
+		}
+	}
+	return sb.String()
+}
+
+func fmtRank(r cards.Rank) string { return (cards.Card{Rank: r}).RankStr() }
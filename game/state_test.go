@@ -0,0 +1,329 @@
+package game
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/azen-engine/cards"
+)
+
+// cc parst kaartnotatie en faalt de test bij een fout.
+func cc(t testing.TB, s string) []cards.Card {
+	t.Helper()
+	c, err := cards.ParseCards(s)
+	if err != nil {
+		t.Fatalf("ParseCards(%q): %v", s, err)
+	}
+	return c
+}
+
+// newTestGame maakt een partij met de gegeven handen; speler 0 begint.
+func newTestGame(t testing.TB, hands ...string) *GameState {
+	t.Helper()
+	hs := make([]*cards.Hand, len(hands))
+	for i, h := range hands {
+		hs[i] = cards.NewHand(cc(t, h))
+	}
+	return NewGameWithHands(hs, nil, 0)
+}
+
+// mv bouwt een zet; "p" is een pass.
+func mv(t testing.TB, pid int, s string) Move {
+	t.Helper()
+	if s == "p" {
+		return PassMove(pid)
+	}
+	return Move{PlayerID: pid, Cards: cc(t, s)}
+}
+
+// play valideert en speelt een zet.
+func play(t testing.TB, gs *GameState, pid int, s string) {
+	t.Helper()
+	m := mv(t, pid, s)
+	if err := gs.ValidateMove(m); err != nil {
+		t.Fatalf("P%d %q: %v", pid, s, err)
+	}
+	gs.ApplyMove(m)
+}
+
+func TestApplyMoveOpenAndResponse(t *testing.T) {
+	gs := newTestGame(t, "7 7 K", "8 8 9")
+	play(t, gs, 0, "7 7")
+	if gs.Round.IsOpen || gs.Round.Count != 2 || gs.Round.TableRank != cards.RankSeven {
+		t.Fatalf("na 7 7: ronde %+v", gs.Round)
+	}
+	if gs.CurrentTurn != 1 || gs.Round.LastPlayerID != 0 {
+		t.Fatalf("na 7 7: beurt %d, laatste %d", gs.CurrentTurn, gs.Round.LastPlayerID)
+	}
+	play(t, gs, 1, "8 8")
+	if gs.Round.TableRank != cards.RankEight || gs.Round.LastPlayerID != 1 || gs.CurrentTurn != 0 {
+		t.Fatalf("na 8 8: ronde %+v, beurt %d", gs.Round, gs.CurrentTurn)
+	}
+	if gs.Hands[0].Count() != 1 || gs.Hands[1].Count() != 1 {
+		t.Errorf("handen: %d en %d kaarten", gs.Hands[0].Count(), gs.Hands[1].Count())
+	}
+	if len(gs.Played) != 4 || len(gs.History) != 2 {
+		t.Errorf("Played %d, History %d", len(gs.Played), len(gs.History))
+	}
+}
+
+func TestApplyMovePassOpensRoundForLastPlayer(t *testing.T) {
+	gs := newTestGame(t, "7 K", "3 4", "3 5")
+	play(t, gs, 0, "7")
+	play(t, gs, 1, "p")
+	if gs.Round.IsOpen || gs.CurrentTurn != 2 || gs.Round.ConsecPasses != 1 {
+		t.Fatalf("na één pass: ronde %+v, beurt %d", gs.Round, gs.CurrentTurn)
+	}
+	play(t, gs, 2, "p")
+	if !gs.Round.IsOpen || gs.CurrentTurn != 0 || gs.Round.ConsecPasses != 0 {
+		t.Fatalf("na twee passes: ronde %+v, beurt %d", gs.Round, gs.CurrentTurn)
+	}
+}
+
+func TestApplyMovePlayResetsPassCount(t *testing.T) {
+	gs := newTestGame(t, "7 K", "3 8", "3 9")
+	play(t, gs, 0, "7")
+	play(t, gs, 1, "p")
+	play(t, gs, 2, "9")
+	if gs.Round.ConsecPasses != 0 || gs.Round.LastPlayerID != 2 || gs.CurrentTurn != 0 {
+		t.Fatalf("na 9: ronde %+v, beurt %d", gs.Round, gs.CurrentTurn)
+	}
+	play(t, gs, 0, "p")
+	play(t, gs, 1, "p")
+	if !gs.Round.IsOpen || gs.CurrentTurn != 2 {
+		t.Fatalf("na twee passes: ronde %+v, beurt %d", gs.Round, gs.CurrentTurn)
+	}
+}
+
+func TestApplyMoveWildKeepsTableRank(t *testing.T) {
+	gs := newTestGame(t, "7 7 K", "2 2 9")
+	play(t, gs, 0, "7 7")
+	play(t, gs, 1, "2 2")
+	if gs.Round.TableRank != cards.RankSeven || gs.Round.LastPlayerID != 1 {
+		t.Errorf("na 2 2 op 7 7: ronde %+v", gs.Round)
+	}
+}
+
+func TestApplyMoveWildTakesNormalRank(t *testing.T) {
+	gs := newTestGame(t, "7 7 K", "9 2 3")
+	play(t, gs, 0, "7 7")
+	play(t, gs, 1, "9 2")
+	if gs.Round.TableRank != cards.RankNine {
+		t.Errorf("na 9 2: tafel-rank %d, want 9", gs.Round.TableRank)
+	}
+}
+
+func TestApplyMoveResetKeepsTurn(t *testing.T) {
+	gs := newTestGame(t, "3 K", "0 8 8 9")
+	play(t, gs, 0, "K")
+	play(t, gs, 1, "0")
+	if !gs.Round.IsOpen || gs.CurrentTurn != 1 || gs.Round.LastPlayerID != 1 {
+		t.Fatalf("na joker: ronde %+v, beurt %d", gs.Round, gs.CurrentTurn)
+	}
+	play(t, gs, 1, "8 8")
+	if gs.Round.Count != 2 || gs.CurrentTurn != 0 {
+		t.Errorf("na 8 8: ronde %+v, beurt %d", gs.Round, gs.CurrentTurn)
+	}
+}
+
+func TestApplyMoveFinishOnResetPassesTurn(t *testing.T) {
+	gs := newTestGame(t, "3 4", "5", "0")
+	play(t, gs, 0, "3")
+	play(t, gs, 1, "p")
+	play(t, gs, 2, "0")
+	if !gs.Finished[2] || gs.GameOver {
+		t.Fatalf("speler 2 moet klaar zijn, partij niet: %+v", gs)
+	}
+	if !gs.Round.IsOpen || gs.CurrentTurn != 0 {
+		t.Errorf("na laatste joker: ronde %+v, beurt %d (want 0)", gs.Round, gs.CurrentTurn)
+	}
+}
+
+// Als de laatste speler op tafel al klaar is, moeten álle actieve spelers
+// passen voor de ronde opent; de beurt gaat dan naar de volgende actieve
+// speler na hem.
+func TestPassThresholdWhenLastPlayerFinished(t *testing.T) {
+	gs := newTestGame(t, "K", "3 4", "3 5")
+	play(t, gs, 0, "K")
+	if !gs.Finished[0] || gs.Round.LastPlayerID != 0 {
+		t.Fatalf("speler 0 moet klaar zijn: %+v", gs.Round)
+	}
+	if got := gs.passThreshold(); got != 2 {
+		t.Fatalf("passThreshold = %d, want 2", got)
+	}
+	play(t, gs, 1, "p")
+	if gs.Round.IsOpen || gs.CurrentTurn != 2 {
+		t.Fatalf("na één pass: ronde %+v, beurt %d", gs.Round, gs.CurrentTurn)
+	}
+	play(t, gs, 2, "p")
+	if !gs.Round.IsOpen || gs.CurrentTurn != 1 {
+		t.Fatalf("na twee passes: ronde %+v, beurt %d (want 1)", gs.Round, gs.CurrentTurn)
+	}
+}
+
+func TestPassThresholdSkipsFinishedPlayers(t *testing.T) {
+	gs := newTestGame(t, "K", "3 4", "3 5", "3 6")
+	play(t, gs, 0, "K")
+	play(t, gs, 1, "p")
+	play(t, gs, 2, "p")
+	play(t, gs, 3, "p")
+	if !gs.Round.IsOpen || gs.CurrentTurn != 1 {
+		t.Fatalf("ronde %+v, beurt %d", gs.Round, gs.CurrentTurn)
+	}
+	play(t, gs, 1, "4")
+	if got := gs.passThreshold(); got != 2 {
+		t.Errorf("passThreshold = %d, want 2 (3 actief, laatste speler actief)", got)
+	}
+	play(t, gs, 2, "p")
+	play(t, gs, 3, "p")
+	if !gs.Round.IsOpen || gs.CurrentTurn != 1 {
+		t.Errorf("ronde %+v, beurt %d (want 1)", gs.Round, gs.CurrentTurn)
+	}
+}
+
+func TestFinishPlayerRanking(t *testing.T) {
+	gs := newTestGame(t, "K", "3 4", "5")
+	play(t, gs, 0, "K")
+	if gs.Winner != 0 || len(gs.Ranking) != 1 || gs.GameOver {
+		t.Fatalf("na K: winnaar %d, ranking %v, voorbij %v", gs.Winner, gs.Ranking, gs.GameOver)
+	}
+	play(t, gs, 1, "p")
+	play(t, gs, 2, "p")
+	play(t, gs, 1, "3")
+	play(t, gs, 2, "5")
+	if !gs.GameOver {
+		t.Fatal("partij moet voorbij zijn")
+	}
+	want := []int{0, 2, 1}
+	if len(gs.Ranking) != len(want) {
+		t.Fatalf("ranking %v, want %v", gs.Ranking, want)
+	}
+	for i := range want {
+		if gs.Ranking[i] != want[i] {
+			t.Fatalf("ranking %v, want %v", gs.Ranking, want)
+		}
+		if gs.PlayerRank(want[i]) != i {
+			t.Errorf("PlayerRank(%d) = %d, want %d", want[i], gs.PlayerRank(want[i]), i)
+		}
+	}
+	for p, f := range gs.Finished {
+		if !f {
+			t.Errorf("speler %d niet gemarkeerd als klaar", p)
+		}
+	}
+	if gs.Winner != 0 {
+		t.Errorf("winnaar %d, want 0", gs.Winner)
+	}
+	if gs.GetLegalMoves() != nil {
+		t.Error("GetLegalMoves na einde moet nil zijn")
+	}
+}
+
+func TestValidateMove(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   []string // zetten om en om vanaf speler 0
+		pid     int
+		move    string
+		wantErr string // "" = geldig
+	}{
+		{"open single", nil, 0, "7", ""},
+		{"open met wild", nil, 0, "7 2", ""},
+		{"open enkel wilds", nil, 0, "2 2", ""},
+		{"open joker", nil, 0, "0", ""},
+		{"open joker met wild", nil, 0, "0 2", ""},
+		{"open joker met normale kaart", nil, 0, "0 7", "joker"},
+		{"open gemengde ranks", nil, 0, "7 8", "dezelfde rank"},
+		{"pass op open ronde", nil, 0, "p", ""},
+		{"niet aan de beurt", nil, 1, "9", "turn"},
+		{"niet in hand", nil, 0, "Q", "not in hand"},
+		{"leeg", nil, 0, "", "at least one"},
+		{"hoger", []string{"7"}, 1, "9", ""},
+		{"gelijk", []string{"7"}, 1, "7", "verslaat"},
+		{"lager", []string{"8"}, 1, "3", "verslaat"},
+		{"verkeerd aantal", []string{"7"}, 1, "9 9", "exact 1"},
+		{"wild op single", []string{"7"}, 1, "2", ""},
+		{"joker op single", []string{"7"}, 1, "0", ""},
+		{"joker op paar zonder wild", []string{"7 7"}, 1, "0", "exact 2"},
+		{"joker met wild op paar", []string{"7 7"}, 1, "0 2", ""},
+		{"paar met wild", []string{"7 7"}, 1, "9 2", ""},
+		{"aas op koning", []string{"K"}, 1, "1", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := newTestGame(t, "7 7 8 K 2 2 0", "3 7 9 9 1 2 0")
+			for i, s := range tt.setup {
+				play(t, gs, i%2, s)
+			}
+			err := gs.ValidateMove(mv(t, tt.pid, tt.move))
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("onverwachte fout: %v", err)
+			case tt.wantErr != "" && err == nil:
+				t.Errorf("geen fout, want %q", tt.wantErr)
+			case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+				t.Errorf("fout %q bevat %q niet", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Een open kent geen maximum: zeven of acht kaarten zijn geldig en de
+// generator biedt ze ook aan.
+func TestLargeOpens(t *testing.T) {
+	gs := newTestGame(t, "4 4 4 4 2 2 2 2 2 0 0", "3")
+	legal := map[string]bool{}
+	for _, s := range moveSet(gs.GetLegalMoves()) {
+		legal[s] = true
+	}
+	for _, s := range []string{"4 4 4 4 2 2", "4 4 4 4 2 2 2", "4 4 4 4 2 2 2 2", "0 0 2 2 2 2 2"} {
+		m := mv(t, 0, s)
+		if err := gs.ValidateMove(m); err != nil {
+			t.Errorf("%s: %v", s, err)
+		}
+		if !legal[FormatMove(m)] {
+			t.Errorf("%s niet gegenereerd", s)
+		}
+	}
+}
+
+func TestValidateMoveGameOver(t *testing.T) {
+	gs := newTestGame(t, "K", "3")
+	play(t, gs, 0, "K")
+	if err := gs.ValidateMove(PassMove(1)); err == nil {
+		t.Error("zet na einde van de partij gaf geen fout")
+	}
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	gs := newTestGame(t, "7 7 K", "8 8 9")
+	c := gs.Clone()
+	play(t, c, 0, "7 7")
+	if gs.Hands[0].Count() != 3 || len(gs.History) != 0 || !gs.Round.IsOpen || gs.CurrentTurn != 0 {
+		t.Errorf("zet op de kloon veranderde het origineel: %+v", gs)
+	}
+}
+
+func TestDealNewGameIsIndependent(t *testing.T) {
+	d := NewDeal(3, rand.New(rand.NewSource(1)))
+	a := d.NewGame()
+	if a.CurrentTurn != d.Start || len(a.Hands) != 3 || len(a.DeadCards) != len(d.Dead) {
+		t.Fatalf("partij past niet bij de deal: %+v", a)
+	}
+	move := a.GetLegalMoves()[0]
+	if move.IsPass {
+		move = a.GetLegalMoves()[1]
+	}
+	a.ApplyMove(move)
+	b := d.NewGame()
+	for i, h := range b.Hands {
+		if !reflect.DeepEqual(h.Cards, d.Hands[i]) {
+			t.Errorf("hand %d veranderd na een zet in een andere partij: %v, want %v", i, h.Cards, d.Hands[i])
+		}
+	}
+	if !reflect.DeepEqual(d, NewDeal(3, rand.New(rand.NewSource(1)))) {
+		t.Error("zelfde seed geeft een andere deal")
+	}
+}
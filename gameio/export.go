@@ -0,0 +1,240 @@
+package gameio
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Exportformaten voor een (geanalyseerde) partij: CSV voor rekenbladen,
+// Markdown voor chat en wiki, HTML als op zichzelf staande pagina. Spelers
+// worden vanaf 1 genummerd, zoals in de CLI; winkansen staan in CSV als
+// fractie (0-1), in Markdown en HTML als percentage.
+
+// csvHeader zijn de kolommen van WriteCSV.
+var csvHeader = []string{
+	"turn", "player", "move", "follow", "best", "best_follow",
+	"score", "best_score", "loss", "grade", "visits", "forced_win_depth",
+}
+
+// WriteCSV schrijft één regel per beurt. Kolommen van de beoordeling blijven
+// leeg voor beurten zonder Eval.
+func WriteCSV(w io.Writer, rec *Record) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for i, t := range rec.Turns {
+		row := []string{strconv.Itoa(i + 1), strconv.Itoa(t.Player + 1), t.label(), t.Follow}
+		if e := t.Eval; e != nil {
+			row = append(row, e.Best, e.BestFollow,
+				formatScore(e.Score), formatScore(e.BestScore), formatScore(e.Loss),
+				e.Grade, strconv.Itoa(e.Visits), strconv.Itoa(e.ForcedWinDepth))
+		} else {
+			row = append(row, make([]string, len(csvHeader)-len(row))...)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatScore(f float64) string { return strconv.FormatFloat(f, 'f', 4, 64) }
+
+// label is de zet van de beurt zonder vervolg-zet, zoals FormatMove.
+func (t Turn) label() string {
+	if t.Pass {
+		return "PASS"
+	}
+	return t.Cards
+}
+
+// exportRow is één beurt zoals Markdown en HTML ze tonen.
+type exportRow struct {
+	Num      int
+	Player   string
+	Move     string
+	Best     string
+	Score    string
+	BestWin  string
+	Loss     string
+	Grade    string
+	Visits   string
+	Forced   string
+	Analyzed bool
+}
+
+// exportSummary telt de oordelen van één speler.
+type exportSummary struct {
+	Player       string
+	Moves        int
+	Good         int
+	Inaccuracies int
+	Blunders     int
+	Accuracy     string
+	AvgLoss      string
+}
+
+// exportView is wat de Markdown- en HTML-uitvoer nodig hebben.
+type exportView struct {
+	Title   string
+	Info    string
+	Rows    []exportRow
+	Summary []exportSummary
+	Result  string
+}
+
+func pct(f float64) string { return fmt.Sprintf("%.1f%%", f*100) }
+
+// playerName is de naam uit Meta.Names, of "Speler n".
+func (rec *Record) playerName(p int) string {
+	if p >= 0 && p < len(rec.Meta.Names) && rec.Meta.Names[p] != "" {
+		return rec.Meta.Names[p]
+	}
+	return fmt.Sprintf("Speler %d", p+1)
+}
+
+func (rec *Record) exportView() exportView {
+	v := exportView{Title: "Analyse"}
+	var info []string
+	info = append(info, fmt.Sprintf("%d spelers", rec.Players))
+	if rec.Meta.Created != nil {
+		info = append(info, rec.Meta.Created.Format("2006-01-02 15:04"))
+	}
+	if rec.Meta.Source != "" {
+		info = append(info, "bron: "+rec.Meta.Source)
+	}
+	if rec.Meta.Iterations > 0 {
+		info = append(info, fmt.Sprintf("%d iteraties per zet", rec.Meta.Iterations))
+	}
+	v.Info = strings.Join(info, " · ")
+
+	sums := make([]exportSummary, rec.Players)
+	loss := make([]float64, rec.Players)
+	for i, t := range rec.Turns {
+		row := exportRow{Num: i + 1, Player: rec.playerName(t.Player), Move: t.label()}
+		if t.Follow != "" {
+			row.Move += " / " + t.Follow
+		}
+		if e := t.Eval; e != nil {
+			row.Analyzed = true
+			row.Best = e.Best
+			if e.BestFollow != "" {
+				row.Best += " / " + e.BestFollow
+			}
+			row.Score, row.BestWin, row.Loss = pct(e.Score), pct(e.BestScore), pct(e.Loss)
+			row.Grade = e.Grade
+			row.Visits = strconv.Itoa(e.Visits)
+			if e.ForcedWinDepth > 0 {
+				row.Forced = strconv.Itoa(e.ForcedWinDepth)
+			}
+			if t.Player >= 0 && t.Player < rec.Players {
+				s := &sums[t.Player]
+				s.Moves++
+				loss[t.Player] += e.Loss
+				switch e.Grade {
+				case "blunder":
+					s.Blunders++
+				case "onnauwkeurig":
+					s.Inaccuracies++
+				default:
+					s.Good++
+				}
+			}
+		}
+		v.Rows = append(v.Rows, row)
+	}
+	for p, s := range sums {
+		if s.Moves == 0 {
+			continue
+		}
+		s.Player = rec.playerName(p)
+		s.Accuracy = pct(float64(s.Good) / float64(s.Moves))
+		s.AvgLoss = pct(loss[p] / float64(s.Moves))
+		v.Summary = append(v.Summary, s)
+	}
+	if len(rec.Ranking) > 0 {
+		names := make([]string, len(rec.Ranking))
+		for i, p := range rec.Ranking {
+			names[i] = fmt.Sprintf("%d. %s", i+1, rec.playerName(p))
+		}
+		v.Result = strings.Join(names, ", ")
+	}
+	return v
+}
+
+// WriteMarkdown schrijft de partij als Markdown-tabel met per speler een
+// samenvatting (nauwkeurigheid, fouten, gemiddeld verlies).
+func WriteMarkdown(w io.Writer, rec *Record) error {
+	v := rec.exportView()
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n%s\n\n", v.Title, v.Info)
+	b.WriteString("| # | Speler | Zet | Beste zet | Winkans | Beste | Verlies | Oordeel | Visits | Gedwongen winst |\n")
+	b.WriteString("|--:|--------|-----|-----------|--------:|------:|--------:|---------|-------:|----------------:|\n")
+	for _, r := range v.Rows {
+		grade := r.Grade
+		if grade == "blunder" {
+			grade = "**blunder**"
+		}
+		fmt.Fprintf(&b, "| %d | %s | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			r.Num, r.Player, r.Move, r.Best, r.Score, r.BestWin, r.Loss, grade, r.Visits, r.Forced)
+	}
+	if len(v.Summary) > 0 {
+		b.WriteString("\n## Samenvatting\n\n")
+		b.WriteString("| Speler | Zetten | Goed | Onnauwkeurig | Blunders | Nauwkeurigheid | Gem. verlies |\n")
+		b.WriteString("|--------|-------:|-----:|-------------:|---------:|---------------:|-------------:|\n")
+		for _, s := range v.Summary {
+			fmt.Fprintf(&b, "| %s | %d | %d | %d | %d | %s | %s |\n",
+				s.Player, s.Moves, s.Good, s.Inaccuracies, s.Blunders, s.Accuracy, s.AvgLoss)
+		}
+	}
+	if v.Result != "" {
+		fmt.Fprintf(&b, "\nEindstand: %s\n", v.Result)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var htmlExport = template.Must(template.New("export").Parse(`<!DOCTYPE html>
+<html lang="nl">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 1em; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #ccc; padding: .25em .5em; }
+td.n { text-align: right; }
+tr.onnauwkeurig { background: #fff4d6; }
+tr.blunder { background: #fde0e0; }
+tr.skip { color: #888; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Info}}</p>
+<table>
+<tr><th>#</th><th>Speler</th><th>Zet</th><th>Beste zet</th><th>Winkans</th><th>Beste</th><th>Verlies</th><th>Oordeel</th><th>Visits</th><th>Gedwongen winst</th></tr>
+{{range .Rows}}<tr class="{{if .Analyzed}}{{.Grade}}{{else}}skip{{end}}"><td class="n">{{.Num}}</td><td>{{.Player}}</td><td>{{.Move}}</td><td>{{.Best}}</td><td class="n">{{.Score}}</td><td class="n">{{.BestWin}}</td><td class="n">{{.Loss}}</td><td>{{.Grade}}</td><td class="n">{{.Visits}}</td><td class="n">{{.Forced}}</td></tr>
+{{end}}</table>
+{{if .Summary}}<h2>Samenvatting</h2>
+<table>
+<tr><th>Speler</th><th>Zetten</th><th>Goed</th><th>Onnauwkeurig</th><th>Blunders</th><th>Nauwkeurigheid</th><th>Gem. verlies</th></tr>
+{{range .Summary}}<tr><td>{{.Player}}</td><td class="n">{{.Moves}}</td><td class="n">{{.Good}}</td><td class="n">{{.Inaccuracies}}</td><td class="n">{{.Blunders}}</td><td class="n">{{.Accuracy}}</td><td class="n">{{.AvgLoss}}</td></tr>
+{{end}}</table>
+{{end}}{{if .Result}}<p>Eindstand: {{.Result}}</p>
+{{end}}</body>
+</html>
+`))
+
+// WriteHTML schrijft de partij als op zichzelf staande HTML-pagina, met
+// dezelfde inhoud als WriteMarkdown en fouten in kleur.
+func WriteHTML(w io.Writer, rec *Record) error {
+	return htmlExport.Execute(w, rec.exportView())
+}
@@ -0,0 +1,69 @@
+package gameio
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+// annotated is de testpartij met namen en een beoordeling bij beide beurten
+// van An: een blunder en de joker-beurt met gedwongen winst.
+func annotated(t *testing.T) *Record {
+	t.Helper()
+	rec, err := FromLog(readText(t, partij))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Meta.Names = []string{"An", "<Bo>"}
+	rec.Turns[1].Eval = &Eval{Best: "3 3", BestScore: 0.8, Score: 0.5, Loss: 0.3, Visits: 40, Grade: "blunder"}
+	rec.Turns[3].Eval = &Eval{Best: "0", BestFollow: "3 3", BestScore: 1, Score: 1, ForcedWinDepth: 1, Grade: "goed"}
+	return rec
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, annotated(t)); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 5 {
+		t.Fatalf("%d regels, want kop + 4 beurten", len(rows))
+	}
+	want := []string{"2", "1", "PASS", "", "3 3", "", "0.5000", "0.8000", "0.3000", "blunder", "40", "0"}
+	if strings.Join(rows[2], ",") != strings.Join(want, ",") {
+		t.Errorf("beurt 2: %q\nwant %q", rows[2], want)
+	}
+	if rows[4][2] != "0" || rows[4][3] != "3 3" || rows[4][11] != "1" {
+		t.Errorf("joker-beurt: %q", rows[4])
+	}
+}
+
+func TestWriteMarkdownAndHTML(t *testing.T) {
+	rec := annotated(t)
+	var md bytes.Buffer
+	if err := WriteMarkdown(&md, rec); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"| 2 | An | PASS | 3 3 | 50.0% | 80.0% | 30.0% | **blunder** | 40 |  |",
+		"| 4 | An | 0 / 3 3 | 0 / 3 3 |",
+		"| An | 2 | 1 | 0 | 1 | 50.0% | 15.0% |",
+		"Eindstand: 1. An, 2. <Bo>",
+	} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("Markdown mist %q:\n%s", want, md.String())
+		}
+	}
+
+	var html bytes.Buffer
+	if err := WriteHTML(&html, rec); err != nil {
+		t.Fatal(err)
+	}
+	if s := html.String(); !strings.Contains(s, `<tr class="blunder">`) || !strings.Contains(s, "&lt;Bo&gt;") {
+		t.Errorf("HTML:\n%s", s)
+	}
+}
@@ -0,0 +1,158 @@
+// Package gameio leest en schrijft gespeelde partijen als tekstlog.
+package gameio
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/game"
+)
+
+type GameLog struct {
+	NumPlayers int
+	Hands      [][]cards.Card
+	DeadCards  []cards.Card
+	Moves      []game.Move
+	Winner     int
+}
+
+func SaveGame(path string, log *GameLog) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = WriteGame(f, log)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// WriteGame schrijft log in het tekstformaat naar w.
+func WriteGame(w io.Writer, log *GameLog) error {
+	f := bufio.NewWriter(w)
+	fmt.Fprintf(f, "AZEN GAME LOG\n")
+	fmt.Fprintf(f, "players:%d\n", log.NumPlayers)
+	fmt.Fprintf(f, "winner:%d\n", log.Winner)
+	for i, hand := range log.Hands {
+		parts := make([]string, len(hand))
+		for j, c := range hand {
+			parts[j] = c.String()
+		}
+		fmt.Fprintf(f, "hand:%d:%s\n", i, strings.Join(parts, ","))
+	}
+	if len(log.DeadCards) > 0 {
+		parts := make([]string, len(log.DeadCards))
+		for i, c := range log.DeadCards {
+			parts[i] = c.String()
+		}
+		fmt.Fprintf(f, "dead:%s\n", strings.Join(parts, ","))
+	}
+	fmt.Fprintf(f, "---\n")
+	for _, m := range log.Moves {
+		if m.IsPass {
+			fmt.Fprintf(f, "P%d:PASS\n", m.PlayerID)
+		} else {
+			parts := make([]string, len(m.Cards))
+			for i, c := range m.Cards {
+				parts[i] = c.String()
+			}
+			fmt.Fprintf(f, "P%d:%s\n", m.PlayerID, strings.Join(parts, ","))
+		}
+	}
+	return f.Flush()
+}
+
+func LoadGame(path string) (*GameLog, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadGame(f)
+}
+
+// ReadGame leest een tekstlog zoals SaveGame die schrijft, bv. uit een upload.
+func ReadGame(r io.Reader) (*GameLog, error) {
+	log := &GameLog{Winner: -1}
+	scanner := bufio.NewScanner(r)
+	inMoves := false
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line == "AZEN GAME LOG" {
+			continue
+		}
+		if line == "---" {
+			inMoves = true
+			continue
+		}
+		if inMoves {
+			m, err := parseMoveLog(line)
+			if err != nil {
+				return nil, fmt.Errorf("parsing move %q: %v", line, err)
+			}
+			log.Moves = append(log.Moves, m)
+			continue
+		}
+		if strings.HasPrefix(line, "players:") {
+			n, err := strconv.Atoi(strings.TrimPrefix(line, "players:"))
+			if err != nil {
+				return nil, fmt.Errorf("parsing %q: %v", line, err)
+			}
+			log.NumPlayers = n
+		} else if strings.HasPrefix(line, "winner:") {
+			n, err := strconv.Atoi(strings.TrimPrefix(line, "winner:"))
+			if err != nil {
+				return nil, fmt.Errorf("parsing %q: %v", line, err)
+			}
+			log.Winner = n
+		} else if strings.HasPrefix(line, "hand:") {
+			parts := strings.SplitN(strings.TrimPrefix(line, "hand:"), ":", 2)
+			if len(parts) == 2 {
+				cc, err := cards.ParseCards(parts[1])
+				if err != nil {
+					return nil, err
+				}
+				idx, err := strconv.Atoi(parts[0])
+				if err != nil || idx < 0 || idx >= 4 {
+					return nil, fmt.Errorf("parsing %q: invalid hand index", line)
+				}
+				for len(log.Hands) <= idx {
+					log.Hands = append(log.Hands, nil)
+				}
+				log.Hands[idx] = cc
+			}
+		} else if strings.HasPrefix(line, "dead:") {
+			cc, err := cards.ParseCards(strings.TrimPrefix(line, "dead:"))
+			if err != nil {
+				return nil, err
+			}
+			log.DeadCards = cc
+		}
+	}
+	return log, scanner.Err()
+}
+
+func parseMoveLog(line string) (game.Move, error) {
+	parts := strings.SplitN(line, ":", 2)
+	if len(parts) != 2 {
+		return game.Move{}, fmt.Errorf("invalid move format")
+	}
+	var pid int
+	if _, err := fmt.Sscanf(parts[0], "P%d", &pid); err != nil || pid < 0 {
+		return game.Move{}, fmt.Errorf("invalid player %q", parts[0])
+	}
+	if strings.TrimSpace(parts[1]) == "PASS" {
+		return game.PassMove(pid), nil
+	}
+	cc, err := cards.ParseCards(parts[1])
+	if err != nil {
+		return game.Move{}, err
+	}
+	return game.Move{PlayerID: pid, Cards: cc}, nil
+}
@@ -0,0 +1,389 @@
+package gameio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/game"
+)
+
+// RecordVersion is de versie van het JSON-formaat van Record. ReadRecord
+// weigert records met een hogere versie.
+const RecordVersion = 1
+
+// Record is een gespeelde partij in JSON-vorm. Anders dan de tekstlog
+// bewaart het de startspeler, de kaartaantallen, een vervolg-zet na een
+// joker als deel van dezelfde beurt, de eindrangschikking, tijdstippen en
+// optioneel de beoordeling van de engine per zet.
+type Record struct {
+	Version int      `json:"version"`
+	Meta    Meta     `json:"meta"`
+	Players int      `json:"players"`
+	Start   int      `json:"start"`             // speler die begint (0-based)
+	Counts  []int    `json:"counts"`            // startkaarten per speler
+	Hands   []string `json:"hands"`             // starthanden; "" = onbekend
+	Dead    string   `json:"dead,omitempty"`    // kaarten niet in spel
+	Turns   []Turn   `json:"turns"`             // beurten in volgorde
+	Ranking []int    `json:"ranking,omitempty"` // eindstand, winnaar eerst
+	Winner  int      `json:"winner"`            // -1 = onbekend of niet afgelopen
+
+	// awaitFollow: de laatste beurt (via AddMove) is een joker-reset zonder
+	// vervolg-zet.
+	awaitFollow bool
+}
+
+// Meta zijn gegevens over de partij die niet tot het spel zelf behoren.
+type Meta struct {
+	Created    *time.Time `json:"created,omitempty"`
+	Source     string     `json:"source,omitempty"` // "play", "simulate", "analyze", "textlog", ...
+	Names      []string   `json:"names,omitempty"`  // spelersnamen
+	Seed       int64      `json:"seed,omitempty"`
+	Iterations int        `json:"iterations,omitempty"`
+	Notes      string     `json:"notes,omitempty"`
+}
+
+// Turn is één beurt: een zet, met de vervolg-zet als de speler een joker
+// speelde en meteen opende ("0 / K K").
+type Turn struct {
+	Player int        `json:"player"`
+	Cards  string     `json:"cards,omitempty"`
+	Pass   bool       `json:"pass,omitempty"`
+	Follow string     `json:"follow,omitempty"`
+	Time   *time.Time `json:"time,omitempty"`
+	Eval   *Eval      `json:"eval,omitempty"`
+}
+
+// Eval is de beoordeling van een beurt door de engine.
+type Eval struct {
+	Best           string  `json:"best"` // zoals FormatMove: "K K", "PASS"
+	BestFollow     string  `json:"best_follow,omitempty"`
+	BestScore      float64 `json:"best_score"` // winkans van de beste zet
+	Score          float64 `json:"score"`      // winkans van de gespeelde zet
+	Loss           float64 `json:"loss"`       // verschil; 0 als de gespeelde zet de beste was
+	Visits         int     `json:"visits,omitempty"`
+	ForcedWinDepth int     `json:"forced_win_depth,omitempty"`
+	Grade          string  `json:"grade,omitempty"`
+}
+
+// NewRecord begint een record voor een partij vanaf beginstand start: alle
+// handen, dode kaarten en de startspeler. Zetten komen erbij met AddMove.
+func NewRecord(start *game.GameState) *Record {
+	now := time.Now()
+	n := start.NumPlayers
+	rec := &Record{
+		Version: RecordVersion,
+		Meta:    Meta{Created: &now},
+		Players: n,
+		Start:   start.CurrentTurn,
+		Counts:  make([]int, n),
+		Hands:   make([]string, n),
+		Dead:    cards.CardsToString(start.DeadCards),
+		Winner:  -1,
+	}
+	for i, h := range start.Hands {
+		rec.Counts[i] = h.Count()
+		rec.Hands[i] = cards.CardsToString(h.Cards)
+	}
+	return rec
+}
+
+// AddMove voegt een gespeelde zet toe. Een zet van dezelfde speler na zijn
+// joker wordt de vervolg-zet van die beurt. Geeft de beurt terug, zodat de
+// aanroeper er een tijdstip of beoordeling aan kan hangen.
+func (rec *Record) AddMove(m game.Move) *Turn {
+	if n := len(rec.Turns); n > 0 && rec.awaitFollow && !m.IsPass {
+		last := &rec.Turns[n-1]
+		if last.Player == m.PlayerID {
+			rec.awaitFollow = false
+			last.Follow = cards.CardsToString(m.Cards)
+			return last
+		}
+	}
+	rec.awaitFollow = m.ContainsReset()
+	rec.Turns = append(rec.Turns, NewTurn(m, nil))
+	return &rec.Turns[len(rec.Turns)-1]
+}
+
+// Finish neemt de eindstand over uit gs als de partij voorbij is.
+func (rec *Record) Finish(gs *game.GameState) {
+	if gs.GameOver {
+		rec.Ranking = append([]int(nil), gs.Ranking...)
+		rec.Winner = gs.Winner
+	}
+}
+
+// NewTurn maakt een beurt uit een zet en een eventuele vervolg-zet.
+func NewTurn(m game.Move, follow *game.Move) Turn {
+	t := Turn{Player: m.PlayerID, Pass: m.IsPass}
+	if !m.IsPass {
+		t.Cards = cards.CardsToString(m.Cards)
+	}
+	if follow != nil {
+		t.Follow = cards.CardsToString(follow.Cards)
+	}
+	return t
+}
+
+// Moves zet de beurt om naar zetten: de zet en eventueel de vervolg-zet.
+func (t Turn) Moves() ([]game.Move, error) {
+	if t.Pass {
+		if t.Follow != "" {
+			return nil, fmt.Errorf("pass met vervolg-zet")
+		}
+		return []game.Move{game.PassMove(t.Player)}, nil
+	}
+	cc, err := cards.ParseCards(t.Cards)
+	if err != nil {
+		return nil, err
+	}
+	if len(cc) == 0 {
+		return nil, fmt.Errorf("beurt zonder kaarten (gebruik pass)")
+	}
+	moves := []game.Move{{PlayerID: t.Player, Cards: cc}}
+	if t.Follow != "" {
+		fc, err := cards.ParseCards(t.Follow)
+		if err != nil {
+			return nil, fmt.Errorf("vervolg-zet: %v", err)
+		}
+		moves = append(moves, game.Move{PlayerID: t.Player, Cards: fc})
+	}
+	return moves, nil
+}
+
+// FromLog zet een tekstlog om naar een Record. De log kent geen startspeler
+// en geen beurten: de startspeler is die van de eerste zet en twee zetten na
+// elkaar van dezelfde speler, de eerste met een joker, vormen één beurt. De
+// eindstand wordt berekend door de partij af te spelen; een log die niet
+// (geldig) afloopt, krijgt geen Ranking.
+func FromLog(log *GameLog) (*Record, error) {
+	if log.NumPlayers < 2 || log.NumPlayers > 4 || len(log.Hands) != log.NumPlayers {
+		return nil, fmt.Errorf("ongeldige log (%d spelers, %d handen)", log.NumPlayers, len(log.Hands))
+	}
+	rec := &Record{
+		Version: RecordVersion,
+		Meta:    Meta{Source: "textlog"},
+		Players: log.NumPlayers,
+		Counts:  make([]int, log.NumPlayers),
+		Hands:   make([]string, log.NumPlayers),
+		Dead:    cards.CardsToString(log.DeadCards),
+		Winner:  log.Winner,
+	}
+	for i, h := range log.Hands {
+		rec.Counts[i] = len(h)
+		rec.Hands[i] = cards.CardsToString(h)
+	}
+	if len(log.Moves) > 0 {
+		rec.Start = log.Moves[0].PlayerID
+	}
+	for i := 0; i < len(log.Moves); i++ {
+		m := log.Moves[i]
+		var follow *game.Move
+		if m.ContainsReset() && i+1 < len(log.Moves) && log.Moves[i+1].PlayerID == m.PlayerID && !log.Moves[i+1].IsPass {
+			follow = &log.Moves[i+1]
+			i++
+		}
+		rec.Turns = append(rec.Turns, NewTurn(m, follow))
+	}
+	if gs, err := rec.Replay(); err == nil && gs.GameOver {
+		rec.Ranking = gs.Ranking
+	}
+	return rec, nil
+}
+
+// ToLog zet het record om naar een tekstlog. Dat kan enkel als alle
+// starthanden gekend zijn; metagegevens en beoordelingen vallen weg.
+func (rec *Record) ToLog() (*GameLog, error) {
+	log := &GameLog{NumPlayers: rec.Players, Winner: rec.Winner}
+	for i, h := range rec.Hands {
+		if h == "" {
+			return nil, fmt.Errorf("hand van speler %d is onbekend", i)
+		}
+		cc, err := cards.ParseCards(h)
+		if err != nil {
+			return nil, fmt.Errorf("hand %d: %v", i, err)
+		}
+		log.Hands = append(log.Hands, cc)
+	}
+	dead, err := cards.ParseCards(rec.Dead)
+	if err != nil {
+		return nil, fmt.Errorf("dead: %v", err)
+	}
+	log.DeadCards = dead
+	for i, t := range rec.Turns {
+		moves, err := t.Moves()
+		if err != nil {
+			return nil, fmt.Errorf("beurt %d: %v", i+1, err)
+		}
+		log.Moves = append(log.Moves, moves...)
+	}
+	return log, nil
+}
+
+// Replay speelt de partij af vanaf de starthanden en geeft de eindstand
+// terug. Elke zet wordt gevalideerd.
+func (rec *Record) Replay() (*game.GameState, error) {
+	log, err := rec.ToLog()
+	if err != nil {
+		return nil, err
+	}
+	hands := make([]*cards.Hand, rec.Players)
+	for i, h := range log.Hands {
+		hands[i] = cards.NewHand(h)
+	}
+	gs := game.NewGameWithHands(hands, log.DeadCards, rec.Start)
+	for i, t := range rec.Turns {
+		moves, _ := t.Moves()
+		for _, m := range moves {
+			if err := gs.ValidateMove(m); err != nil {
+				return gs, fmt.Errorf("beurt %d (%s): %v", i+1, m, err)
+			}
+			gs.ApplyMove(m)
+		}
+	}
+	return gs, nil
+}
+
+// check controleert de vaste velden van een ingelezen record.
+func (rec *Record) check() error {
+	if rec.Version < 1 || rec.Version > RecordVersion {
+		return fmt.Errorf("onbekende recordversie %d (ondersteund: 1-%d)", rec.Version, RecordVersion)
+	}
+	n := rec.Players
+	if n < 2 || n > 4 || len(rec.Hands) != n || len(rec.Counts) != n {
+		return fmt.Errorf("ongeldig record (%d spelers, %d handen, %d kaartaantallen)", n, len(rec.Hands), len(rec.Counts))
+	}
+	if rec.Start < 0 || rec.Start >= n {
+		return fmt.Errorf("ongeldige startspeler %d", rec.Start)
+	}
+	for i, t := range rec.Turns {
+		if t.Player < 0 || t.Player >= n {
+			return fmt.Errorf("beurt %d: ongeldige speler %d", i+1, t.Player)
+		}
+	}
+	return nil
+}
+
+// WriteRecord schrijft rec als ingesprongen JSON.
+func WriteRecord(w io.Writer, rec *Record) error {
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// ReadRecord leest één JSON-record.
+func ReadRecord(r io.Reader) (*Record, error) {
+	rec := &Record{}
+	if err := json.NewDecoder(r).Decode(rec); err != nil {
+		return nil, err
+	}
+	return rec, rec.check()
+}
+
+// WriteRecords schrijft records als JSONL: één compact record per regel,
+// handig om veel partijen in één bestand bij te houden.
+func WriteRecords(w io.Writer, recs []*Record) error {
+	enc := json.NewEncoder(w)
+	for _, rec := range recs {
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadRecords leest JSONL zoals WriteRecords die schrijft. Lege regels
+// worden overgeslagen.
+func ReadRecords(r io.Reader) ([]*Record, error) {
+	var recs []*Record
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16<<20)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		rec := &Record{}
+		if err := json.Unmarshal(text, rec); err != nil {
+			return nil, fmt.Errorf("regel %d: %v", line, err)
+		}
+		if err := rec.check(); err != nil {
+			return nil, fmt.Errorf("regel %d: %v", line, err)
+		}
+		recs = append(recs, rec)
+	}
+	return recs, scanner.Err()
+}
+
+// SaveRecord schrijft rec naar path: JSONL (één regel) als path op .jsonl
+// eindigt, anders ingesprongen JSON.
+func SaveRecord(path string, rec *Record) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(path, ".jsonl") {
+		err = WriteRecords(f, []*Record{rec})
+	} else {
+		err = WriteRecord(f, rec)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// AppendRecord voegt rec als één JSONL-regel toe aan path, zodat alle
+// partijen van een club in één bestand kunnen staan.
+func AppendRecord(path string, rec *Record) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	err = WriteRecords(f, []*Record{rec})
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// LoadRecords leest de partijen in path, in elk van de drie formaten: een
+// JSON-record, JSONL met een record per regel, of een tekstlog zoals
+// SaveGame die schrijft.
+func LoadRecords(path string) ([]*Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		if recs, err := ReadRecords(bytes.NewReader(trimmed)); err == nil {
+			return recs, nil
+		}
+		rec, err := ReadRecord(bytes.NewReader(trimmed))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		return []*Record{rec}, nil
+	}
+	log, err := ReadGame(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	rec, err := FromLog(log)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return []*Record{rec}, nil
+}
@@ -0,0 +1,204 @@
+package gameio
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/game"
+)
+
+// partij is een volledige tweespelerspartij met een joker en vervolg-zet.
+const partij = `AZEN GAME LOG
+players:2
+winner:0
+hand:0:3,3,0
+hand:1:4,4,5,6
+---
+P1:4,4
+P0:PASS
+P1:5
+P0:0
+P0:3,3
+`
+
+func readText(t *testing.T, s string) *GameLog {
+	t.Helper()
+	log, err := ReadGame(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return log
+}
+
+func TestFromLog(t *testing.T) {
+	rec, err := FromLog(readText(t, partij))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Start != 1 || !reflect.DeepEqual(rec.Counts, []int{3, 4}) {
+		t.Errorf("start %d, counts %v", rec.Start, rec.Counts)
+	}
+	if len(rec.Turns) != 4 {
+		t.Fatalf("%d beurten, want 4: %+v", len(rec.Turns), rec.Turns)
+	}
+	if last := rec.Turns[3]; last.Cards != "0" || last.Follow != "3 3" {
+		t.Errorf("joker-beurt: %+v, want 0 / 3 3", last)
+	}
+	if !reflect.DeepEqual(rec.Ranking, []int{0, 1}) || rec.Winner != 0 {
+		t.Errorf("ranking %v, winner %d", rec.Ranking, rec.Winner)
+	}
+}
+
+// TestRecordWhilePlaying bouwt het record zet per zet op, zoals play en
+// simulate doen, en vergelijkt met de omzetting van de tekstlog.
+func TestRecordWhilePlaying(t *testing.T) {
+	log := readText(t, partij)
+	hands := make([]*cards.Hand, log.NumPlayers)
+	for i, h := range log.Hands {
+		hands[i] = cards.NewHand(h)
+	}
+	gs := game.NewGameWithHands(hands, nil, log.Moves[0].PlayerID)
+	rec := NewRecord(gs)
+	for _, m := range log.Moves {
+		if err := gs.ValidateMove(m); err != nil {
+			t.Fatal(err)
+		}
+		gs.ApplyMove(m)
+		rec.AddMove(m)
+	}
+	rec.Finish(gs)
+	want, err := FromLog(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rec.Turns, want.Turns) || !reflect.DeepEqual(rec.Ranking, want.Ranking) || rec.Winner != want.Winner {
+		t.Errorf("record %+v\nwant %+v", rec, want)
+	}
+
+	path := filepath.Join(t.TempDir(), "partijen.jsonl")
+	for i := 0; i < 2; i++ {
+		if err := AppendRecord(path, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	recs, err := LoadRecords(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 || !reflect.DeepEqual(recs[1].Turns, rec.Turns) {
+		t.Errorf("AppendRecord: %d records gelezen, want 2", len(recs))
+	}
+}
+
+// Enkel na een joker wordt een zet van dezelfde speler een vervolg-zet; na
+// een gewonnen ronde opent hij een nieuwe beurt.
+func TestAddMoveFollow(t *testing.T) {
+	mv := func(pid int, s string) game.Move {
+		if s == "" {
+			return game.PassMove(pid)
+		}
+		cc, err := cards.ParseCards(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return game.Move{PlayerID: pid, Cards: cc}
+	}
+	rec := &Record{Players: 2, Winner: -1}
+	for _, m := range []game.Move{mv(0, "0 2"), mv(0, "K K"), mv(1, ""), mv(0, "X"), mv(1, ""), mv(0, "5")} {
+		rec.AddMove(m)
+	}
+	want := []Turn{
+		{Player: 0, Cards: "0 2", Follow: "K K"},
+		{Player: 1, Pass: true},
+		{Player: 0, Cards: "X"},
+		{Player: 1, Pass: true},
+		{Player: 0, Cards: "5"},
+	}
+	if !reflect.DeepEqual(rec.Turns, want) {
+		t.Errorf("beurten %+v\nwant %+v", rec.Turns, want)
+	}
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	rec, err := FromLog(readText(t, partij))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log, err := rec.ToLog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteGame(&buf, log); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != partij {
+		t.Errorf("tekst → record → tekst:\n%s\nwant\n%s", buf.String(), partij)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	rec, err := FromLog(readText(t, partij))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Turns[0].Eval = &Eval{Best: "5", BestScore: 0.8, Score: 0.6, ForcedWinDepth: 2, Grade: "blunder"}
+
+	var buf bytes.Buffer
+	if err := WriteRecord(&buf, rec); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadRecord(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, rec) {
+		t.Errorf("JSON:\n%+v\nwant\n%+v", got, rec)
+	}
+
+	buf.Reset()
+	if err := WriteRecords(&buf, []*Record{rec, rec}); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), "\n"); n != 2 {
+		t.Errorf("JSONL: %d regels, want 2", n)
+	}
+	recs, err := ReadRecords(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 || !reflect.DeepEqual(recs[1], rec) {
+		t.Errorf("JSONL: %d records", len(recs))
+	}
+}
+
+func TestReadRecordRejectsNewerVersion(t *testing.T) {
+	_, err := ReadRecord(strings.NewReader(`{"version":99,"players":2,"hands":["",""],"counts":[18,18]}`))
+	if err == nil || !strings.Contains(err.Error(), "versie") {
+		t.Errorf("fout %v, want onbekende versie", err)
+	}
+}
+
+func TestToLogNeedsAllHands(t *testing.T) {
+	rec := &Record{Version: RecordVersion, Players: 2, Counts: []int{18, 18}, Hands: []string{"3 3", ""}}
+	if _, err := rec.ToLog(); err == nil {
+		t.Error("ToLog met onbekende hand gaf geen fout")
+	}
+}
+
+func TestReadGameInvalid(t *testing.T) {
+	for _, s := range []string{
+		"players:2\n---\nX1:3\n",
+		"players:2\n---\nP-1:3\n",
+		"players:twee\n",
+		"hand:a:3 3\n",
+	} {
+		if _, err := ReadGame(strings.NewReader(s)); err == nil {
+			t.Errorf("ReadGame(%q) gaf geen fout", s)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+// Package knowledge houdt bij wat een speler weet over de verborgen handen
+// van tegenstanders: gespeelde kaarten, passes, vermoedens en uitsluitingen.
+package knowledge
+
+import (
+	"fmt"
+
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/game"
+)
+
+type PassRecord struct {
+	Count     int
+	TableRank cards.Rank
+}
+
+type KnowledgeTracker struct {
+	NumPlayers     int
+	MyPlayerID     int
+	MyHand         *cards.Hand
+	CardsPlayed    []cards.Card
+	DeadCards      []cards.Card
+	HandCounts     []int
+	PlayedByPlayer [][]cards.Card
+	PassRecords    [][]PassRecord
+	Suspicions     map[int][]cards.Card
+	Exclusions     map[int]map[cards.Rank]int
+}
+
+func NewKnowledgeTracker(numPlayers, myID int, myHand *cards.Hand, deadCards []cards.Card) *KnowledgeTracker {
+	kt := &KnowledgeTracker{
+		NumPlayers:     numPlayers,
+		MyPlayerID:     myID,
+		MyHand:         myHand.Clone(),
+		DeadCards:      make([]cards.Card, len(deadCards)),
+		HandCounts:     make([]int, numPlayers),
+		PlayedByPlayer: make([][]cards.Card, numPlayers),
+		PassRecords:    make([][]PassRecord, numPlayers),
+		Suspicions:     map[int][]cards.Card{},
+		Exclusions:     map[int]map[cards.Rank]int{},
+	}
+	copy(kt.DeadCards, deadCards)
+	for i := range kt.HandCounts {
+		kt.HandCounts[i] = 18
+	}
+	// Spelregel: elke speler krijgt bij de verdeling gegarandeerd minstens 1 wildcard (2).
+	// → Voeg voor elke tegenstander 1 Twee toe als zekere suspicion.
+	// updateSuspicions() verwijdert die automatisch zodra de tegenstander een Twee speelt.
+	for p := 0; p < numPlayers; p++ {
+		if p != myID {
+			kt.Suspicions[p] = []cards.Card{{Rank: cards.RankTwo}}
+		}
+	}
+	return kt
+}
+
+// Clone maakt een diepe kopie, zodat een spelstand later hersteld kan worden.
+func (kt *KnowledgeTracker) Clone() *KnowledgeTracker {
+	n := &KnowledgeTracker{
+		NumPlayers:     kt.NumPlayers,
+		MyPlayerID:     kt.MyPlayerID,
+		MyHand:         kt.MyHand.Clone(),
+		CardsPlayed:    append([]cards.Card(nil), kt.CardsPlayed...),
+		DeadCards:      append([]cards.Card(nil), kt.DeadCards...),
+		HandCounts:     append([]int(nil), kt.HandCounts...),
+		PlayedByPlayer: make([][]cards.Card, len(kt.PlayedByPlayer)),
+		PassRecords:    make([][]PassRecord, len(kt.PassRecords)),
+		Suspicions:     map[int][]cards.Card{},
+		Exclusions:     map[int]map[cards.Rank]int{},
+	}
+	for i, pp := range kt.PlayedByPlayer {
+		n.PlayedByPlayer[i] = append([]cards.Card(nil), pp...)
+	}
+	for i, pr := range kt.PassRecords {
+		n.PassRecords[i] = append([]PassRecord(nil), pr...)
+	}
+	for p, susp := range kt.Suspicions {
+		n.Suspicions[p] = append([]cards.Card(nil), susp...)
+	}
+	for p, excl := range kt.Exclusions {
+		if excl == nil {
+			n.Exclusions[p] = nil
+			continue
+		}
+		m := make(map[cards.Rank]int, len(excl))
+		for r, c := range excl {
+			m[r] = c
+		}
+		n.Exclusions[p] = m
+	}
+	return n
+}
+
+func (kt *KnowledgeTracker) RecordMove(m game.Move) {
+	if m.IsPass {
+		return
+	}
+	kt.CardsPlayed = append(kt.CardsPlayed, m.Cards...)
+	kt.PlayedByPlayer[m.PlayerID] = append(kt.PlayedByPlayer[m.PlayerID], m.Cards...)
+	kt.HandCounts[m.PlayerID] -= len(m.Cards)
+	if m.PlayerID == kt.MyPlayerID {
+		kt.MyHand.Remove(m.Cards)
+	}
+	kt.updateSuspicions(m.Cards)
+}
+
+func (kt *KnowledgeTracker) RecordPass(passerID int, round game.RoundState) {
+	if passerID == kt.MyPlayerID {
+		return
+	}
+	if round.IsOpen {
+		return
+	}
+	// Alleen loggen bij ≤12 kaarten; bij heel veel kaarten is passen nog strategisch
+	if kt.HandCounts[passerID] > 12 {
+		return
+	}
+	// Accepteer zowel singles als pairs — wie past op een pair heeft dat pair niet
+	if round.Count < 1 || round.Count > 2 {
+		return
+	}
+	kt.PassRecords[passerID] = append(kt.PassRecords[passerID], PassRecord{
+		Count:     round.Count,
+		TableRank: round.TableRank,
+	})
+}
+
+func (kt *KnowledgeTracker) AddSuspicion(playerID int, cc []cards.Card) int {
+	if playerID == kt.MyPlayerID {
+		return 0
+	}
+	pool := kt.PossibleOpponentCards()
+	poolCount := map[cards.Rank]int{}
+	for _, c := range pool {
+		poolCount[c.Rank]++
+	}
+	for pid, susp := range kt.Suspicions {
+		if pid == playerID {
+			continue
+		}
+		for _, c := range susp {
+			poolCount[c.Rank]--
+		}
+	}
+	suspCount := map[cards.Rank]int{}
+	for _, c := range kt.Suspicions[playerID] {
+		suspCount[c.Rank]++
+	}
+	added := 0
+	for _, c := range cc {
+		available := poolCount[c.Rank] - suspCount[c.Rank]
+		if available > 0 {
+			kt.Suspicions[playerID] = append(kt.Suspicions[playerID], c)
+			suspCount[c.Rank]++
+			added++
+		}
+	}
+	return added
+}
+
+func (kt *KnowledgeTracker) ClearSuspicions(playerID int) {
+	kt.Suspicions[playerID] = nil
+}
+
+func (kt *KnowledgeTracker) AddExclusion(playerID int, cc []cards.Card) int {
+	if playerID == kt.MyPlayerID {
+		return 0
+	}
+	if kt.Exclusions[playerID] == nil {
+		kt.Exclusions[playerID] = map[cards.Rank]int{}
+	}
+	added := 0
+	for _, c := range cc {
+		pool := kt.PossibleOpponentCards()
+		poolCount := 0
+		for _, p := range pool {
+			if p.Rank == c.Rank {
+				poolCount++
+			}
+		}
+		current := kt.Exclusions[playerID][c.Rank]
+		if current < poolCount {
+			kt.Exclusions[playerID][c.Rank]++
+			added++
+		}
+	}
+	return added
+}
+
+func (kt *KnowledgeTracker) ClearExclusions(playerID int) {
+	kt.Exclusions[playerID] = nil
+}
+
+func (kt *KnowledgeTracker) ExcludedRanks(playerID int) map[cards.Rank]bool {
+	excluded := map[cards.Rank]bool{}
+	for _, pr := range kt.PassRecords[playerID] {
+		// Joker: bij een pass op single altijd reset-mogelijkheid gemist → exclude.
+		// Bij pair: joker helpt niet direct → niet excluden.
+		if pr.Count == 1 {
+			excluded[cards.RankJoker] = true
+		}
+		// Wildcard (2): alleen excluden bij hoge tafel (≥ Queen).
+		// Bij lage tafel is het slim om wild te bewaren — geen bewijs dat je er geen hebt.
+		if pr.Count == 1 && pr.TableRank >= cards.RankQueen {
+			excluded[cards.RankTwo] = true
+		}
+		// Bij single-pass: sluit hogere ranks uit (ze hadden die kunnen spelen)
+		// Bij pair-pass: NIET excluden — ze kunnen singles van hoge ranks hebben,
+		// alleen geen PAIRS. De exclusie-map is binair en kan dat onderscheid niet maken.
+		if pr.Count == 1 {
+			for _, r := range cards.NormalRanks() {
+				if r > pr.TableRank {
+					excluded[r] = true
+				}
+			}
+		}
+	}
+	pool := kt.PossibleOpponentCards()
+	poolCount := map[cards.Rank]int{}
+	for _, c := range pool {
+		poolCount[c.Rank]++
+	}
+	for rank, exclCount := range kt.Exclusions[playerID] {
+		if exclCount > 0 {
+			excluded[rank] = true
+		}
+		_ = poolCount
+	}
+	return excluded
+}
+
+func (kt *KnowledgeTracker) updateSuspicions(played []cards.Card) {
+	playedCount := map[cards.Rank]int{}
+	for _, c := range played {
+		playedCount[c.Rank]++
+	}
+	for pid, suspected := range kt.Suspicions {
+		if len(suspected) == 0 {
+			continue
+		}
+		removed := map[cards.Rank]int{}
+		var newSusp []cards.Card
+		for _, c := range suspected {
+			if removed[c.Rank] < playedCount[c.Rank] {
+				removed[c.Rank]++
+			} else {
+				newSusp = append(newSusp, c)
+			}
+		}
+		kt.Suspicions[pid] = newSusp
+	}
+	for pid, exclMap := range kt.Exclusions {
+		if exclMap == nil {
+			continue
+		}
+		for rank, count := range exclMap {
+			played := playedCount[rank]
+			if played > 0 && count > 0 {
+				newCount := count - played
+				if newCount <= 0 {
+					delete(exclMap, rank)
+				} else {
+					exclMap[rank] = newCount
+				}
+			}
+		}
+		kt.Exclusions[pid] = exclMap
+	}
+}
+
+func (kt *KnowledgeTracker) PossibleOpponentCards() []cards.Card {
+	knownCount := map[cards.Rank]int{}
+	for _, c := range kt.MyHand.Cards {
+		knownCount[c.Rank]++
+	}
+	for _, c := range kt.CardsPlayed {
+		knownCount[c.Rank]++
+	}
+	for _, c := range kt.DeadCards {
+		knownCount[c.Rank]++
+	}
+	numDecks := 1
+	if kt.NumPlayers == 4 {
+		numDecks = 2
+	}
+	normalRanks := []cards.Rank{
+		cards.RankThree, cards.RankFour, cards.RankFive, cards.RankSix, cards.RankSeven,
+		cards.RankEight, cards.RankNine, cards.RankTen, cards.RankJack, cards.RankQueen, cards.RankKing,
+		cards.RankAce, cards.RankTwo, // beide aanwezig in 4 exemplaren per deck
+	}
+	totalCount := map[cards.Rank]int{}
+	for _, r := range normalRanks {
+		totalCount[r] = 4 * numDecks
+	}
+	totalCount[cards.RankJoker] = 2 * numDecks
+	var possible []cards.Card
+	allRanks := append(normalRanks, cards.RankJoker)
+	for _, r := range allRanks {
+		available := totalCount[r] - knownCount[r]
+		for i := 0; i < available; i++ {
+			possible = append(possible, cards.Card{Rank: r})
+		}
+	}
+	return possible
+}
+
+// CheckAvailable faalt als cc meer kaarten van een rank bevat dan er nog
+// onbekend zijn (niet in de eigen hand, niet gespeeld en niet dood): zo'n
+// zet van een tegenstander kan niet.
+func (kt *KnowledgeTracker) CheckAvailable(cc []cards.Card) error {
+	pool := map[cards.Rank]int{}
+	for _, c := range kt.PossibleOpponentCards() {
+		pool[c.Rank]++
+	}
+	need := map[cards.Rank]int{}
+	for _, c := range cc {
+		need[c.Rank]++
+		if need[c.Rank] > pool[c.Rank] {
+			return fmt.Errorf("nog maar %d× %s onbekend (niet in jouw hand, niet gespeeld)", pool[c.Rank], c)
+		}
+	}
+	return nil
+}
+
+func (kt *KnowledgeTracker) TotalOpponentCards() int {
+	total := 0
+	for i, count := range kt.HandCounts {
+		if i != kt.MyPlayerID {
+			total += count
+		}
+	}
+	return total
+}
@@ -0,0 +1,456 @@
+// Package protocol implementeert het JSON-lines engineprotocol waarmee een
+// ander proces (GUI, bot) de engine aanstuurt via stdin/stdout, in de geest
+// van UCI bij schaken.
+//
+// Elke regel is één JSON-object met een "cmd"-veld; elk antwoord is één
+// JSON-object met een "type"-veld. Spelers zijn 0-based, kaarten staan in de
+// gewone notatie ("K K", "0", "2 2"). Een optioneel "id" wordt in het
+// antwoord teruggestuurd.
+//
+//	{"cmd":"newgame","players":2,"seat":0,"hand":"33455789XXJQKK1220","start":1}
+//	{"cmd":"move","player":1,"cards":"8 8"}
+//	{"cmd":"move","player":0,"pass":true}
+//	{"cmd":"move","player":1,"cards":"0","follow":"5 5"}
+//	{"cmd":"go","iterations":20000,"time_ms":3000}
+//	{"cmd":"go","time_ms":10000,"progress":true}
+//	{"cmd":"stop"}
+//	{"cmd":"setsuspicion","player":1,"cards":"K K"}
+//	{"cmd":"setexclusion","player":1,"clear":true}
+//	{"cmd":"undo"}
+//	{"cmd":"quit"}
+package protocol
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/engine"
+	"github.com/azen-engine/game"
+	"github.com/azen-engine/knowledge"
+	"github.com/azen-engine/session"
+)
+
+// Request is één commando van de client.
+type Request struct {
+	ID  string `json:"id,omitempty"`
+	Cmd string `json:"cmd"`
+
+	// newgame
+	Players int    `json:"players,omitempty"`
+	Seat    int    `json:"seat,omitempty"`
+	Hand    string `json:"hand,omitempty"`
+	Start   int    `json:"start,omitempty"`
+	Counts  []int  `json:"counts,omitempty"`
+	Dead    string `json:"dead,omitempty"`
+
+	// move, setsuspicion, setexclusion
+	Player int    `json:"player,omitempty"`
+	Cards  string `json:"cards,omitempty"`
+	Pass   bool   `json:"pass,omitempty"`
+	Follow string `json:"follow,omitempty"`
+	Clear  bool   `json:"clear,omitempty"`
+
+	// go
+	Iterations int   `json:"iterations,omitempty"`
+	TimeMs     int   `json:"time_ms,omitempty"`
+	Workers    int   `json:"workers,omitempty"`
+	Seed       int64 `json:"seed,omitempty"`
+	// Progress vraagt tijdens de zoektocht periodiek een "progress"-antwoord
+	// met de huidige beste zet en de statistieken per kandidaat.
+	Progress bool `json:"progress,omitempty"`
+}
+
+// WireMove is een zet zoals die over de lijn gaat.
+type WireMove struct {
+	Player int    `json:"player"`
+	Cards  string `json:"cards,omitempty"`
+	Pass   bool   `json:"pass,omitempty"`
+}
+
+// WireDetail is één kandidaat-zet uit MoveEval.Details.
+type WireDetail struct {
+	Move    WireMove `json:"move"`
+	WinRate float64  `json:"win_rate"`
+	Visits  int      `json:"visits"`
+}
+
+// Response is één antwoord van de engine.
+type Response struct {
+	ID    string `json:"id,omitempty"`
+	Type  string `json:"type"`
+	Error string `json:"error,omitempty"`
+
+	// bestmove; Score en Visits staan er ook bij 0, zodat een verloren
+	// stelling niet lijkt op een antwoord zonder score
+	Move           *WireMove    `json:"move,omitempty"`
+	Follow         *WireMove    `json:"follow,omitempty"`
+	Score          *float64     `json:"score,omitempty"`
+	Visits         *int         `json:"visits,omitempty"`
+	ForcedWinDepth int          `json:"forced_win_depth,omitempty"`
+	Details        []WireDetail `json:"details,omitempty"`
+	Seed           int64        `json:"seed,omitempty"` // herhaalt de zoektocht via go.seed
+
+	// ok na newgame/move/undo/redo: wie is aan de beurt
+	Turn     *int  `json:"turn,omitempty"`
+	GameOver bool  `json:"game_over,omitempty"`
+	Ranking  []int `json:"ranking,omitempty"`
+}
+
+// ToWire zet een game.Move om naar de lijnvorm.
+func ToWire(m game.Move) WireMove {
+	if m.IsPass {
+		return WireMove{Player: m.PlayerID, Pass: true}
+	}
+	return WireMove{Player: m.PlayerID, Cards: game.FormatMove(m)}
+}
+
+// Session is de toestand van één protocolverbinding.
+type Session struct {
+	Config engine.Config // basisconfig; go-parameters overschrijven per zoektocht
+
+	out   *json.Encoder
+	outMu sync.Mutex
+
+	mu     sync.Mutex
+	game   *session.Game
+	cancel context.CancelFunc // niet-nil tijdens een zoektocht
+	done   chan struct{}
+}
+
+// NewSession maakt een sessie die antwoorden naar w schrijft.
+func NewSession(cfg engine.Config, w io.Writer) *Session {
+	return &Session{Config: cfg, out: json.NewEncoder(w)}
+}
+
+// Run leest commando's van r tot EOF of "quit". Bij EOF wordt een lopende
+// zoektocht afgewacht, zodat ook een gepipete "go" zijn bestmove geeft; bij
+// "quit" wordt hij eerst gestopt.
+func (s *Session) Run(r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		var req Request
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			s.send(Response{Type: "error", Error: fmt.Sprintf("ongeldige JSON: %v", err)})
+			continue
+		}
+		if req.Cmd == "quit" {
+			s.wait(true)
+			return nil
+		}
+		s.handle(req)
+	}
+	s.wait(false)
+	return sc.Err()
+}
+
+func (s *Session) send(resp Response) {
+	s.outMu.Lock()
+	defer s.outMu.Unlock()
+	s.out.Encode(resp)
+}
+
+func (s *Session) fail(req Request, format string, args ...any) {
+	s.send(Response{ID: req.ID, Type: "error", Error: fmt.Sprintf(format, args...)})
+}
+
+func (s *Session) handle(req Request) {
+	switch req.Cmd {
+	case "stop":
+		s.mu.Lock()
+		if s.cancel != nil {
+			s.cancel()
+		}
+		s.mu.Unlock()
+		return
+	case "isready":
+		s.send(Response{ID: req.ID, Type: "readyok"})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.fail(req, "zoektocht bezig; stuur eerst stop")
+		return
+	}
+	switch req.Cmd {
+	case "newgame":
+		s.newGame(req)
+	case "move":
+		s.move(req)
+	case "go":
+		s.goSearch(req)
+	case "setsuspicion", "setexclusion":
+		s.guess(req)
+	case "undo", "redo":
+		s.takeBack(req)
+	default:
+		s.fail(req, "onbekend commando %q", req.Cmd)
+	}
+}
+
+func (s *Session) okTurn(req Request) {
+	gs := s.game.GS
+	turn := gs.CurrentTurn
+	resp := Response{ID: req.ID, Type: "ok", Turn: &turn, GameOver: gs.GameOver}
+	if gs.GameOver {
+		resp.Ranking = gs.Ranking
+	}
+	s.send(resp)
+}
+
+func (s *Session) newGame(req Request) {
+	hand, err := cards.ParseCards(req.Hand)
+	if err != nil {
+		s.fail(req, "hand: %v", err)
+		return
+	}
+	dead, err := cards.ParseCards(req.Dead)
+	if err != nil {
+		s.fail(req, "dead: %v", err)
+		return
+	}
+	g, err := session.Open(session.Setup{
+		Players: req.Players,
+		Seat:    req.Seat,
+		Start:   req.Start,
+		Hand:    hand,
+		Counts:  req.Counts,
+		Dead:    dead,
+	})
+	if err != nil {
+		s.fail(req, "%v", err)
+		return
+	}
+	s.game = g
+	s.okTurn(req)
+}
+
+func (s *Session) move(req Request) {
+	if s.game == nil {
+		s.fail(req, "geen partij; stuur eerst newgame")
+		return
+	}
+	m, err := session.ParseMove(req.Player, req.Cards, req.Pass)
+	if err != nil {
+		s.fail(req, "move: %v", err)
+		return
+	}
+	if err := s.game.Apply(m); err != nil {
+		s.fail(req, "move: %v", err)
+		return
+	}
+	if req.Follow != "" {
+		f, err := session.ParseMove(req.Player, req.Follow, false)
+		if err == nil {
+			err = s.game.ApplyFollow(f)
+		}
+		if err != nil {
+			s.fail(req, "follow: %v", err)
+			return
+		}
+	}
+	s.okTurn(req)
+}
+
+// takeBack neemt de laatste zet terug (undo) of speelt hem opnieuw (redo).
+// Het antwoord is ok met de betrokken zet in move/follow.
+func (s *Session) takeBack(req Request) {
+	if s.game == nil {
+		s.fail(req, "geen partij; stuur eerst newgame")
+		return
+	}
+	var moves []game.Move
+	var err error
+	if req.Cmd == "undo" {
+		moves, err = s.game.Undo()
+	} else {
+		moves, err = s.game.Redo()
+	}
+	if err != nil {
+		s.fail(req, "%s: %v", req.Cmd, err)
+		return
+	}
+	gs := s.game.GS
+	turn := gs.CurrentTurn
+	resp := Response{ID: req.ID, Type: "ok", Turn: &turn, GameOver: gs.GameOver}
+	if gs.GameOver {
+		resp.Ranking = gs.Ranking
+	}
+	m := ToWire(moves[0])
+	resp.Move = &m
+	if len(moves) > 1 {
+		f := ToWire(moves[1])
+		resp.Follow = &f
+	}
+	s.send(resp)
+}
+
+func (s *Session) guess(req Request) {
+	if s.game == nil {
+		s.fail(req, "geen partij; stuur eerst newgame")
+		return
+	}
+	if err := s.game.CheckOpponent(req.Player); err != nil {
+		s.fail(req, "%v", err)
+		return
+	}
+	suspicion := req.Cmd == "setsuspicion"
+	if req.Clear {
+		if suspicion {
+			s.game.ClearSuspicions(req.Player)
+		} else {
+			s.game.ClearExclusions(req.Player)
+		}
+		s.send(Response{ID: req.ID, Type: "ok"})
+		return
+	}
+	cc, err := cards.ParseCards(req.Cards)
+	if err == nil {
+		if suspicion {
+			err = s.game.Suspect(req.Player, cc)
+		} else {
+			err = s.game.Exclude(req.Player, cc)
+		}
+	}
+	if err != nil {
+		s.fail(req, "%s: %v", req.Cmd, err)
+		return
+	}
+	s.send(Response{ID: req.ID, Type: "ok"})
+}
+
+func (s *Session) goSearch(req Request) {
+	if s.game == nil {
+		s.fail(req, "geen partij; stuur eerst newgame")
+		return
+	}
+	gs, tracker, me := s.game.GS, s.game.Tracker, s.game.MyPlayer
+	if gs.GameOver {
+		s.fail(req, "de partij is voorbij")
+		return
+	}
+	if gs.CurrentTurn != me {
+		s.fail(req, "speler %d is aan de beurt, niet %d", gs.CurrentTurn, me)
+		return
+	}
+	eng := engine.NewEngine(s.searchConfig(req, gs.NumPlayers))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	s.cancel, s.done = cancel, done
+	var progress func(Response)
+	if req.Progress {
+		progress = s.send
+	}
+
+	go func() {
+		defer close(done)
+		defer cancel()
+		resp := RunSearch(ctx, eng, gs, tracker, req.ID, progress)
+		s.mu.Lock()
+		s.cancel, s.done = nil, nil
+		s.mu.Unlock()
+		s.send(resp)
+	}()
+}
+
+// RunSearch zoekt de beste zet tot ctx afloopt en bouwt het
+// "bestmove"-antwoord. progress (nil = geen) krijgt onderweg de
+// tussenstanden als "progress"-antwoord. Na een joker-reset opent dezelfde
+// speler opnieuw: dan wordt ook de beste vervolg-zet meegegeven, tenzij de
+// zoektocht afgebroken werd.
+func RunSearch(ctx context.Context, eng *engine.Engine, gs *game.GameState, tracker *knowledge.KnowledgeTracker, id string, progress func(Response)) Response {
+	var final engine.SearchUpdate
+	for u := range eng.Search(ctx, gs, tracker) {
+		if u.Done {
+			final = u
+		} else if progress != nil {
+			resp := BestMoveResponse(id, u.Best, u.Eval)
+			resp.Type = "progress"
+			progress(resp)
+		}
+	}
+	resp := BestMoveResponse(id, final.Best, final.Eval)
+	resp.Seed = eng.Seed()
+	if ctx.Err() == nil {
+		if follow, ok := eng.BestFollowContext(ctx, gs, tracker, final.Best); ok {
+			fw := ToWire(follow)
+			resp.Follow = &fw
+		}
+	}
+	return resp
+}
+
+// searchConfig past de go-parameters van req toe op de basisconfig.
+func (s *Session) searchConfig(req Request, numPlayers int) engine.Config {
+	return SearchConfig(s.Config, numPlayers, req)
+}
+
+// SearchConfig past de go-parameters van req (iterations, time_ms, workers,
+// seed) toe op cfg. Met enkel een tijdslimiet zoekt de engine tot de tijd op
+// is; 0 betekent "basisconfig behouden".
+func SearchConfig(cfg engine.Config, numPlayers int, req Request) engine.Config {
+	iterations, timeMs, workers := req.Iterations, req.TimeMs, req.Workers
+	cfg.NumPlayers = numPlayers
+	if iterations > 0 {
+		cfg.Iterations = iterations
+	}
+	if timeMs > 0 {
+		cfg.MaxTime = time.Duration(timeMs) * time.Millisecond
+		if iterations == 0 {
+			cfg.Iterations = 1 << 30
+		}
+	}
+	if workers > 0 {
+		cfg.NumWorkers = workers
+	}
+	if req.Seed != 0 {
+		cfg.Seed = req.Seed
+	}
+	return cfg
+}
+
+// BestMoveResponse zet het resultaat van een zoektocht om naar een
+// "bestmove"-antwoord (zonder vervolg-zet).
+func BestMoveResponse(id string, best game.Move, eval engine.MoveEval) Response {
+	resp := Response{
+		ID:             id,
+		Type:           "bestmove",
+		Score:          &eval.Score,
+		Visits:         &eval.Visits,
+		ForcedWinDepth: eval.ForcedWinDepth,
+	}
+	bm := ToWire(best)
+	resp.Move = &bm
+	for _, d := range eval.Details {
+		resp.Details = append(resp.Details, WireDetail{Move: ToWire(d.Move), WinRate: d.WinRate, Visits: d.Visits})
+	}
+	return resp
+}
+
+// wait wacht tot een lopende zoektocht klaar is; met stop wordt hij eerst
+// afgebroken.
+func (s *Session) wait(stop bool) {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	if stop && cancel != nil {
+		cancel()
+	}
+	s.mu.Unlock()
+	if done == nil {
+		return
+	}
+	<-done
+}
@@ -0,0 +1,181 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/azen-engine/engine"
+	"github.com/azen-engine/game"
+)
+
+// testHand is de hand van speler 0 in de tests.
+const testHand = `"hand":"33455789XXJQKK1220"`
+
+// run stuurt lines naar een nieuwe sessie en geeft de antwoorden terug.
+func run(t *testing.T, lines ...string) []Response {
+	t.Helper()
+	cfg := engine.DefaultConfig(2)
+	cfg.Iterations = 200
+	cfg.NumWorkers = 1
+	cfg.Seed = 1
+	var out bytes.Buffer
+	if err := NewSession(cfg, &out).Run(strings.NewReader(strings.Join(lines, "\n"))); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	var resps []Response
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var r Response
+		if err := dec.Decode(&r); err != nil {
+			t.Fatalf("antwoord: %v", err)
+		}
+		resps = append(resps, r)
+	}
+	return resps
+}
+
+// checkBestMove faalt als r geen bestmove met score en een legale niet-pass
+// zet van speler 0 is.
+func checkBestMove(t *testing.T, r Response) {
+	t.Helper()
+	if r.Type != "bestmove" || r.Move == nil || r.Score == nil || r.Visits == nil {
+		t.Fatalf("verwacht bestmove met zet en score, kreeg %+v", r)
+	}
+	if r.Move.Player != 0 || r.Move.Pass || r.Move.Cards == "" {
+		t.Errorf("bestmove %+v, verwacht een zet van speler 0", *r.Move)
+	}
+}
+
+func TestSession(t *testing.T) {
+	newGame := `{"cmd":"newgame","players":2,"seat":0,` + testHand + `}`
+	tests := []struct {
+		name  string
+		lines []string
+		types []string // verwachte antwoordtypes
+		check func(t *testing.T, resps []Response)
+	}{
+		{
+			name:  "newgame en zetten",
+			lines: []string{newGame, `{"id":"a","cmd":"move","player":0,"cards":"3 3"}`, `{"cmd":"move","player":1,"pass":true}`},
+			types: []string{"ok", "ok", "ok"},
+			check: func(t *testing.T, resps []Response) {
+				if resps[1].ID != "a" || resps[1].Turn == nil || *resps[1].Turn != 1 {
+					t.Errorf("na 3 3: %+v, verwacht id a en speler 1 aan zet", resps[1])
+				}
+				if *resps[2].Turn != 0 {
+					t.Errorf("na pass: speler %d aan zet, verwacht 0", *resps[2].Turn)
+				}
+			},
+		},
+		{
+			name:  "ongeldige zet",
+			lines: []string{newGame, `{"cmd":"move","player":0,"cards":"6"}`, `{"cmd":"move","player":1,"cards":"4"}`},
+			types: []string{"ok", "error", "error"},
+		},
+		{
+			name:  "zonder partij",
+			lines: []string{`{"cmd":"go"}`, `{"cmd":"move","player":0,"pass":true}`, `{"cmd":"bla"}`, `geen json`},
+			types: []string{"error", "error", "error", "error"},
+		},
+		{
+			name:  "go",
+			lines: []string{newGame, `{"id":"z","cmd":"go"}`},
+			types: []string{"ok", "bestmove"},
+			check: func(t *testing.T, resps []Response) {
+				checkBestMove(t, resps[1])
+				if resps[1].ID != "z" || len(resps[1].Details) == 0 || resps[1].Seed != 1 {
+					t.Errorf("bestmove %+v, verwacht id z, details en seed 1", resps[1])
+				}
+			},
+		},
+		{
+			// Een gepipete go zonder stop of quit: Run wacht bij EOF op de
+			// zoektocht in plaats van hem af te breken.
+			name:  "go tot EOF",
+			lines: []string{newGame, `{"cmd":"go","iterations":300}`},
+			types: []string{"ok", "bestmove"},
+			check: func(t *testing.T, resps []Response) {
+				checkBestMove(t, resps[1])
+				if *resps[1].Visits == 0 {
+					t.Error("zoektocht afgebroken bij EOF")
+				}
+			},
+		},
+		{
+			name:  "go en stop",
+			lines: []string{newGame, `{"cmd":"go","iterations":100000000}`, `{"cmd":"stop"}`},
+			types: []string{"ok", "bestmove"},
+			check: func(t *testing.T, resps []Response) { checkBestMove(t, resps[1]) },
+		},
+		{
+			name:  "go en quit",
+			lines: []string{newGame, `{"cmd":"go","iterations":100000000}`, `{"cmd":"quit"}`, `{"cmd":"isready"}`},
+			types: []string{"ok", "bestmove"},
+			check: func(t *testing.T, resps []Response) { checkBestMove(t, resps[1]) },
+		},
+		{
+			name:  "go niet aan de beurt",
+			lines: []string{newGame, `{"cmd":"move","player":0,"cards":"3 3"}`, `{"cmd":"go"}`},
+			types: []string{"ok", "ok", "error"},
+		},
+		{
+			name: "vermoedens en uitsluitingen",
+			lines: []string{
+				newGame,
+				`{"cmd":"setsuspicion","player":1,"cards":"K"}`,
+				`{"cmd":"setexclusion","player":1,"cards":"Q"}`,
+				`{"cmd":"setsuspicion","player":1,"clear":true}`,
+				`{"cmd":"setexclusion","player":0,"cards":"Q"}`,
+				`{"cmd":"setsuspicion","player":1,"cards":"K K K"}`,
+			},
+			types: []string{"ok", "ok", "ok", "ok", "error", "error"},
+		},
+		{
+			name:  "undo en redo",
+			lines: []string{newGame, `{"cmd":"move","player":0,"cards":"0","follow":"3 3"}`, `{"cmd":"undo"}`, `{"cmd":"redo"}`, `{"cmd":"redo"}`},
+			types: []string{"ok", "ok", "ok", "ok", "error"},
+			check: func(t *testing.T, resps []Response) {
+				u := resps[2]
+				if u.Move == nil || u.Move.Cards != "0" || u.Follow == nil || u.Follow.Cards != "3 3" || *u.Turn != 0 {
+					t.Errorf("undo: %+v, verwacht 0 / 3 3 terug en speler 0 aan zet", u)
+				}
+				if *resps[3].Turn != 1 {
+					t.Errorf("redo: speler %d aan zet, verwacht 1", *resps[3].Turn)
+				}
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resps := run(t, tc.lines...)
+			var types []string
+			for _, r := range resps {
+				types = append(types, r.Type)
+			}
+			if strings.Join(types, " ") != strings.Join(tc.types, " ") {
+				t.Fatalf("antwoorden %v, verwacht %v (%+v)", types, tc.types, resps)
+			}
+			if tc.check != nil {
+				tc.check(t, resps)
+			}
+		})
+	}
+}
+
+func TestBestMoveResponseKeepsZeroScore(t *testing.T) {
+	data, err := json.Marshal(BestMoveResponse("", game.PassMove(0), engine.MoveEval{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{`"score":0`, `"visits":0`} {
+		if !strings.Contains(string(data), field) {
+			t.Errorf("%s ontbreekt in %s", field, data)
+		}
+	}
+	data, _ = json.Marshal(Response{Type: "ok"})
+	if strings.Contains(string(data), "score") {
+		t.Errorf("ok-antwoord met score: %s", data)
+	}
+}
@@ -0,0 +1,181 @@
+package server
+
+import "net/http"
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(indexHTML))
+}
+
+// indexHTML is een eenvoudige pagina voor de telefoon: partij starten, zetten
+// invoeren, beste zet met live voortgang, kennis bekijken en een log laten
+// analyseren. Alles gaat via de JSON-API hierboven.
+const indexHTML = `<!DOCTYPE html>
+<html lang="nl">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>AZEN</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 0; padding: 12px; background: #111; color: #eee; max-width: 640px; }
+h1 { font-size: 1.4em; margin: 0 0 8px; }
+h2 { font-size: 1.1em; margin: 16px 0 6px; border-bottom: 1px solid #333; }
+input, select, button, textarea { font-size: 1em; padding: 6px; margin: 2px 0; background: #222; color: #eee; border: 1px solid #444; border-radius: 4px; }
+input[type=text], textarea { width: 100%; box-sizing: border-box; }
+button { background: #2a5; border: none; color: #fff; }
+button.alt { background: #555; }
+.row { display: flex; gap: 6px; align-items: center; flex-wrap: wrap; }
+.err { color: #f66; }
+.bar { background: #2a5; height: 6px; }
+table { width: 100%; border-collapse: collapse; }
+td { padding: 2px 4px; border-bottom: 1px solid #222; }
+pre { white-space: pre-wrap; font-size: .85em; }
+</style>
+</head>
+<body>
+<h1>🂡 AZEN</h1>
+<div id="err" class="err"></div>
+
+<div id="setup">
+<h2>Nieuwe partij</h2>
+<div class="row">Spelers <select id="players"><option>2</option><option>3</option><option>4</option></select>
+Jij bent speler <select id="seat"></select> Begint <select id="start"></select></div>
+<input type="text" id="hand" placeholder="Jouw kaarten, bv. 3345578 9XJQKK1220">
+<input type="text" id="counts" placeholder="Startkaarten per speler (leeg = 18 elk), bv. 18,18">
+<button onclick="create()">Start</button>
+</div>
+
+<div id="game" hidden>
+<h2>Partij</h2>
+<div id="status"></div>
+<div class="row">Speler <select id="mplayer"></select>
+<input type="text" id="mcards" placeholder="Kaarten (leeg = pas), bv. K K of 0/5 5" style="flex:1"></div>
+<div class="row"><button onclick="move()">Zet invoeren</button>
+<button onclick="best()">Beste zet</button>
+<button class="alt" id="undo" onclick="takeBack('undo')">↩️ Undo</button>
+<button class="alt" id="redo" onclick="takeBack('redo')">↪️ Redo</button>
+<button class="alt" onclick="send({cmd:'stop'})">Stop</button>
+<button class="alt" onclick="know()">Kennis</button>
+<button class="alt" onclick="leave()">Nieuwe partij</button></div>
+<div id="best"></div>
+<pre id="knowledge"></pre>
+</div>
+
+<h2>Partij analyseren</h2>
+<input type="file" id="logfile">
+<textarea id="logtext" rows="4" placeholder="of plak hier een AZEN GAME LOG"></textarea>
+<div class="row">Spelers (0-based, leeg = alle) <input type="text" id="aplayers" style="width:6em">
+Iteraties <input type="text" id="aiters" value="3000" style="width:6em">
+<button onclick="analyze()">Analyseer</button></div>
+<div id="analysis"></div>
+
+<script>
+let sid = null, ws = null, st = null;
+const $ = id => document.getElementById(id);
+function err(msg) { $('err').textContent = msg || ''; }
+function opts(sel, n) { sel.innerHTML = ''; for (let i = 0; i < n; i++) sel.add(new Option('Speler ' + (i + 1), i)); }
+function setupOpts() { const n = +$('players').value; opts($('seat'), n); opts($('start'), n); }
+$('players').onchange = setupOpts; setupOpts();
+
+async function api(method, path, body) {
+  const r = await fetch(path, {method, headers: {'Content-Type': 'application/json'}, body: body === undefined ? undefined : (typeof body === 'string' ? body : JSON.stringify(body))});
+  if (r.status === 204) return null;
+  const j = await r.json();
+  if (!r.ok) throw new Error(j.error || r.statusText);
+  return j;
+}
+function mv(m) { return m.pass ? 'pas' : m.cards; }
+
+async function create() {
+  err();
+  const body = {players: +$('players').value, seat: +$('seat').value, start: +$('start').value, hand: $('hand').value};
+  const c = $('counts').value.trim();
+  if (c) body.counts = c.split(',').map(x => +x);
+  try { show(await api('POST', '/api/sessions', body)); } catch (e) { err(e.message); return; }
+  $('setup').hidden = true; $('game').hidden = false;
+  const proto = location.protocol === 'https:' ? 'wss:' : 'ws:';
+  ws = new WebSocket(proto + '//' + location.host + '/api/sessions/' + sid + '/ws');
+  ws.onmessage = ev => onMsg(JSON.parse(ev.data));
+}
+function show(s) {
+  st = s; sid = s.id;
+  opts($('mplayer'), s.players); $('mplayer').value = s.turn;
+  const t = s.table.open ? 'tafel leeg' : s.table.count + '× ' + s.table.rank;
+  let h = 'Jouw hand: <b>' + (s.hand || '—') + '</b><br>Kaarten: ' + s.hand_counts.map((n, i) => 'S' + (i + 1) + '=' + n).join(' ') +
+    '<br>Tafel: ' + t + ' · aan de beurt: <b>Speler ' + (s.turn + 1) + '</b>' + (s.turn === s.seat ? ' (jij)' : '');
+  if (s.game_over) h += '<br>🏁 Uitslag: ' + s.ranking.map(p => 'Speler ' + (p + 1)).join(', ');
+  h += '<br><small>' + s.history.map(m => 'S' + (m.player + 1) + ':' + mv(m)).join(' ') + '</small>';
+  $('status').innerHTML = h;
+  $('undo').disabled = !s.can_undo;
+  $('redo').disabled = !s.can_redo;
+}
+async function move() {
+  err();
+  const raw = $('mcards').value.trim();
+  const [main, follow] = raw.split('/');
+  const body = {player: +$('mplayer').value};
+  if (!main || ['p', '-', 'pas', 'pass'].includes(main.trim().toLowerCase())) body.pass = true; else body.cards = main;
+  if (follow) body.follow = follow;
+  try { show(await api('POST', '/api/sessions/' + sid + '/moves', body)); $('mcards').value = ''; } catch (e) { err(e.message); }
+}
+async function takeBack(what) {
+  err();
+  try { show(await api('POST', '/api/sessions/' + sid + '/' + what)); } catch (e) { err(e.message); }
+}
+function send(req) { if (ws && ws.readyState === 1) ws.send(JSON.stringify(req)); }
+function best() { err(); $('best').innerHTML = '⏳ rekenen…'; send({cmd: 'go'}); }
+function onMsg(m) {
+  if (m.type === 'error') { err(m.error); return; }
+  if (m.type !== 'progress' && m.type !== 'bestmove') return;
+  const total = (m.details || []).reduce((a, d) => a + d.visits, 0) || 1;
+  let h = (m.type === 'bestmove' ? '✅ Beste zet: ' : '⏳ Voorlopig: ') + '<b>' + mv(m.move) + (m.follow ? ' / ' + mv(m.follow) : '') + '</b> (' + (m.score * 100).toFixed(1) + '%)';
+  if (m.forced_win_depth) h += ' ♟️ gedwongen winst in ' + m.forced_win_depth;
+  h += '<table>';
+  for (const d of (m.details || []).slice(0, 8)) {
+    h += '<tr><td>' + mv(d.move) + '</td><td>' + (d.win_rate * 100).toFixed(1) + '%</td><td style="width:40%"><div class="bar" style="width:' + (100 * d.visits / total).toFixed(0) + '%"></div></td><td>' + d.visits + '</td></tr>';
+  }
+  $('best').innerHTML = h + '</table>';
+}
+async function know() {
+  err();
+  try {
+    const k = await api('GET', '/api/sessions/' + sid + '/knowledge');
+    let t = 'Mogelijke kaarten bij tegenstanders: ' + k.possible + '\n';
+    for (const p in k.suspicions) {
+      t += 'Speler ' + (+p + 1) + ': ' + k.hand_counts[p] + ' kaarten · gespeeld: ' + (k.played[p] || '—') +
+        ' · vermoeden: ' + (k.suspicions[p] || '—') + ' · uitgesloten: ' + (k.excluded_ranks[p].join(' ') || '—') + '\n';
+    }
+    $('knowledge').textContent = t;
+  } catch (e) { err(e.message); }
+}
+async function leave() {
+  if (ws) ws.close();
+  if (sid) try { await api('DELETE', '/api/sessions/' + sid); } catch (e) {}
+  sid = null; $('game').hidden = true; $('setup').hidden = false; $('best').innerHTML = ''; $('knowledge').textContent = '';
+}
+async function analyze() {
+  err();
+  let text = $('logtext').value;
+  const f = $('logfile').files[0];
+  if (f) text = await f.text();
+  $('analysis').innerHTML = '⏳ analyseren…';
+  const q = new URLSearchParams({players: $('aplayers').value, iterations: $('aiters').value});
+  try {
+    const r = await api('POST', '/api/analyze?' + q, text);
+    const icon = {goed: '✅', onnauwkeurig: '⚠️', blunder: '❌'};
+    let h = '<table>';
+    for (const m of r.moves) {
+      h += '<tr><td>' + m.num + '</td><td>S' + (m.player + 1) + '</td><td>' + mv(m.move) + (m.follow ? ' / ' + mv(m.follow) : '') + '</td><td>' +
+        (m.grade ? icon[m.grade] + ' ' + (m.score * 100).toFixed(1) + '%' : '') + '</td><td>' +
+        (m.best ? 'beste: ' + mv(m.best) + (m.best_follow ? ' / ' + mv(m.best_follow) : '') + ' (' + (m.best_score * 100).toFixed(1) + '%)' : '') + '</td></tr>';
+    }
+    h += '</table>';
+    if (r.error) h += '<div class="err">' + r.error + '</div>';
+    if (r.game_over) h += '🏁 Uitslag: ' + r.ranking.map(p => 'Speler ' + (p + 1)).join(', ');
+    $('analysis').innerHTML = h;
+  } catch (e) { err(e.message); $('analysis').innerHTML = ''; }
+}
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,692 @@
+// Package server biedt de engine aan over HTTP, zodat je hem vanuit een
+// browser (bv. op de telefoon naast de speltafel) kunt gebruiken.
+//
+// De JSON-bodies gebruiken dezelfde velden als het engineprotocol
+// (protocol.Request); spelers zijn 0-based.
+//
+//	POST   /api/sessions                 nieuwe partij (players, seat, hand, start, counts, dead)
+//	GET    /api/sessions/{id}            toestand van de partij
+//	DELETE /api/sessions/{id}            partij verwijderen
+//	POST   /api/sessions/{id}/moves      waargenomen zet (player, cards|pass, follow)
+//	POST   /api/sessions/{id}/guess      vermoeden/uitsluiting (cmd: setsuspicion|setexclusion)
+//	GET    /api/sessions/{id}/knowledge  wat de KnowledgeTracker weet
+//	POST   /api/sessions/{id}/bestmove   beste zet (iterations, time_ms, workers, seed)
+//	POST   /api/sessions/{id}/stop       lopende zoektocht afbreken
+//	GET    /api/sessions/{id}/ws         WebSocket: {"cmd":"go"} streamt progress en bestmove
+//	POST   /api/analyze                  tekstlog uploaden en alle zetten laten beoordelen
+package server
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/azen-engine/analysis"
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/engine"
+	"github.com/azen-engine/game"
+	"github.com/azen-engine/gameio"
+	"github.com/azen-engine/protocol"
+	"github.com/azen-engine/session"
+)
+
+// maxSessions begrenst het aantal gelijktijdige partijen in het geheugen.
+const maxSessions = 64
+
+// maxBody is de maximale grootte van een request-body (ook voor uploads).
+const maxBody = 1 << 20
+
+var errBusy = errors.New("zoektocht bezig; stuur eerst stop")
+
+// Server houdt de lopende partijen bij en beantwoordt de HTTP-verzoeken.
+type Server struct {
+	Config engine.Config // basisconfig; zoekparameters overschrijven per zoektocht
+
+	mux      *http.ServeMux
+	mu       sync.Mutex
+	sessions map[string]*liveGame
+}
+
+// New maakt een server met cfg als basisconfiguratie.
+func New(cfg engine.Config) *Server {
+	s := &Server{Config: cfg, mux: http.NewServeMux(), sessions: map[string]*liveGame{}}
+	s.mux.HandleFunc("GET /{$}", s.handleIndex)
+	s.mux.HandleFunc("POST /api/sessions", s.handleCreate)
+	s.mux.HandleFunc("GET /api/sessions/{id}", s.withGame(s.handleState))
+	s.mux.HandleFunc("DELETE /api/sessions/{id}", s.handleDelete)
+	s.mux.HandleFunc("POST /api/sessions/{id}/moves", s.withGame(s.handleMove))
+	s.mux.HandleFunc("POST /api/sessions/{id}/undo", s.withGame(s.handleUndo))
+	s.mux.HandleFunc("POST /api/sessions/{id}/redo", s.withGame(s.handleRedo))
+	s.mux.HandleFunc("POST /api/sessions/{id}/guess", s.withGame(s.handleGuess))
+	s.mux.HandleFunc("GET /api/sessions/{id}/knowledge", s.withGame(s.handleKnowledge))
+	s.mux.HandleFunc("POST /api/sessions/{id}/bestmove", s.withGame(s.handleBestMove))
+	s.mux.HandleFunc("POST /api/sessions/{id}/stop", s.withGame(s.handleStop))
+	s.mux.HandleFunc("GET /api/sessions/{id}/ws", s.withGame(s.handleWS))
+	s.mux.HandleFunc("POST /api/analyze", s.handleAnalyze)
+	return s
+}
+
+// ServeHTTP weigert verzoeken die iets veranderen (alles behalve GET en
+// HEAD) van een andere origin, zodat een andere website in de browser van
+// de gebruiker geen partijen kan aanmaken of analyses kan starten.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead && !sameOrigin(r) {
+		writeError(w, http.StatusForbidden, "verzoek van een andere origin geweigerd")
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// sameOrigin meldt of een verzoek van de eigen pagina komt: de Origin-header
+// moet dezelfde host hebben als het verzoek. Browsers sturen die header mee
+// bij POST, DELETE en WebSocket-verbindingen; zonder Origin (geen browser,
+// bv. curl) wordt het verzoek aanvaard.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// liveGame is één partij op de server, met hooguit één lopende zoektocht.
+type liveGame struct {
+	id string
+
+	mu     sync.Mutex
+	game   *session.Game
+	cancel context.CancelFunc // niet-nil tijdens een zoektocht
+	done   chan struct{}
+}
+
+// ─── JSON ────────────────────────────────────────────────────────────────────
+
+// State is de toestand van een partij zoals de client die te zien krijgt.
+type State struct {
+	ID         string              `json:"id"`
+	Players    int                 `json:"players"`
+	Seat       int                 `json:"seat"`
+	Turn       int                 `json:"turn"`
+	GameOver   bool                `json:"game_over"`
+	Ranking    []int               `json:"ranking,omitempty"`
+	Hand       string              `json:"hand"`
+	HandCounts []int               `json:"hand_counts"`
+	Table      TableState          `json:"table"`
+	History    []protocol.WireMove `json:"history"`
+	Searching  bool                `json:"searching"`
+	CanUndo    bool                `json:"can_undo"`
+	CanRedo    bool                `json:"can_redo"`
+}
+
+// TableState is de lopende ronde: wat er op tafel ligt.
+type TableState struct {
+	Open       bool   `json:"open"` // niets op tafel: de speler aan de beurt opent
+	Count      int    `json:"count,omitempty"`
+	Rank       string `json:"rank,omitempty"`
+	LastPlayer int    `json:"last_player"`
+	Passes     int    `json:"passes"`
+}
+
+// Knowledge is de inhoud van de KnowledgeTracker.
+type Knowledge struct {
+	HandCounts    []int            `json:"hand_counts"`
+	Played        []string         `json:"played"` // per speler
+	Dead          string           `json:"dead,omitempty"`
+	PassRecords   [][]PassInfo     `json:"pass_records"`
+	Suspicions    map[int]string   `json:"suspicions"`
+	Exclusions    map[int]string   `json:"exclusions"`
+	ExcludedRanks map[int][]string `json:"excluded_ranks"`
+	Possible      string           `json:"possible"` // kaarten die nog bij tegenstanders kunnen zitten
+}
+
+// PassInfo is één pass van een tegenstander.
+type PassInfo struct {
+	Count     int    `json:"count"`
+	TableRank string `json:"table_rank"`
+}
+
+// AnalyzedMove is één beurt uit een geüploade partij.
+type AnalyzedMove struct {
+	Num            int                   `json:"num"`
+	Player         int                   `json:"player"`
+	Move           protocol.WireMove     `json:"move"`
+	Follow         *protocol.WireMove    `json:"follow,omitempty"`
+	Grade          string                `json:"grade,omitempty"` // leeg = niet geanalyseerd
+	Score          float64               `json:"score,omitempty"`
+	Best           *protocol.WireMove    `json:"best,omitempty"`
+	BestFollow     *protocol.WireMove    `json:"best_follow,omitempty"`
+	BestScore      float64               `json:"best_score,omitempty"`
+	ForcedWinDepth int                   `json:"forced_win_depth,omitempty"`
+	Top            []protocol.WireDetail `json:"top,omitempty"`
+}
+
+// AnalyzeResponse is het resultaat van POST /api/analyze.
+type AnalyzeResponse struct {
+	Players  int            `json:"players"`
+	Moves    []AnalyzedMove `json:"moves"`
+	GameOver bool           `json:"game_over"`
+	Ranking  []int          `json:"ranking,omitempty"`
+	Error    string         `json:"error,omitempty"` // log stopte op een ongeldige zet
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...any) {
+	writeJSON(w, status, protocol.Response{Type: "error", Error: fmt.Sprintf(format, args...)})
+}
+
+func readRequest(w http.ResponseWriter, r *http.Request) (protocol.Request, bool) {
+	var req protocol.Request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "ongeldige JSON: %v", err)
+		return req, false
+	}
+	return req, true
+}
+
+// ─── Partijen ────────────────────────────────────────────────────────────────
+
+func newID() string {
+	var b [8]byte
+	crand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// withGame zoekt de partij uit het pad op en geeft 404 als die niet bestaat.
+func (s *Server) withGame(h func(http.ResponseWriter, *http.Request, *liveGame)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		g := s.sessions[r.PathValue("id")]
+		s.mu.Unlock()
+		if g == nil {
+			writeError(w, http.StatusNotFound, "onbekende partij %q", r.PathValue("id"))
+			return
+		}
+		h(w, r, g)
+	}
+}
+
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	req, ok := readRequest(w, r)
+	if !ok {
+		return
+	}
+	hand, err := cards.ParseCards(req.Hand)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "hand: %v", err)
+		return
+	}
+	dead, err := cards.ParseCards(req.Dead)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "dead: %v", err)
+		return
+	}
+	sg, err := session.Open(session.Setup{
+		Players: req.Players,
+		Seat:    req.Seat,
+		Start:   req.Start,
+		Hand:    hand,
+		Counts:  req.Counts,
+		Dead:    dead,
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	g := &liveGame{id: newID(), game: sg}
+	s.mu.Lock()
+	if len(s.sessions) >= maxSessions {
+		s.mu.Unlock()
+		writeError(w, http.StatusServiceUnavailable, "te veel partijen (max %d); verwijder er eerst een", maxSessions)
+		return
+	}
+	s.sessions[g.id] = g
+	s.mu.Unlock()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	writeJSON(w, http.StatusCreated, g.state())
+}
+
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	g := s.sessions[r.PathValue("id")]
+	delete(s.sessions, r.PathValue("id"))
+	s.mu.Unlock()
+	if g == nil {
+		writeError(w, http.StatusNotFound, "onbekende partij %q", r.PathValue("id"))
+		return
+	}
+	g.stopAndWait()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleState(w http.ResponseWriter, r *http.Request, g *liveGame) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	writeJSON(w, http.StatusOK, g.state())
+}
+
+// state vat de partij samen; g.mu moet vastgehouden worden.
+func (g *liveGame) state() State {
+	gs := g.game.GS
+	st := State{
+		ID:         g.id,
+		Players:    gs.NumPlayers,
+		Seat:       g.game.MyPlayer,
+		Turn:       gs.CurrentTurn,
+		GameOver:   gs.GameOver,
+		Ranking:    gs.Ranking,
+		Hand:       cards.CardsToString(gs.Hands[g.game.MyPlayer].Cards),
+		HandCounts: make([]int, gs.NumPlayers),
+		History:    make([]protocol.WireMove, len(gs.History)),
+		Searching:  g.cancel != nil,
+		CanUndo:    g.game.CanUndo(),
+		CanRedo:    g.game.CanRedo(),
+		Table: TableState{
+			Open:       gs.Round.IsOpen,
+			LastPlayer: gs.Round.LastPlayerID,
+			Passes:     gs.Round.ConsecPasses,
+		},
+	}
+	if !gs.Round.IsOpen {
+		st.Table.Count = gs.Round.Count
+		st.Table.Rank = cards.Card{Rank: gs.Round.TableRank}.RankStr()
+	}
+	for i, h := range gs.Hands {
+		st.HandCounts[i] = h.Count()
+	}
+	for i, m := range gs.History {
+		st.History[i] = protocol.ToWire(m)
+	}
+	return st
+}
+
+func (s *Server) handleMove(w http.ResponseWriter, r *http.Request, g *liveGame) {
+	req, ok := readRequest(w, r)
+	if !ok {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.cancel != nil {
+		writeError(w, http.StatusConflict, "%v", errBusy)
+		return
+	}
+	m, err := session.ParseMove(req.Player, req.Cards, req.Pass)
+	if err == nil {
+		err = g.game.Apply(m)
+	}
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "move: %v", err)
+		return
+	}
+	if req.Follow != "" {
+		f, err := session.ParseMove(req.Player, req.Follow, false)
+		if err == nil {
+			err = g.game.ApplyFollow(f)
+		}
+		if err != nil {
+			// De hoofdzet is al toegepast; de client ziet dat in de toestand.
+			writeJSON(w, http.StatusUnprocessableEntity, struct {
+				protocol.Response
+				State State `json:"state"`
+			}{protocol.Response{Type: "error", Error: "follow: " + err.Error()}, g.state()})
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, g.state())
+}
+
+func (s *Server) handleUndo(w http.ResponseWriter, r *http.Request, g *liveGame) {
+	g.takeBack(w, "undo", g.game.Undo)
+}
+
+func (s *Server) handleRedo(w http.ResponseWriter, r *http.Request, g *liveGame) {
+	g.takeBack(w, "redo", g.game.Redo)
+}
+
+// takeBack voert Undo of Redo uit en antwoordt met de nieuwe toestand.
+func (g *liveGame) takeBack(w http.ResponseWriter, name string, f func() ([]game.Move, error)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.cancel != nil {
+		writeError(w, http.StatusConflict, "%v", errBusy)
+		return
+	}
+	if _, err := f(); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "%s: %v", name, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, g.state())
+}
+
+func (s *Server) handleGuess(w http.ResponseWriter, r *http.Request, g *liveGame) {
+	req, ok := readRequest(w, r)
+	if !ok {
+		return
+	}
+	if req.Cmd != "setsuspicion" && req.Cmd != "setexclusion" {
+		writeError(w, http.StatusBadRequest, "cmd moet setsuspicion of setexclusion zijn")
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.cancel != nil {
+		writeError(w, http.StatusConflict, "%v", errBusy)
+		return
+	}
+	err := g.game.CheckOpponent(req.Player)
+	if err == nil {
+		suspicion := req.Cmd == "setsuspicion"
+		switch {
+		case req.Clear && suspicion:
+			err = g.game.ClearSuspicions(req.Player)
+		case req.Clear:
+			err = g.game.ClearExclusions(req.Player)
+		default:
+			var cc []cards.Card
+			if cc, err = cards.ParseCards(req.Cards); err == nil {
+				if suspicion {
+					err = g.game.Suspect(req.Player, cc)
+				} else {
+					err = g.game.Exclude(req.Player, cc)
+				}
+			}
+		}
+	}
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "%s: %v", req.Cmd, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, g.knowledge())
+}
+
+func (s *Server) handleKnowledge(w http.ResponseWriter, r *http.Request, g *liveGame) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	writeJSON(w, http.StatusOK, g.knowledge())
+}
+
+// knowledge vat de tracker samen; g.mu moet vastgehouden worden.
+func (g *liveGame) knowledge() Knowledge {
+	kt := g.game.Tracker
+	gs := g.game.GS
+	k := Knowledge{
+		HandCounts:    make([]int, gs.NumPlayers),
+		Played:        make([]string, gs.NumPlayers),
+		Dead:          cards.CardsToString(kt.DeadCards),
+		PassRecords:   make([][]PassInfo, gs.NumPlayers),
+		Suspicions:    map[int]string{},
+		Exclusions:    map[int]string{},
+		ExcludedRanks: map[int][]string{},
+		Possible:      cards.CardsToString(kt.PossibleOpponentCards()),
+	}
+	for p := 0; p < gs.NumPlayers; p++ {
+		k.HandCounts[p] = gs.Hands[p].Count()
+		k.Played[p] = cards.CardsToString(kt.PlayedByPlayer[p])
+		k.PassRecords[p] = []PassInfo{}
+		for _, pr := range kt.PassRecords[p] {
+			k.PassRecords[p] = append(k.PassRecords[p], PassInfo{
+				Count:     pr.Count,
+				TableRank: cards.Card{Rank: pr.TableRank}.RankStr(),
+			})
+		}
+		if p == g.game.MyPlayer {
+			continue
+		}
+		k.Suspicions[p] = cards.CardsToString(kt.Suspicions[p])
+		var excl []cards.Card
+		for r, n := range kt.Exclusions[p] {
+			for i := 0; i < n; i++ {
+				excl = append(excl, cards.Card{Rank: r})
+			}
+		}
+		sort.Slice(excl, func(i, j int) bool { return excl[i].Rank < excl[j].Rank })
+		k.Exclusions[p] = cards.CardsToString(excl)
+		var ranks []cards.Rank
+		for r, ok := range kt.ExcludedRanks(p) {
+			if ok {
+				ranks = append(ranks, r)
+			}
+		}
+		sort.Slice(ranks, func(i, j int) bool { return ranks[i] < ranks[j] })
+		k.ExcludedRanks[p] = []string{}
+		for _, r := range ranks {
+			k.ExcludedRanks[p] = append(k.ExcludedRanks[p], cards.Card{Rank: r}.RankStr())
+		}
+	}
+	return k
+}
+
+// ─── Zoeken ──────────────────────────────────────────────────────────────────
+
+// startSearch start een zoektocht op de achtergrond. progress (mag nil zijn)
+// krijgt periodiek een "progress"-antwoord, result het "bestmove"-antwoord.
+func (g *liveGame) startSearch(base engine.Config, req protocol.Request, progress, result func(protocol.Response)) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.cancel != nil {
+		return errBusy
+	}
+	gs, tracker, me := g.game.GS, g.game.Tracker, g.game.MyPlayer
+	if gs.GameOver {
+		return fmt.Errorf("de partij is voorbij")
+	}
+	if gs.CurrentTurn != me {
+		return fmt.Errorf("speler %d is aan de beurt, niet %d", gs.CurrentTurn, me)
+	}
+	eng := engine.NewEngine(protocol.SearchConfig(base, gs.NumPlayers, req))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	g.cancel, g.done = cancel, done
+	go func() {
+		defer close(done)
+		defer cancel()
+		resp := protocol.RunSearch(ctx, eng, gs, tracker, req.ID, progress)
+		g.mu.Lock()
+		g.cancel, g.done = nil, nil
+		g.mu.Unlock()
+		result(resp)
+	}()
+	return nil
+}
+
+func (g *liveGame) stop() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.cancel != nil {
+		g.cancel()
+	}
+}
+
+func (g *liveGame) stopAndWait() {
+	g.mu.Lock()
+	cancel, done := g.cancel, g.done
+	if cancel != nil {
+		cancel()
+	}
+	g.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+func searchStatus(err error) int {
+	if errors.Is(err, errBusy) {
+		return http.StatusConflict
+	}
+	return http.StatusUnprocessableEntity
+}
+
+func (s *Server) handleBestMove(w http.ResponseWriter, r *http.Request, g *liveGame) {
+	req := protocol.Request{}
+	if r.ContentLength != 0 {
+		var ok bool
+		if req, ok = readRequest(w, r); !ok {
+			return
+		}
+	}
+	resCh := make(chan protocol.Response, 1)
+	if err := g.startSearch(s.Config, req, nil, func(resp protocol.Response) { resCh <- resp }); err != nil {
+		writeError(w, searchStatus(err), "%v", err)
+		return
+	}
+	select {
+	case resp := <-resCh:
+		writeJSON(w, http.StatusOK, resp)
+	case <-r.Context().Done():
+		// Client is weg: de zoektocht heeft geen zin meer.
+		g.stop()
+	}
+}
+
+func (s *Server) handleStop(w http.ResponseWriter, r *http.Request, g *liveGame) {
+	g.stop()
+	writeJSON(w, http.StatusOK, protocol.Response{Type: "ok"})
+}
+
+// handleWS aanvaardt protocol.Request-berichten met cmd "go", "stop" of
+// "state". Tijdens "go" stuurt de server "progress"-berichten met de
+// tussenstand per kandidaat-zet en tot slot een "bestmove".
+func (s *Server) handleWS(w http.ResponseWriter, r *http.Request, g *liveGame) {
+	ws, err := upgradeWebSocket(w, r)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+	// Een afgebroken verbinding stopt de zoektocht die ze gestart heeft.
+	defer g.stopAndWait()
+	send := func(resp protocol.Response) { ws.WriteJSON(resp) }
+	for {
+		data, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		var req protocol.Request
+		if err := json.Unmarshal(data, &req); err != nil {
+			send(protocol.Response{Type: "error", Error: fmt.Sprintf("ongeldige JSON: %v", err)})
+			continue
+		}
+		switch req.Cmd {
+		case "go":
+			if err := g.startSearch(s.Config, req, send, send); err != nil {
+				send(protocol.Response{ID: req.ID, Type: "error", Error: err.Error()})
+			}
+		case "stop":
+			g.stop()
+		case "state":
+			g.mu.Lock()
+			st := g.state()
+			g.mu.Unlock()
+			ws.WriteJSON(struct {
+				ID    string `json:"id,omitempty"`
+				Type  string `json:"type"`
+				State State  `json:"state"`
+			}{req.ID, "state", st})
+		default:
+			send(protocol.Response{ID: req.ID, Type: "error", Error: fmt.Sprintf("onbekend commando %q", req.Cmd)})
+		}
+	}
+}
+
+// ─── Analyse ─────────────────────────────────────────────────────────────────
+
+// handleAnalyze leest een tekstlog (zoals gameio.SaveGame die schrijft) uit
+// de body en beoordeelt de zetten zoals analyzeMode. Query-parameters:
+// players=0,2 (leeg = alle spelers) en iterations=N. Verbreekt de client
+// de verbinding, dan stopt de analyse.
+func (s *Server) handleAnalyze(w http.ResponseWriter, r *http.Request) {
+	log, err := gameio.ReadGame(http.MaxBytesReader(w, r.Body, maxBody))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "log: %v", err)
+		return
+	}
+	players := map[int]bool{}
+	for _, part := range strings.Split(r.URL.Query().Get("players"), ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		p, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || p < 0 || p >= log.NumPlayers {
+			writeError(w, http.StatusBadRequest, "ongeldige speler: %q", part)
+			return
+		}
+		players[p] = true
+	}
+	cfg := s.Config
+	if it := r.URL.Query().Get("iterations"); it != "" {
+		n, err := strconv.Atoi(it)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "ongeldig aantal iteraties: %q", it)
+			return
+		}
+		cfg.Iterations = n
+	}
+
+	resp := AnalyzeResponse{Players: log.NumPlayers, Moves: []AnalyzedMove{}}
+	gs, err := analysis.ReplayLogContext(r.Context(), cfg, log, players, func(p analysis.Ply) {
+		resp.Moves = append(resp.Moves, analyzedMove(p))
+	})
+	if gs == nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	resp.GameOver = gs.GameOver
+	if gs.GameOver {
+		resp.Ranking = gs.Ranking
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func analyzedMove(p analysis.Ply) AnalyzedMove {
+	am := AnalyzedMove{Num: p.Num, Player: p.Move.PlayerID, Move: protocol.ToWire(p.Move)}
+	if p.Follow != nil {
+		f := protocol.ToWire(*p.Follow)
+		am.Follow = &f
+	}
+	a := p.Analysis
+	if a == nil {
+		return am
+	}
+	am.Grade = a.Grade().String()
+	am.Score = a.Actual.WinRate
+	am.BestScore = a.Eval.Score
+	am.ForcedWinDepth = a.Eval.ForcedWinDepth
+	if !a.PlayedIsBest() {
+		b := protocol.ToWire(a.Best)
+		am.Best = &b
+		if a.BestFollow != nil {
+			bf := protocol.ToWire(*a.BestFollow)
+			am.BestFollow = &bf
+		}
+	}
+	top := append([]engine.MoveDetail(nil), a.Eval.Details...)
+	sort.SliceStable(top, func(i, j int) bool { return top[i].WinRate > top[j].WinRate })
+	if len(top) > 5 {
+		top = top[:5]
+	}
+	for _, d := range top {
+		am.Top = append(am.Top, protocol.WireDetail{Move: protocol.ToWire(d.Move), WinRate: d.WinRate, Visits: d.Visits})
+	}
+	return am
+}
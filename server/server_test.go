@@ -0,0 +1,286 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azen-engine/engine"
+	"github.com/azen-engine/protocol"
+)
+
+// newTestServer start een server met een kleine, deterministische engine.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	cfg := engine.DefaultConfig(2)
+	cfg.Iterations = 200
+	cfg.NumWorkers = 1
+	cfg.Seed = 1
+	ts := httptest.NewServer(New(cfg))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// call doet een verzoek met een JSON-body (leeg = geen) en decodeert het
+// antwoord in v (nil = negeren). Het geeft de statuscode terug.
+func call(t *testing.T, ts *httptest.Server, method, path, body string, v any) int {
+	t.Helper()
+	req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("%s %s: antwoord: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// newSession maakt een partij voor 2 spelers waarin speler 0 begint.
+func newSession(t *testing.T, ts *httptest.Server) State {
+	t.Helper()
+	var st State
+	if code := call(t, ts, "POST", "/api/sessions", `{"players":2,"seat":0,"hand":"33455789XXJQKK1220"}`, &st); code != http.StatusCreated {
+		t.Fatalf("nieuwe partij: status %d", code)
+	}
+	return st
+}
+
+func TestSessionMovesAndUndo(t *testing.T) {
+	ts := newTestServer(t)
+	st := newSession(t, ts)
+	if st.ID == "" || st.Turn != 0 || len(st.Hand) == 0 {
+		t.Fatalf("nieuwe partij: %+v", st)
+	}
+	base := "/api/sessions/" + st.ID
+	if code := call(t, ts, "POST", base+"/moves", `{"player":0,"cards":"3 3"}`, &st); code != http.StatusOK || st.Turn != 1 {
+		t.Fatalf("3 3: status %d, %+v", code, st)
+	}
+	if code := call(t, ts, "POST", base+"/moves", `{"player":0,"cards":"4"}`, nil); code != http.StatusUnprocessableEntity {
+		t.Errorf("speler 0 niet aan de beurt: status %d, verwacht 422", code)
+	}
+	if code := call(t, ts, "POST", base+"/moves", `{"player":1,"cards":"3"}`, nil); code != http.StatusUnprocessableEntity {
+		t.Errorf("enkele 3 op 3 3: status %d, verwacht 422", code)
+	}
+	if code := call(t, ts, "POST", base+"/undo", "", &st); code != http.StatusOK || st.Turn != 0 || len(st.History) != 0 {
+		t.Fatalf("undo: status %d, %+v", code, st)
+	}
+	if code := call(t, ts, "POST", base+"/redo", "", &st); code != http.StatusOK || len(st.History) != 1 {
+		t.Fatalf("redo: status %d, %+v", code, st)
+	}
+	if code := call(t, ts, "GET", "/api/sessions/onbekend", "", nil); code != http.StatusNotFound {
+		t.Errorf("onbekende partij: status %d", code)
+	}
+	if code := call(t, ts, "DELETE", base, "", nil); code != http.StatusNoContent {
+		t.Errorf("verwijderen: status %d", code)
+	}
+}
+
+func TestBestMove(t *testing.T) {
+	ts := newTestServer(t)
+	st := newSession(t, ts)
+	var resp protocol.Response
+	if code := call(t, ts, "POST", "/api/sessions/"+st.ID+"/bestmove", "", &resp); code != http.StatusOK {
+		t.Fatalf("bestmove: status %d, %+v", code, resp)
+	}
+	if resp.Type != "bestmove" || resp.Move == nil || resp.Move.Pass || resp.Score == nil || len(resp.Details) == 0 {
+		t.Errorf("bestmove: %+v", resp)
+	}
+}
+
+func TestBestMoveStopsWhenClientLeaves(t *testing.T) {
+	ts := newTestServer(t)
+	st := newSession(t, ts)
+	base := ts.URL + "/api/sessions/" + st.ID
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "POST", base+"/bestmove", strings.NewReader(`{"iterations":1000000000}`))
+	if resp, err := ts.Client().Do(req); err == nil {
+		resp.Body.Close()
+		t.Fatal("zoektocht van een miljard iteraties was meteen klaar")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if call(t, ts, "GET", "/api/sessions/"+st.ID, "", &st); !st.Searching {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("zoektocht loopt nog nadat de client weg is")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// testLog is een korte partij als tekstlog: speler 0 wint met 5 en K.
+const testLog = `AZEN GAME LOG
+players:2
+winner:0
+hand:0:K,5
+hand:1:9,4
+---
+P0:5
+P1:9
+P0:K
+`
+
+func TestAnalyze(t *testing.T) {
+	ts := newTestServer(t)
+	var resp AnalyzeResponse
+	if code := call(t, ts, "POST", "/api/analyze?players=0&iterations=50", testLog, &resp); code != http.StatusOK {
+		t.Fatalf("analyze: status %d, %+v", code, resp)
+	}
+	if len(resp.Moves) != 3 || !resp.GameOver || resp.Error != "" {
+		t.Fatalf("analyze: %+v", resp)
+	}
+	for _, m := range resp.Moves {
+		if analyzed := m.Grade != ""; analyzed != (m.Player == 0) {
+			t.Errorf("beurt %d van speler %d: oordeel %q", m.Num, m.Player, m.Grade)
+		}
+	}
+	if code := call(t, ts, "POST", "/api/analyze?players=5", testLog, nil); code != http.StatusBadRequest {
+		t.Errorf("ongeldige speler: status %d", code)
+	}
+}
+
+func TestOrigin(t *testing.T) {
+	ts := newTestServer(t)
+	st := newSession(t, ts)
+	own := ts.URL
+	tests := []struct {
+		name, method, path, origin string
+		want                       int
+	}{
+		{"partij eigen origin", "POST", "/api/sessions", own, http.StatusCreated},
+		{"partij andere origin", "POST", "/api/sessions", "http://evil.example", http.StatusForbidden},
+		{"analyse andere origin", "POST", "/api/analyze", "http://evil.example", http.StatusForbidden},
+		{"verwijderen andere origin", "DELETE", "/api/sessions/" + st.ID, "http://evil.example", http.StatusForbidden},
+		{"toestand andere origin", "GET", "/api/sessions/" + st.ID, "http://evil.example", http.StatusOK},
+		{"websocket zonder origin", "GET", "/api/sessions/" + st.ID + "/ws", "", http.StatusSwitchingProtocols},
+		{"websocket eigen origin", "GET", "/api/sessions/" + st.ID + "/ws", own, http.StatusSwitchingProtocols},
+		{"websocket andere origin", "GET", "/api/sessions/" + st.ID + "/ws", "http://evil.example", http.StatusForbidden},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var body io.Reader
+			if tc.method == "POST" {
+				body = strings.NewReader(`{"players":2,"seat":0,"hand":"33455789XXJQKK1220"}`)
+			}
+			req, _ := http.NewRequest(tc.method, ts.URL+tc.path, body)
+			// Een eenvoudige cross-site POST vanuit een formulier of fetch.
+			req.Header.Set("Content-Type", "text/plain")
+			if strings.HasSuffix(tc.path, "/ws") {
+				req.Header.Set("Connection", "Upgrade")
+				req.Header.Set("Upgrade", "websocket")
+				req.Header.Set("Sec-WebSocket-Version", "13")
+				req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+			}
+			if tc.origin != "" {
+				req.Header.Set("Origin", tc.origin)
+			}
+			resp, err := ts.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tc.want {
+				t.Errorf("status %d, verwacht %d", resp.StatusCode, tc.want)
+			}
+		})
+	}
+}
+
+func TestWebSocketGo(t *testing.T) {
+	ts := newTestServer(t)
+	st := newSession(t, ts)
+	req, _ := http.NewRequest("GET", ts.URL+"/api/sessions/"+st.ID+"/ws", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake: status %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("Sec-WebSocket-Accept %q", got)
+	}
+	conn, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		t.Fatalf("body %T is geen verbinding", resp.Body)
+	}
+	defer conn.Close()
+	writeClientText(t, conn, `{"id":"w","cmd":"go"}`)
+	for {
+		var r protocol.Response
+		if err := json.Unmarshal(readServerText(t, conn), &r); err != nil {
+			t.Fatal(err)
+		}
+		if r.Type == "progress" {
+			continue
+		}
+		if r.Type != "bestmove" || r.ID != "w" || r.Move == nil {
+			t.Fatalf("antwoord %+v, verwacht bestmove", r)
+		}
+		return
+	}
+}
+
+// writeClientText stuurt een gemaskeerd tekstbericht, zoals een browser.
+func writeClientText(t *testing.T, w io.Writer, msg string) {
+	t.Helper()
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{0x80 | opText, 0x80 | byte(len(msg))}
+	frame = append(frame, mask[:]...)
+	for i := 0; i < len(msg); i++ {
+		frame = append(frame, msg[i]^mask[i%4])
+	}
+	if _, err := w.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readServerText leest één ongemaskeerd tekstbericht van de server.
+func readServerText(t *testing.T, r io.Reader) []byte {
+	t.Helper()
+	var hdr [2]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		t.Fatal(err)
+	}
+	n := int(hdr[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		io.ReadFull(r, ext[:])
+		n = int(ext[0])<<8 | int(ext[1])
+	case 127:
+		var ext [8]byte
+		io.ReadFull(r, ext[:])
+		n = 0
+		for _, b := range ext {
+			n = n<<8 | int(b)
+		}
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(r, data); err != nil {
+		t.Fatal(err)
+	}
+	if hdr[0]&0x0f != opText {
+		t.Fatalf("opcode %#x, verwacht tekst", hdr[0]&0x0f)
+	}
+	return data
+}
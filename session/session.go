@@ -0,0 +1,328 @@
+// Package session houdt één partij bij vanuit het standpunt van één speler:
+// de spelstaat (met onbekende tegenstandershanden) en de KnowledgeTracker.
+// Het JSON-protocol en de HTTP-server delen deze logica.
+package session
+
+import (
+	"fmt"
+
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/game"
+	"github.com/azen-engine/knowledge"
+)
+
+// Setup beschrijft de beginsituatie van een partij. Spelers zijn 0-based.
+type Setup struct {
+	Players int
+	Seat    int          // eigen spelernummer
+	Start   int          // spelernummer dat begint
+	Hand    []cards.Card // eigen hand
+	Counts  []int        // startkaarten per speler (nil = 18 elk)
+	Dead    []cards.Card
+}
+
+// Game is de toestand van één partij zoals de engine die ziet.
+//
+// Elke invoer (zet of vermoeden) wordt gelogd; Undo speelt de log opnieuw
+// af vanaf de beginstand. GS en Tracker kunnen daardoor na Undo naar nieuwe
+// objecten wijzen: lees ze telkens via Game. Redo zet teruggenomen zetten
+// terug zolang er intussen geen nieuwe zet werd ingevoerd.
+type Game struct {
+	NumPlayers int
+	MyPlayer   int
+	GS         *game.GameState
+	Tracker    *knowledge.KnowledgeTracker
+
+	setup        Setup
+	startGS      *game.GameState
+	startTracker *knowledge.KnowledgeTracker
+	log          []entry
+	redo         [][]game.Move // teruggenomen zetten, laatste bovenaan
+}
+
+// entry is één invoer: een zet (met eventuele vervolg-zet na een joker) of
+// een wijziging van vermoedens.
+type entry struct {
+	moves []game.Move
+	guess *guess
+}
+
+// guess is een wijziging van vermoedens over één tegenstander.
+type guess struct {
+	kind   string // "suspect", "exclude", "clear-suspicions", "clear-exclusions"
+	player int
+	cards  []cards.Card
+}
+
+// apply voert de wijziging uit op kt en geeft het aantal toegevoegde
+// kaarten terug.
+func (gu *guess) apply(kt *knowledge.KnowledgeTracker) int {
+	switch gu.kind {
+	case "suspect":
+		return kt.AddSuspicion(gu.player, gu.cards)
+	case "exclude":
+		return kt.AddExclusion(gu.player, gu.cards)
+	case "clear-suspicions":
+		kt.ClearSuspicions(gu.player)
+	case "clear-exclusions":
+		kt.ClearExclusions(gu.player)
+	}
+	return 0
+}
+
+// Open controleert setup en maakt een partij. De handen van tegenstanders
+// bestaan uit placeholders; enkel hun aantal is gekend.
+func Open(setup Setup) (*Game, error) {
+	n := setup.Players
+	if n < 2 || n > 4 {
+		return nil, fmt.Errorf("ongeldig aantal spelers: %d (2-4)", n)
+	}
+	if setup.Seat < 0 || setup.Seat >= n || setup.Start < 0 || setup.Start >= n {
+		return nil, fmt.Errorf("seat en start moeten tussen 0 en %d liggen", n-1)
+	}
+	counts := setup.Counts
+	if len(counts) == 0 {
+		counts = make([]int, n)
+		for i := range counts {
+			counts[i] = 18
+		}
+	}
+	if len(counts) != n {
+		return nil, fmt.Errorf("verwacht %d kaartaantallen, kreeg %d", n, len(counts))
+	}
+	for _, c := range counts {
+		if c <= 0 {
+			return nil, fmt.Errorf("ongeldig kaartaantal: %d", c)
+		}
+	}
+	if len(setup.Hand) != counts[setup.Seat] {
+		return nil, fmt.Errorf("verwacht %d kaarten in de hand, kreeg %d", counts[setup.Seat], len(setup.Hand))
+	}
+	hands := make([]*cards.Hand, n)
+	for i := range hands {
+		if i == setup.Seat {
+			hands[i] = cards.NewHand(setup.Hand)
+		} else {
+			hands[i] = cards.NewHand(make([]cards.Card, counts[i]))
+		}
+	}
+	tracker := knowledge.NewKnowledgeTracker(n, setup.Seat, hands[setup.Seat], setup.Dead)
+	copy(tracker.HandCounts, counts)
+	gs := game.NewGameWithHands(hands, setup.Dead, setup.Start)
+	setup.Counts = counts
+	return &Game{
+		NumPlayers:   n,
+		MyPlayer:     setup.Seat,
+		GS:           gs,
+		Tracker:      tracker,
+		setup:        setup,
+		startGS:      gs.Clone(),
+		startTracker: tracker.Clone(),
+	}, nil
+}
+
+// ParseMove leest een zet van speler pid: pass, of kaarten in de gewone
+// notatie ("K K", "0", "2 2").
+func ParseMove(pid int, cardStr string, pass bool) (game.Move, error) {
+	if pass {
+		return game.PassMove(pid), nil
+	}
+	cc, err := cards.ParseCards(cardStr)
+	if err != nil {
+		return game.Move{}, err
+	}
+	if len(cc) == 0 {
+		return game.Move{}, fmt.Errorf("geen kaarten (gebruik pass)")
+	}
+	return game.Move{PlayerID: pid, Cards: cc}, nil
+}
+
+// Validate controleert een waargenomen zet tegen wat publiek bekend is.
+// Eigen zetten moeten uit de eigen hand komen. Van een tegenstander kennen
+// we de hand niet, maar wel het aantal kaarten (placeholders in gs), de
+// tafel en welke kaarten nog onbekend zijn: een lagere rank, een verkeerd
+// aantal, een joker met normale kaarten of een kaart die al helemaal
+// gezien is, wordt geweigerd.
+func Validate(gs *game.GameState, kt *knowledge.KnowledgeTracker, myPlayer int, m game.Move) error {
+	if err := gs.ValidateMove(m); err != nil {
+		return err
+	}
+	if m.PlayerID != myPlayer && !m.IsPass {
+		return kt.CheckAvailable(m.Cards)
+	}
+	return nil
+}
+
+// Apply valideert een waargenomen zet en past hem toe op spelstaat en
+// tracker.
+func (g *Game) Apply(m game.Move) error {
+	if err := g.validate(m); err != nil {
+		return err
+	}
+	record(g.GS, g.Tracker, m)
+	g.log = append(g.log, entry{moves: []game.Move{m}})
+	g.redo = nil
+	return nil
+}
+
+// ApplyFollow past de vervolg-zet toe die een speler na een joker-reset
+// meteen in dezelfde beurt speelt. Undo neemt zet en vervolg-zet samen
+// terug. Vermoedens die tussen reset en vervolg-zet ingevoerd zijn, tellen
+// niet als zet.
+func (g *Game) ApplyFollow(f game.Move) error {
+	last := len(g.log) - 1
+	for last >= 0 && g.log[last].moves == nil {
+		last--
+	}
+	if g.GS.GameOver || g.GS.CurrentTurn != f.PlayerID || f.IsPass || last < 0 {
+		return fmt.Errorf("vervolg-zet kan enkel na een joker-reset")
+	}
+	moves := g.log[last].moves
+	if prev := moves[len(moves)-1]; !prev.ContainsReset() || prev.PlayerID != f.PlayerID {
+		return fmt.Errorf("vervolg-zet kan enkel na een joker-reset")
+	}
+	if err := g.validate(f); err != nil {
+		return err
+	}
+	record(g.GS, g.Tracker, f)
+	g.log[last].moves = append(moves, f)
+	return nil
+}
+
+func (g *Game) validate(m game.Move) error {
+	if g.GS.GameOver {
+		return fmt.Errorf("de partij is voorbij")
+	}
+	if m.PlayerID != g.GS.CurrentTurn {
+		return fmt.Errorf("speler %d is niet aan de beurt (beurt: %d)", m.PlayerID, g.GS.CurrentTurn)
+	}
+	return Validate(g.GS, g.Tracker, g.MyPlayer, m)
+}
+
+// record past een (gevalideerde) zet toe op gs en kt.
+func record(gs *game.GameState, kt *knowledge.KnowledgeTracker, m game.Move) {
+	if m.IsPass {
+		kt.RecordPass(m.PlayerID, gs.Round)
+	}
+	gs.ApplyMove(m)
+	kt.RecordMove(m)
+}
+
+// Undo neemt de laatst ingevoerde zet terug (met zijn vervolg-zet) en geeft
+// die terug. Vermoedens die daarna zijn ingevoerd blijven behouden.
+func (g *Game) Undo() ([]game.Move, error) {
+	for i := len(g.log) - 1; i >= 0; i-- {
+		if g.log[i].moves == nil {
+			continue
+		}
+		undone := g.log[i].moves
+		g.log = append(g.log[:i:i], g.log[i+1:]...)
+		g.redo = append(g.redo, undone)
+		g.replay()
+		return undone, nil
+	}
+	return nil, fmt.Errorf("geen zet om terug te nemen")
+}
+
+// Redo voert de laatst teruggenomen zet opnieuw in en geeft hem terug. Een
+// nieuwe zet via Apply wist de teruggenomen zetten.
+func (g *Game) Redo() ([]game.Move, error) {
+	n := len(g.redo)
+	if n == 0 {
+		return nil, fmt.Errorf("geen zet om opnieuw te doen")
+	}
+	moves := g.redo[n-1]
+	for _, m := range moves {
+		if err := g.validate(m); err != nil {
+			g.replay() // draait een al toegepaste hoofdzet terug
+			return nil, err
+		}
+		record(g.GS, g.Tracker, m)
+	}
+	g.log = append(g.log, entry{moves: moves})
+	g.redo = g.redo[:n-1]
+	return moves, nil
+}
+
+// CanUndo en CanRedo melden of Undo of Redo iets te doen heeft.
+func (g *Game) CanUndo() bool {
+	for _, e := range g.log {
+		if e.moves != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Game) CanRedo() bool { return len(g.redo) > 0 }
+
+// replay bouwt GS en Tracker opnieuw op vanaf de beginstand en de log.
+func (g *Game) replay() {
+	gs, kt := g.startGS.Clone(), g.startTracker.Clone()
+	for _, e := range g.log {
+		for _, m := range e.moves {
+			record(gs, kt, m)
+		}
+		if e.guess != nil {
+			e.guess.apply(kt)
+		}
+	}
+	g.GS, g.Tracker = gs, kt
+}
+
+// guess voert een wijziging van vermoedens uit en logt ze voor replay.
+// Geeft het aantal toegevoegde kaarten terug.
+func (g *Game) guess(kind string, p int, cc []cards.Card) int {
+	gu := &guess{kind: kind, player: p, cards: cc}
+	g.log = append(g.log, entry{guess: gu})
+	return gu.apply(g.Tracker)
+}
+
+// CheckOpponent faalt als p geen tegenstander is.
+func (g *Game) CheckOpponent(p int) error {
+	if p < 0 || p >= g.NumPlayers || p == g.MyPlayer {
+		return fmt.Errorf("ongeldige tegenstander: %d", p)
+	}
+	return nil
+}
+
+// Suspect voegt kaarten toe waarvan we vermoeden dat tegenstander p ze heeft.
+func (g *Game) Suspect(p int, cc []cards.Card) error {
+	if err := g.CheckOpponent(p); err != nil {
+		return err
+	}
+	return partial(g.guess("suspect", p, cc), len(cc))
+}
+
+// Exclude noteert kaarten die tegenstander p zeker niet heeft.
+func (g *Game) Exclude(p int, cc []cards.Card) error {
+	if err := g.CheckOpponent(p); err != nil {
+		return err
+	}
+	return partial(g.guess("exclude", p, cc), len(cc))
+}
+
+// ClearSuspicions wist de vermoedens over tegenstander p.
+func (g *Game) ClearSuspicions(p int) error {
+	if err := g.CheckOpponent(p); err != nil {
+		return err
+	}
+	g.guess("clear-suspicions", p, nil)
+	return nil
+}
+
+// ClearExclusions wist de uitsluitingen voor tegenstander p.
+func (g *Game) ClearExclusions(p int) error {
+	if err := g.CheckOpponent(p); err != nil {
+		return err
+	}
+	g.guess("clear-exclusions", p, nil)
+	return nil
+}
+
+func partial(added, total int) error {
+	if added < total {
+		return fmt.Errorf("%d van %d kaart(en) toegevoegd: al gespeeld of niet meer in pool", added, total)
+	}
+	return nil
+}
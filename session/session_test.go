@@ -0,0 +1,250 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/game"
+)
+
+func open(t *testing.T) *Game {
+	t.Helper()
+	hand, _ := cards.ParseCards("3 3 4 5 5 7 8 9 X X J Q K K 1 2 2 0")
+	g, err := Open(Setup{Players: 2, Seat: 0, Start: 1, Hand: hand})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func move(t *testing.T, pid int, s string) game.Move {
+	t.Helper()
+	m, err := ParseMove(pid, s, s == "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestApplyRejectsImpossibleOpponentMoves(t *testing.T) {
+	tests := []struct {
+		setup   []string // zetten om en om vanaf speler 1; "" = pass
+		move    string
+		wantErr string
+	}{
+		{nil, "1 1 1 1", "onbekend"},  // één aas zit in onze hand
+		{nil, "0 0", "onbekend"},      // één joker zit in onze hand
+		{nil, "0 6", "joker"},         // joker met normale kaart
+		{nil, "6 7", "dezelfde rank"}, // gemengde ranks
+		{[]string{"8 8", "X X"}, "9 9", "verslaat"},
+		{[]string{"8 8", "X X"}, "K", "exact 2"},
+		{nil, "6 6 6 6 6 6 6", "onbekend"}, // er zijn maar vier zessen
+	}
+	for _, tt := range tests {
+		g := open(t)
+		for i, s := range tt.setup {
+			if err := g.Apply(move(t, 1-i%2, s)); err != nil {
+				t.Fatalf("setup %q: %v", s, err)
+			}
+		}
+		before := len(g.GS.History)
+		err := g.Apply(move(t, 1, tt.move))
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%v + %q: fout %v, want %q", tt.setup, tt.move, err, tt.wantErr)
+		}
+		if len(g.GS.History) != before {
+			t.Errorf("%q: geweigerde zet toch toegepast", tt.move)
+		}
+	}
+}
+
+func TestApplyAcceptsPossibleOpponentMove(t *testing.T) {
+	g := open(t)
+	for _, s := range []string{"1 1 1", "6 6 2", "0"} {
+		if err := Validate(g.GS, g.Tracker, g.MyPlayer, move(t, 1, s)); err != nil {
+			t.Errorf("%q: %v", s, err)
+		}
+	}
+}
+
+func TestUndo(t *testing.T) {
+	g := open(t)
+	if _, err := g.Undo(); err == nil {
+		t.Error("Undo zonder zetten gaf geen fout")
+	}
+	if err := g.Apply(move(t, 1, "0")); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.ApplyFollow(move(t, 1, "6 6")); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Suspect(1, []cards.Card{{Rank: cards.RankQueen}}); err != nil {
+		t.Fatal(err)
+	}
+	undone, err := g.Undo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(undone) != 2 || !undone[0].ContainsReset() {
+		t.Errorf("teruggenomen: %v, want joker en vervolg-zet", undone)
+	}
+	if len(g.GS.History) != 0 || g.GS.CurrentTurn != 1 || !g.GS.Round.IsOpen {
+		t.Errorf("stand na Undo: %+v", g.GS)
+	}
+	if g.Tracker.HandCounts[1] != 18 || len(g.Tracker.CardsPlayed) != 0 {
+		t.Errorf("tracker na Undo: hand %d, gespeeld %v", g.Tracker.HandCounts[1], g.Tracker.CardsPlayed)
+	}
+	if got := cards.CardsToString(g.Tracker.Suspicions[1]); !strings.Contains(got, "Q") {
+		t.Errorf("vermoeden na Undo verloren: %q", got)
+	}
+}
+
+func TestApplyFollow(t *testing.T) {
+	// Na een pass is speler 1 weer aan de beurt, maar dat is geen reset.
+	g := open(t)
+	for _, s := range []string{"8 8", ""} {
+		if err := g.Apply(move(t, g.GS.CurrentTurn, s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if g.GS.CurrentTurn != 1 {
+		t.Fatalf("beurt %d na pass, want 1", g.GS.CurrentTurn)
+	}
+	if err := g.ApplyFollow(move(t, 1, "6 6")); err == nil {
+		t.Error("vervolg-zet na een pass gaf geen fout")
+	}
+
+	// Een vermoeden tussen joker en vervolg-zet hoort er niet tussen te komen.
+	g = open(t)
+	if err := g.Apply(move(t, 1, "0")); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Suspect(1, []cards.Card{{Rank: cards.RankQueen}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.ApplyFollow(move(t, 1, "6 6")); err != nil {
+		t.Fatalf("vervolg-zet na vermoeden: %v", err)
+	}
+	undone, err := g.Undo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(undone) != 2 || !undone[0].ContainsReset() {
+		t.Errorf("teruggenomen: %v, want joker en vervolg-zet", undone)
+	}
+}
+
+func TestRedo(t *testing.T) {
+	hand, _ := cards.ParseCards("3 3 4 5 5 7 8 9 X X J Q K K 1 2 2 0")
+	// Met ≤12 kaarten logt de tracker passes.
+	g, err := Open(Setup{Players: 2, Seat: 0, Start: 1, Hand: hand, Counts: []int{18, 10}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"8 8", "X X", ""} {
+		if err := g.Apply(move(t, g.GS.CurrentTurn, s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := g.GS.StatusString()
+	for i := 0; i < 3; i++ {
+		if _, err := g.Undo(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if g.CanUndo() || !g.CanRedo() {
+		t.Fatalf("CanUndo=%v CanRedo=%v na drie keer Undo", g.CanUndo(), g.CanRedo())
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := g.Redo(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := g.GS.StatusString(); got != want {
+		t.Errorf("na Undo/Redo:\n%s\nwant\n%s", got, want)
+	}
+	if len(g.Tracker.PassRecords[1]) != 1 {
+		t.Errorf("pass van speler 1 niet opnieuw gelogd: %v", g.Tracker.PassRecords[1])
+	}
+	if _, err := g.Redo(); err == nil {
+		t.Error("Redo zonder teruggenomen zet gaf geen fout")
+	}
+
+	// Een nieuwe zet na Undo wist de teruggenomen zetten.
+	g.Undo()
+	if err := g.Apply(move(t, 1, "J J")); err != nil {
+		t.Fatal(err)
+	}
+	if g.CanRedo() {
+		t.Error("Redo nog mogelijk na een nieuwe zet")
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	hand, _ := cards.ParseCards("3 3 4 5 5 7 8 9 X X J Q K K 1 2 2 0")
+	g, err := Open(Setup{Players: 3, Seat: 0, Start: 1, Hand: hand, Counts: []int{18, 12, 12}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"8 8", ""} {
+		if err := g.Apply(move(t, g.GS.CurrentTurn, s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := g.Exclude(1, []cards.Card{{Rank: cards.RankAce}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Apply(move(t, 0, "2 0")); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.ApplyFollow(move(t, 0, "9")); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Suspect(2, []cards.Card{{Rank: cards.RankQueen}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Apply(move(t, 1, "")); err != nil {
+		t.Fatal(err)
+	}
+	g.Undo()
+
+	data, err := json.Marshal(g.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		t.Fatal(err)
+	}
+	r, err := Restore(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := r.GS.StatusString(), g.GS.StatusString(); got != want {
+		t.Errorf("spelstaat:\n%s\nwant\n%s", got, want)
+	}
+	if got, want := fmt.Sprintf("%+v", r.Tracker), fmt.Sprintf("%+v", g.Tracker); got != want {
+		t.Errorf("tracker:\n%s\nwant\n%s", got, want)
+	}
+	if !r.CanRedo() {
+		t.Fatal("teruggenomen zet niet bewaard")
+	}
+	if _, err := r.Redo(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRestoreRejectsInvalidMoves(t *testing.T) {
+	g := open(t)
+	if err := g.Apply(move(t, 1, "8 8")); err != nil {
+		t.Fatal(err)
+	}
+	snap := g.Snapshot()
+	snap.Log[0].Moves[0].Cards = "8 8 8 8 8"
+	if _, err := Restore(snap); err == nil {
+		t.Error("Restore aanvaardde een onmogelijke zet")
+	}
+}
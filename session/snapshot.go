@@ -0,0 +1,172 @@
+package session
+
+import (
+	"fmt"
+
+	"github.com/azen-engine/cards"
+	"github.com/azen-engine/game"
+	"github.com/azen-engine/gameio"
+)
+
+// Snapshot is een partij in JSON-vorm: de beginsituatie en alle invoer
+// (zetten en vermoedens) in volgorde, plus de teruggenomen zetten voor
+// Redo. Restore speelt de invoer opnieuw af, zodat spelstaat en tracker
+// (passes, vermoedens, uitsluitingen) exact terugkomen.
+type Snapshot struct {
+	Players int           `json:"players"`
+	Seat    int           `json:"seat"`
+	Start   int           `json:"start"`
+	Hand    string        `json:"hand"`
+	Counts  []int         `json:"counts"`
+	Dead    string        `json:"dead,omitempty"`
+	Log     []SavedEntry  `json:"log"`
+	Redo    [][]SavedMove `json:"redo,omitempty"`
+}
+
+// SavedEntry is één invoer: Moves (zet met eventuele vervolg-zet) of een
+// vermoeden (Guess = "suspect", "exclude", "clear-suspicions" of
+// "clear-exclusions" over Player).
+type SavedEntry struct {
+	Moves  []SavedMove `json:"moves,omitempty"`
+	Guess  string      `json:"guess,omitempty"`
+	Player int         `json:"player,omitempty"`
+	Cards  string      `json:"cards,omitempty"`
+}
+
+// SavedMove is een zet in de gewone kaartnotatie.
+type SavedMove struct {
+	Player int    `json:"player"`
+	Cards  string `json:"cards,omitempty"`
+	Pass   bool   `json:"pass,omitempty"`
+}
+
+func saveMoves(moves []game.Move) []SavedMove {
+	out := make([]SavedMove, len(moves))
+	for i, m := range moves {
+		out[i] = SavedMove{Player: m.PlayerID, Pass: m.IsPass}
+		if !m.IsPass {
+			out[i].Cards = cards.CardsToString(m.Cards)
+		}
+	}
+	return out
+}
+
+func loadMoves(saved []SavedMove) ([]game.Move, error) {
+	out := make([]game.Move, len(saved))
+	for i, sm := range saved {
+		m, err := ParseMove(sm.Player, sm.Cards, sm.Pass)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = m
+	}
+	return out, nil
+}
+
+// Snapshot legt de partij vast.
+func (g *Game) Snapshot() Snapshot {
+	s := Snapshot{
+		Players: g.setup.Players,
+		Seat:    g.setup.Seat,
+		Start:   g.setup.Start,
+		Hand:    cards.CardsToString(g.setup.Hand),
+		Counts:  g.setup.Counts,
+		Dead:    cards.CardsToString(g.setup.Dead),
+		Log:     make([]SavedEntry, len(g.log)),
+	}
+	for i, e := range g.log {
+		if e.guess != nil {
+			s.Log[i] = SavedEntry{Guess: e.guess.kind, Player: e.guess.player, Cards: cards.CardsToString(e.guess.cards)}
+		} else {
+			s.Log[i] = SavedEntry{Moves: saveMoves(e.moves)}
+		}
+	}
+	for _, moves := range g.redo {
+		s.Redo = append(s.Redo, saveMoves(moves))
+	}
+	return s
+}
+
+// Restore bouwt een partij op uit een Snapshot. Elke zet wordt opnieuw
+// gevalideerd; een beschadigde of aangepaste snapshot geeft een fout.
+func Restore(s Snapshot) (*Game, error) {
+	hand, err := cards.ParseCards(s.Hand)
+	if err != nil {
+		return nil, fmt.Errorf("hand: %v", err)
+	}
+	dead, err := cards.ParseCards(s.Dead)
+	if err != nil {
+		return nil, fmt.Errorf("dead: %v", err)
+	}
+	g, err := Open(Setup{Players: s.Players, Seat: s.Seat, Start: s.Start, Hand: hand, Counts: s.Counts, Dead: dead})
+	if err != nil {
+		return nil, err
+	}
+	for i, e := range s.Log {
+		if err := g.restoreEntry(e); err != nil {
+			return nil, fmt.Errorf("invoer %d: %v", i+1, err)
+		}
+	}
+	for _, saved := range s.Redo {
+		moves, err := loadMoves(saved)
+		if err != nil || len(moves) == 0 {
+			return nil, fmt.Errorf("redo: ongeldige zet")
+		}
+		g.redo = append(g.redo, moves)
+	}
+	return g, nil
+}
+
+func (g *Game) restoreEntry(e SavedEntry) error {
+	if e.Guess != "" {
+		if err := g.CheckOpponent(e.Player); err != nil {
+			return err
+		}
+		cc, err := cards.ParseCards(e.Cards)
+		if err != nil {
+			return err
+		}
+		switch e.Guess {
+		case "suspect", "exclude", "clear-suspicions", "clear-exclusions":
+			g.guess(e.Guess, e.Player, cc)
+			return nil
+		}
+		return fmt.Errorf("onbekend vermoeden %q", e.Guess)
+	}
+	moves, err := loadMoves(e.Moves)
+	if err != nil {
+		return err
+	}
+	if len(moves) == 0 {
+		return fmt.Errorf("lege invoer")
+	}
+	if err := g.Apply(moves[0]); err != nil {
+		return err
+	}
+	for _, f := range moves[1:] {
+		if err := g.ApplyFollow(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Record zet de partij om naar een gameio-record. Enkel de eigen hand is
+// gekend; de handen van tegenstanders blijven leeg, hun aantal staat in
+// Counts.
+func (g *Game) Record() *gameio.Record {
+	rec := gameio.NewRecord(g.startGS)
+	rec.Meta.Source = "play"
+	for i := range rec.Hands {
+		if i != g.MyPlayer {
+			rec.Hands[i] = ""
+		}
+	}
+	for _, e := range g.log {
+		for _, m := range e.moves {
+			rec.AddMove(m)
+		}
+	}
+	rec.Finish(g.GS)
+	return rec
+}
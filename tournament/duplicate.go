@@ -0,0 +1,141 @@
+package tournament
+
+import (
+	"math"
+	"sort"
+)
+
+// Points is de score van eindplaats place aan een tafel van players: 1 voor
+// winst, 0 voor de laatste plaats, lineair daartussen.
+func Points(place, players int) float64 {
+	return float64(players-place) / float64(players-1)
+}
+
+// DealPoints geeft per deal en per deelnemer de gemiddelde punten over zijn
+// uitgespeelde partijen op die deal; NaN als hij er geen speelde.
+func (r *Results) DealPoints() [][]float64 {
+	sum := make([][]float64, len(r.Deals))
+	cnt := make([][]int, len(r.Deals))
+	for d := range sum {
+		sum[d] = make([]float64, len(r.Entrants))
+		cnt[d] = make([]int, len(r.Entrants))
+	}
+	for _, m := range r.Matches {
+		if m.Ranking == nil {
+			continue
+		}
+		for seat, e := range m.Seats {
+			sum[m.Deal][e] += Points(m.Place(seat), r.Players)
+			cnt[m.Deal][e]++
+		}
+	}
+	for d := range sum {
+		for e := range sum[d] {
+			if cnt[d][e] == 0 {
+				sum[d][e] = math.NaN()
+			} else {
+				sum[d][e] /= float64(cnt[d][e])
+			}
+		}
+	}
+	return sum
+}
+
+// DuplicateStanding is de duplicate-uitslag van één deelnemer: hoeveel hij
+// per deal beter deed dan het gemiddelde op diezelfde kaarten. Omdat elke
+// deal door iedereen op elke plaats gespeeld wordt, valt het kaartgeluk weg
+// en is Err veel kleiner dan bij losse partijen.
+type DuplicateStanding struct {
+	Name  string
+	Deals int     // deals met minstens één uitgespeelde partij
+	Score float64 // gemiddelde punten (1 = winst, 0 = laatste)
+	Plus  float64 // gemiddeld verschil met het dealgemiddelde
+	Err   float64 // halve breedte van het 95%-interval van Plus
+}
+
+// Duplicate geeft de duplicate-stand, beste Plus eerst.
+func (r *Results) Duplicate() []DuplicateStanding {
+	points := r.DealPoints()
+	scores := make([][]float64, len(r.Entrants))
+	plus := make([][]float64, len(r.Entrants))
+	for _, row := range points {
+		var avg float64
+		n := 0
+		for _, p := range row {
+			if !math.IsNaN(p) {
+				avg += p
+				n++
+			}
+		}
+		if n < 2 {
+			continue
+		}
+		avg /= float64(n)
+		for e, p := range row {
+			if !math.IsNaN(p) {
+				scores[e] = append(scores[e], p)
+				plus[e] = append(plus[e], p-avg)
+			}
+		}
+	}
+	out := make([]DuplicateStanding, len(r.Entrants))
+	for e, name := range r.Entrants {
+		out[e] = DuplicateStanding{Name: name, Deals: len(plus[e])}
+		if len(plus[e]) > 0 {
+			out[e].Score, _ = meanErr(scores[e])
+			out[e].Plus, out[e].Err = meanErr(plus[e])
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Plus > out[j].Plus })
+	return out
+}
+
+// HeadToHead vergelijkt twee deelnemers deal per deal.
+type HeadToHead struct {
+	A, B   string
+	Deals  int // deals die beide speelden
+	Wins   int // deals waarop A meer punten haalde dan B
+	Losses int
+	Ties   int
+	Diff   float64 // gemiddeld puntenverschil A - B per deal
+	Err    float64 // halve breedte van het 95%-interval van Diff
+}
+
+// MinSignificantDeals is het minimum aantal gedeelde deals voor een
+// uitspraak over significantie. Met minder deals is de geschatte spreiding
+// zelf te onzeker: twee deals met toevallig bijna hetzelfde verschil geven
+// een smal interval.
+const MinSignificantDeals = 10
+
+// Significant meldt of het verschil buiten het 95%-interval rond 0 valt,
+// op minstens MinSignificantDeals deals.
+func (h HeadToHead) Significant() bool {
+	return h.Deals >= MinSignificantDeals && math.Abs(h.Diff) > h.Err
+}
+
+// HeadToHead vergelijkt deelnemers a en b (indexen in Entrants) op de deals
+// die ze allebei speelden.
+func (r *Results) HeadToHead(a, b int) HeadToHead {
+	h := HeadToHead{A: r.Entrants[a], B: r.Entrants[b]}
+	var diffs []float64
+	for _, row := range r.DealPoints() {
+		if math.IsNaN(row[a]) || math.IsNaN(row[b]) {
+			continue
+		}
+		d := row[a] - row[b]
+		switch {
+		case d > 1e-9:
+			h.Wins++
+		case d < -1e-9:
+			h.Losses++
+		default:
+			h.Ties++
+		}
+		diffs = append(diffs, d)
+	}
+	h.Deals = len(diffs)
+	if h.Deals > 0 {
+		h.Diff, h.Err = meanErr(diffs)
+	}
+	return h
+}
@@ -0,0 +1,194 @@
+package tournament
+
+import (
+	"math"
+	"sort"
+)
+
+// z95 is de z-waarde van een tweezijdig 95%-betrouwbaarheidsinterval.
+const z95 = 1.96
+
+// t95Table is het tweezijdige 95%-quantiel van de Student-t-verdeling voor
+// 1 t/m 30 vrijheidsgraden.
+var t95Table = [...]float64{
+	12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+	2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+	2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+}
+
+// t95 is het tweezijdige 95%-quantiel van de Student-t-verdeling met df
+// vrijheidsgraden: uit de tabel, daarboven de Cornish-Fisher-reeks rond
+// z95. Bij weinig waarnemingen is het interval zo breder dan met z95.
+func t95(df int) float64 {
+	if df < 1 {
+		return math.Inf(1)
+	}
+	if df <= len(t95Table) {
+		return t95Table[df-1]
+	}
+	z, n := z95, float64(df)
+	z3, z5 := z*z*z, z*z*z*z*z
+	return z + (z3+z)/(4*n) + (5*z5+16*z3+3*z)/(96*n*n)
+}
+
+// Standing is de samenvatting van één deelnemer. Een partij telt per
+// zitplaats: een deelnemer die twee plaatsen aan tafel bezet, speelt er twee.
+type Standing struct {
+	Name     string
+	Games    int // uitgespeelde partijen
+	Aborted  int
+	Wins     int
+	WinRate  float64
+	WinLow   float64 // 95%-interval van WinRate (Wilson)
+	WinHigh  float64
+	AvgPlace float64 // gemiddelde eindplaats, 1 = winst
+	PlaceErr float64 // halve breedte van het 95%-interval van AvgPlace
+	Elo      float64
+}
+
+// Standings geeft de stand per deelnemer, sterkste Elo eerst.
+func (r *Results) Standings() []Standing {
+	out := make([]Standing, len(r.Entrants))
+	places := make([][]float64, len(r.Entrants))
+	for i, name := range r.Entrants {
+		out[i].Name = name
+	}
+	for _, m := range r.Matches {
+		for seat, e := range m.Seats {
+			if m.Ranking == nil {
+				out[e].Aborted++
+				continue
+			}
+			p := m.Place(seat)
+			out[e].Games++
+			if p == 1 {
+				out[e].Wins++
+			}
+			places[e] = append(places[e], float64(p))
+		}
+	}
+	elo := r.Elo()
+	for i := range out {
+		s := &out[i]
+		s.Elo = elo[i]
+		if s.Games == 0 {
+			continue
+		}
+		s.WinRate = float64(s.Wins) / float64(s.Games)
+		s.WinLow, s.WinHigh = Wilson(s.Wins, s.Games)
+		s.AvgPlace, s.PlaceErr = meanErr(places[i])
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Elo > out[j].Elo })
+	return out
+}
+
+// Wilson geeft het 95%-Wilson-interval voor wins successen op n pogingen.
+func Wilson(wins, n int) (lo, hi float64) {
+	if n == 0 {
+		return 0, 1
+	}
+	p := float64(wins) / float64(n)
+	nf := float64(n)
+	z2 := z95 * z95
+	center := (p + z2/(2*nf)) / (1 + z2/nf)
+	half := z95 * math.Sqrt(p*(1-p)/nf+z2/(4*nf*nf)) / (1 + z2/nf)
+	return math.Max(0, center-half), math.Min(1, center+half)
+}
+
+// meanErr geeft het gemiddelde en de halve breedte van het 95%-interval
+// (Student-t, want bij een toernooi van enkele deals is n klein).
+func meanErr(xs []float64) (mean, err float64) {
+	n := float64(len(xs))
+	for _, x := range xs {
+		mean += x
+	}
+	mean /= n
+	if len(xs) < 2 {
+		return mean, 0
+	}
+	var ss float64
+	for _, x := range xs {
+		ss += (x - mean) * (x - mean)
+	}
+	return mean, t95(len(xs)-1) * math.Sqrt(ss/(n-1)/n)
+}
+
+// Elo schat een rating per deelnemer uit alle onderlinge vergelijkingen:
+// in elke partij wint elke deelnemer van iedere andere deelnemer die lager
+// eindigde. De sterktes volgen het Bradley-Terry-model (maximale
+// aannemelijkheid); elk paar dat elkaar trof krijgt er een halve winst per
+// kant bij, zodat ook 100% een eindige rating geeft. Het gemiddelde is 1500.
+func (r *Results) Elo() []float64 {
+	n := len(r.Entrants)
+	wins := make([][]float64, n)
+	for i := range wins {
+		wins[i] = make([]float64, n)
+	}
+	for _, m := range r.Matches {
+		if m.Ranking == nil {
+			continue
+		}
+		for a, ea := range m.Seats {
+			for b, eb := range m.Seats {
+				if ea != eb && m.Place(a) < m.Place(b) {
+					wins[ea][eb]++
+				}
+			}
+		}
+	}
+	games := make([][]float64, n)
+	for i := range games {
+		games[i] = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if wins[i][j]+wins[j][i] > 0 {
+				wins[i][j] += 0.5
+				wins[j][i] += 0.5
+			}
+			games[i][j] = wins[i][j] + wins[j][i]
+			games[j][i] = games[i][j]
+		}
+	}
+	strength := make([]float64, n)
+	for i := range strength {
+		strength[i] = 1
+	}
+	for iter := 0; iter < 1000; iter++ {
+		next := make([]float64, n)
+		change := 0.0
+		for i := 0; i < n; i++ {
+			var w, d float64
+			for j := 0; j < n; j++ {
+				if games[i][j] == 0 {
+					continue
+				}
+				w += wins[i][j]
+				d += games[i][j] / (strength[i] + strength[j])
+			}
+			next[i] = strength[i]
+			if d > 0 {
+				next[i] = w / d
+			}
+		}
+		// Normaliseren op geometrisch gemiddelde 1 (= Elo-gemiddelde 1500).
+		var logSum float64
+		for _, s := range next {
+			logSum += math.Log(s)
+		}
+		norm := math.Exp(logSum / float64(n))
+		for i := range next {
+			next[i] /= norm
+			change = math.Max(change, math.Abs(next[i]-strength[i]))
+		}
+		strength = next
+		if change < 1e-9 {
+			break
+		}
+	}
+	elo := make([]float64, n)
+	for i, s := range strength {
+		elo[i] = 1500 + 400*math.Log10(s)
+	}
+	return elo
+}
@@ -0,0 +1,311 @@
+// Package tournament laat engine-varianten (of andere strategieën) tegen
+// elkaar spelen over gedeelde deals met roterende zitplaatsen, en vat de
+// uitslagen samen: winstpercentage en gemiddelde plaats met
+// betrouwbaarheidsintervallen, en een Elo-rating.
+package tournament
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"github.com/azen-engine/engine"
+	"github.com/azen-engine/game"
+	"github.com/azen-engine/knowledge"
+)
+
+// Player kiest de zetten van één zitplaats in één partij.
+type Player interface {
+	Move(gs *game.GameState, kt *knowledge.KnowledgeTracker) game.Move
+}
+
+// Entrant is een deelnemer: een naam en een fabriek die voor elke partij een
+// nieuwe Player maakt voor een tafel van players spelers.
+type Entrant struct {
+	Name string
+	New  func(players int, seed int64) Player
+}
+
+type enginePlayer struct{ eng *engine.Engine }
+
+func (p enginePlayer) Move(gs *game.GameState, kt *knowledge.KnowledgeTracker) game.Move {
+	m, _ := p.eng.BestMove(gs, kt)
+	return m
+}
+
+// EngineEntrant speelt met de IS-MCTS engine in configuratie cfg. NumPlayers
+// en Seed worden per partij ingevuld.
+func EngineEntrant(name string, cfg engine.Config) Entrant {
+	return Entrant{Name: name, New: func(players int, seed int64) Player {
+		c := cfg
+		c.NumPlayers = players
+		c.Seed = seed
+		return enginePlayer{engine.NewEngine(c)}
+	}}
+}
+
+type greedyPlayer struct{}
+
+func (greedyPlayer) Move(gs *game.GameState, _ *knowledge.KnowledgeTracker) game.Move {
+	moves := gs.GetLegalMoves()
+	best := moves[0]
+	bestScore := engine.QuickEvaluateMove(gs, best).Score
+	for _, m := range moves[1:] {
+		if s := engine.QuickEvaluateMove(gs, m).Score; s > bestScore {
+			best, bestScore = m, s
+		}
+	}
+	return best
+}
+
+// GreedyEntrant speelt zonder zoektocht de zet met de hoogste
+// QuickEvaluateMove-score: een snelle ondergrens voor de engine.
+func GreedyEntrant(name string) Entrant {
+	return Entrant{Name: name, New: func(int, int64) Player { return greedyPlayer{} }}
+}
+
+type randomPlayer struct{ rng *rand.Rand }
+
+func (p randomPlayer) Move(gs *game.GameState, _ *knowledge.KnowledgeTracker) game.Move {
+	var plays []game.Move
+	for _, m := range gs.GetLegalMoves() {
+		if !m.IsPass {
+			plays = append(plays, m)
+		}
+	}
+	if len(plays) == 0 {
+		return game.PassMove(gs.CurrentTurn)
+	}
+	return plays[p.rng.Intn(len(plays))]
+}
+
+// RandomEntrant speelt een willekeurige legale zet en past enkel als het
+// niet anders kan.
+func RandomEntrant(name string) Entrant {
+	return Entrant{Name: name, New: func(_ int, seed int64) Player {
+		return randomPlayer{rand.New(rand.NewSource(seed))}
+	}}
+}
+
+// defaultMaxMoves is het aantal zetten waarna een partij afgebroken wordt.
+const defaultMaxMoves = 1000
+
+// Tournament beschrijft een toernooi in duplicate-vorm: elke deal wordt
+// gespeeld door elke opstelling van deelnemers in elke zitplaatsrotatie, met
+// dezelfde handen, dode kaarten en startplaats, zodat kaartgeluk voor
+// iedereen gelijk is. Duplicate vergelijkt de deelnemers per deal.
+//
+// Met evenveel of minder deelnemers dan plaatsen vullen de deelnemers de
+// tafel om beurt (A B A B); met meer deelnemers speelt elke combinatie van
+// Players deelnemers.
+type Tournament struct {
+	Entrants []Entrant
+	Players  int   // spelers per tafel (2-4)
+	Deals    int   // aantal deals
+	Seed     int64 // bepaalt deals en engines; zelfde seed = zelfde toernooi
+	Jobs     int   // partijen tegelijk (0 = 1)
+	MaxMoves int   // 0 = 1000
+	// Progress wordt na elke partij aangeroepen (nil = geen voortgang).
+	Progress func(done, total int)
+}
+
+// Match is één partij: op welke deal, welke deelnemer op welke plaats zat
+// en hoe ze eindigde.
+type Match struct {
+	Deal    int   // index in Results.Deals
+	Seats   []int // index in Entrants per zitplaats
+	Ranking []int // zitplaatsen, winnaar eerst; nil = afgebroken
+	Moves   int
+
+	seed int64
+}
+
+// Place is de eindplaats (1-based) van zitplaats seat, of 0 als de partij
+// afgebroken is.
+func (m Match) Place(seat int) int {
+	for i, s := range m.Ranking {
+		if s == seat {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Results zijn alle deals en gespeelde partijen van een toernooi.
+type Results struct {
+	Entrants []string
+	Players  int
+	Deals    []game.Deal
+	Matches  []Match
+}
+
+// Schedule deelt de kaarten en geeft alle partijen van het toernooi in
+// speelvolgorde.
+func (t *Tournament) Schedule() ([]game.Deal, []Match, error) {
+	if t.Players < 2 || t.Players > 4 {
+		return nil, nil, fmt.Errorf("ongeldig aantal spelers: %d (2-4)", t.Players)
+	}
+	if len(t.Entrants) < 2 {
+		return nil, nil, fmt.Errorf("minstens 2 deelnemers nodig")
+	}
+	if t.Deals < 1 {
+		return nil, nil, fmt.Errorf("minstens 1 deal nodig")
+	}
+	var lineups [][]int
+	if len(t.Entrants) <= t.Players {
+		lineup := make([]int, t.Players)
+		for i := range lineup {
+			lineup[i] = i % len(t.Entrants)
+		}
+		lineups = [][]int{lineup}
+	} else {
+		lineups = combinations(len(t.Entrants), t.Players)
+	}
+	rng := rand.New(rand.NewSource(t.Seed))
+	deals := make([]game.Deal, t.Deals)
+	var matches []Match
+	for d := range deals {
+		deals[d] = game.NewDeal(t.Players, rand.New(rand.NewSource(rng.Int63())))
+		for _, lineup := range lineups {
+			for _, seats := range rotations(lineup) {
+				matches = append(matches, Match{Deal: d, Seats: seats, seed: rng.Int63()})
+			}
+		}
+	}
+	return deals, matches, nil
+}
+
+// Run speelt het toernooi. De uitslag hangt niet af van Jobs.
+func (t *Tournament) Run() (*Results, error) {
+	res, err := RunAll([]*Tournament{t}, t.Jobs, t.Progress)
+	if err != nil {
+		return nil, err
+	}
+	return res[0], nil
+}
+
+// RunAll speelt de partijen van alle toernooien in één gedeelde pool van
+// jobs partijen tegelijk, zodat ook veel kleine toernooien (bv. één per
+// kandidaat bij het tunen) alle cores bezig houden. Jobs en Progress van de
+// toernooien zelf worden genegeerd; progress telt over alle partijen. De
+// uitslagen hangen niet af van jobs.
+func RunAll(ts []*Tournament, jobs int, progress func(done, total int)) ([]*Results, error) {
+	type job struct{ t, m int }
+	deals := make([][]game.Deal, len(ts))
+	matches := make([][]Match, len(ts))
+	var work []job
+	for i, t := range ts {
+		var err error
+		if deals[i], matches[i], err = t.Schedule(); err != nil {
+			return nil, err
+		}
+		for m := range matches[i] {
+			work = append(work, job{i, m})
+		}
+	}
+
+	next := make(chan job)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	for w := 0; w < max(jobs, 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range next {
+				t, m := ts[j.t], &matches[j.t][j.m]
+				maxMoves := t.MaxMoves
+				if maxMoves <= 0 {
+					maxMoves = defaultMaxMoves
+				}
+				t.play(deals[j.t][m.Deal], m, maxMoves)
+				if progress != nil {
+					mu.Lock()
+					done++
+					progress(done, len(work))
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, j := range work {
+		next <- j
+	}
+	close(next)
+	wg.Wait()
+
+	out := make([]*Results, len(ts))
+	for i, t := range ts {
+		r := &Results{Players: t.Players, Deals: deals[i], Matches: matches[i]}
+		for _, e := range t.Entrants {
+			r.Entrants = append(r.Entrants, e.Name)
+		}
+		out[i] = r
+	}
+	return out, nil
+}
+
+// play speelt partij m op deal.
+func (t *Tournament) play(deal game.Deal, m *Match, maxMoves int) {
+	n := t.Players
+	gs := deal.NewGame()
+	rng := rand.New(rand.NewSource(m.seed))
+	players := make([]Player, n)
+	trackers := make([]*knowledge.KnowledgeTracker, n)
+	for seat, e := range m.Seats {
+		players[seat] = t.Entrants[e].New(n, rng.Int63())
+		trackers[seat] = knowledge.NewKnowledgeTracker(n, seat, gs.Hands[seat], gs.DeadCards)
+	}
+	for !gs.GameOver && m.Moves < maxMoves {
+		pid := gs.CurrentTurn
+		move := players[pid].Move(gs, trackers[pid])
+		if move.IsPass {
+			for _, kt := range trackers {
+				kt.RecordPass(move.PlayerID, gs.Round)
+			}
+		}
+		gs.ApplyMove(move)
+		for _, kt := range trackers {
+			kt.RecordMove(move)
+		}
+		m.Moves++
+	}
+	if gs.GameOver {
+		m.Ranking = append([]int(nil), gs.Ranking...)
+	}
+}
+
+// combinations geeft alle deelverzamelingen van k uit 0..n-1, oplopend.
+func combinations(n, k int) [][]int {
+	var out [][]int
+	cur := make([]int, 0, k)
+	var rec func(start int)
+	rec = func(start int) {
+		if len(cur) == k {
+			out = append(out, append([]int(nil), cur...))
+			return
+		}
+		for i := start; i < n; i++ {
+			cur = append(cur, i)
+			rec(i + 1)
+			cur = cur[:len(cur)-1]
+		}
+	}
+	rec(0)
+	return out
+}
+
+// rotations geeft de verschillende cyclische rotaties van lineup: elke
+// deelnemer komt op elke plaats.
+func rotations(lineup []int) [][]int {
+	var out [][]int
+	seen := map[string]bool{}
+	for r := range lineup {
+		rot := append(append([]int(nil), lineup[r:]...), lineup[:r]...)
+		if k := fmt.Sprint(rot); !seen[k] {
+			seen[k] = true
+			out = append(out, rot)
+		}
+	}
+	return out
+}
@@ -0,0 +1,181 @@
+package tournament
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/azen-engine/game"
+)
+
+func TestSchedule(t *testing.T) {
+	for _, tc := range []struct {
+		entrants, players, perDeal int
+	}{
+		{2, 2, 2},  // A B, B A
+		{2, 4, 2},  // A B A B, B A B A
+		{3, 2, 6},  // 3 paren, elk in 2 rotaties
+		{5, 4, 20}, // 5 combinaties, elk in 4 rotaties
+	} {
+		tr := &Tournament{Players: tc.players, Deals: 3}
+		for i := 0; i < tc.entrants; i++ {
+			tr.Entrants = append(tr.Entrants, RandomEntrant("r"))
+		}
+		deals, matches, err := tr.Schedule()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(deals) != 3 || len(matches) != 3*tc.perDeal {
+			t.Errorf("%d deelnemers, %d spelers: %d partijen, want %d", tc.entrants, tc.players, len(matches), 3*tc.perDeal)
+		}
+		// Elke deelnemer zit binnen een deal even vaak op elke plaats.
+		seats := map[[2]int]int{}
+		for _, m := range matches[:tc.perDeal] {
+			for s, e := range m.Seats {
+				seats[[2]int{e, s}]++
+			}
+		}
+		for e := 0; e < tc.entrants; e++ {
+			for s := 1; s < tc.players; s++ {
+				if seats[[2]int{e, s}] != seats[[2]int{e, 0}] {
+					t.Errorf("%d deelnemers, %d spelers: deelnemer %d niet gelijk verdeeld: %v", tc.entrants, tc.players, e, seats)
+				}
+			}
+		}
+	}
+}
+
+// TestRunDeterministic speelt snelle strategieën: dezelfde seed geeft
+// hetzelfde toernooi, ongeacht het aantal parallelle partijen.
+func TestRunDeterministic(t *testing.T) {
+	run := func(jobs int) *Results {
+		tr := &Tournament{
+			Entrants: []Entrant{GreedyEntrant("greedy"), RandomEntrant("random")},
+			Players:  3,
+			Deals:    5,
+			Seed:     7,
+			Jobs:     jobs,
+		}
+		res, err := tr.Run()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	a, b := run(1), run(3)
+	if !reflect.DeepEqual(a, b) {
+		t.Fatal("uitslag hangt af van Jobs")
+	}
+	st := a.Standings()
+	if st[0].Name != "greedy" || st[0].Games+st[1].Games+st[0].Aborted+st[1].Aborted != 5*3*3 {
+		t.Errorf("stand %+v", st)
+	}
+}
+
+// TestRunAllMatchesRun: toernooien in een gedeelde pool geven dezelfde
+// uitslag als elk apart.
+func TestRunAllMatchesRun(t *testing.T) {
+	mk := func(seed int64) *Tournament {
+		return &Tournament{
+			Entrants: []Entrant{GreedyEntrant("greedy"), RandomEntrant("random")},
+			Players:  2,
+			Deals:    3,
+			Seed:     seed,
+		}
+	}
+	all, err := RunAll([]*Tournament{mk(1), mk(2)}, 4, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, seed := range []int64{1, 2} {
+		one, err := mk(seed).Run()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(all[i], one) {
+			t.Errorf("toernooi %d verschilt in de gedeelde pool", i)
+		}
+	}
+}
+
+func TestWilson(t *testing.T) {
+	lo, hi := Wilson(50, 100)
+	if math.Abs(lo-0.4038) > 1e-3 || math.Abs(hi-0.5962) > 1e-3 {
+		t.Errorf("Wilson(50, 100) = %.4f–%.4f", lo, hi)
+	}
+	if lo, hi := Wilson(0, 10); lo != 0 || hi <= 0 || hi >= 0.35 {
+		t.Errorf("Wilson(0, 10) = %.4f–%.4f", lo, hi)
+	}
+}
+
+func TestElo(t *testing.T) {
+	// A wint 7 van de 10 partijen tegen B: met de halve winst per kant is de
+	// verhouding 7.5 : 3.5.
+	res := &Results{Entrants: []string{"A", "B"}, Players: 2}
+	for i := 0; i < 10; i++ {
+		m := Match{Seats: []int{0, 1}, Ranking: []int{0, 1}}
+		if i >= 7 {
+			m.Ranking = []int{1, 0}
+		}
+		res.Matches = append(res.Matches, m)
+	}
+	elo := res.Elo()
+	want := 400 * math.Log10(7.5/3.5)
+	if math.Abs(elo[0]-elo[1]-want) > 0.01 || math.Abs(elo[0]+elo[1]-3000) > 0.01 {
+		t.Errorf("Elo = %v, want verschil %.1f rond 1500", elo, want)
+	}
+}
+
+func TestDuplicate(t *testing.T) {
+	// Twee deals, A en B elk op beide plaatsen. Deal 0 wint de startplaats
+	// altijd (gelijk); deal 1 wint A beide keren.
+	res := &Results{Entrants: []string{"A", "B"}, Players: 2, Deals: make([]game.Deal, 2)}
+	res.Matches = []Match{
+		{Deal: 0, Seats: []int{0, 1}, Ranking: []int{0, 1}},
+		{Deal: 0, Seats: []int{1, 0}, Ranking: []int{0, 1}},
+		{Deal: 1, Seats: []int{0, 1}, Ranking: []int{0, 1}},
+		{Deal: 1, Seats: []int{1, 0}, Ranking: []int{1, 0}},
+	}
+	dup := res.Duplicate()
+	if dup[0].Name != "A" || dup[0].Deals != 2 || dup[0].Score != 0.75 || dup[0].Plus != 0.25 || dup[1].Plus != -0.25 {
+		t.Errorf("Duplicate = %+v", dup)
+	}
+	h := res.HeadToHead(0, 1)
+	if h.Deals != 2 || h.Wins != 1 || h.Ties != 1 || h.Losses != 0 || h.Diff != 0.5 {
+		t.Errorf("HeadToHead = %+v", h)
+	}
+}
+
+func TestT95(t *testing.T) {
+	for _, tc := range []struct {
+		df   int
+		want float64
+	}{{1, 12.706}, {9, 2.262}, {30, 2.042}, {31, 2.040}, {60, 2.000}, {1000, 1.962}} {
+		if got := t95(tc.df); math.Abs(got-tc.want) > 0.001 {
+			t.Errorf("t95(%d) = %.4f, want %.3f", tc.df, got, tc.want)
+		}
+	}
+}
+
+func TestSignificantNeedsDeals(t *testing.T) {
+	// A wint elke deal nipt, met bijna geen spreiding: op twee deals is dat
+	// nog geen uitspraak, op twaalf wel.
+	for _, tc := range []struct {
+		deals int
+		want  bool
+	}{{2, false}, {MinSignificantDeals - 1, false}, {12, true}} {
+		res := &Results{Entrants: []string{"A", "B"}, Players: 2, Deals: make([]game.Deal, tc.deals)}
+		for d := 0; d < tc.deals; d++ {
+			ranking := []int{1, 0}
+			if d%3 == 0 {
+				ranking = []int{0, 1} // gelijk op deze deal
+			}
+			res.Matches = append(res.Matches,
+				Match{Deal: d, Seats: []int{0, 1}, Ranking: []int{0, 1}},
+				Match{Deal: d, Seats: []int{1, 0}, Ranking: ranking})
+		}
+		if h := res.HeadToHead(0, 1); h.Significant() != tc.want {
+			t.Errorf("%d deals: Significant() = %v, want %v (%+v)", tc.deals, !tc.want, tc.want, h)
+		}
+	}
+}
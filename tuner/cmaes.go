@@ -0,0 +1,218 @@
+package tuner
+
+import (
+	"math"
+	"math/rand"
+)
+
+// CMAES is de covariance matrix adaptation evolution strategy: kandidaten
+// komen uit een normale verdeling rond Mean, en na elke generatie schuiven
+// het gemiddelde, de stapgrootte Sigma en de covariantie C op naar de beste
+// helft. Omdat enkel de rangorde van de scores telt, stoort de ruis op de
+// partijuitslagen weinig.
+type CMAES struct {
+	Mean   []float64   `json:"mean"`
+	Sigma  float64     `json:"sigma"`
+	C      [][]float64 `json:"c"`
+	Pc     []float64   `json:"pc"` // evolutiepad van C
+	Ps     []float64   `json:"ps"` // evolutiepad van Sigma
+	Lambda int         `json:"lambda"`
+	Gen    int         `json:"gen"` // afgeronde generaties
+}
+
+// NewCMAES maakt een CMA-ES rond x0 met lambda kandidaten per generatie
+// (0 = 4 + 3·ln(n)) en een beginstapgrootte van 0.1 van het bereik.
+func NewCMAES(x0 []float64, lambda int) *CMAES {
+	n := len(x0)
+	if lambda <= 0 {
+		lambda = 4 + int(3*math.Log(float64(n)))
+	}
+	c := make([][]float64, n)
+	for i := range c {
+		c[i] = make([]float64, n)
+		c[i][i] = 1
+	}
+	return &CMAES{
+		Mean:   x0,
+		Sigma:  0.1,
+		C:      c,
+		Pc:     make([]float64, n),
+		Ps:     make([]float64, n),
+		Lambda: max(lambda, 2),
+	}
+}
+
+// cmaesParams zijn de standaard leersnelheden (Hansen, "The CMA Evolution
+// Strategy: A Tutorial").
+type cmaesParams struct {
+	mu                    int
+	weights               []float64
+	mueff, cc, cs, c1, cm float64
+	damps, chiN           float64
+}
+
+func (e *CMAES) params() cmaesParams {
+	n := float64(len(e.Mean))
+	p := cmaesParams{mu: e.Lambda / 2}
+	var sum, sq float64
+	for i := 0; i < p.mu; i++ {
+		w := math.Log(float64(p.mu)+0.5) - math.Log(float64(i+1))
+		p.weights = append(p.weights, w)
+		sum += w
+	}
+	for i := range p.weights {
+		p.weights[i] /= sum
+		sq += p.weights[i] * p.weights[i]
+	}
+	p.mueff = 1 / sq
+	p.cc = (4 + p.mueff/n) / (n + 4 + 2*p.mueff/n)
+	p.cs = (p.mueff + 2) / (n + p.mueff + 5)
+	p.c1 = 2 / ((n+1.3)*(n+1.3) + p.mueff)
+	p.cm = math.Min(1-p.c1, 2*(p.mueff-2+1/p.mueff)/((n+2)*(n+2)+p.mueff))
+	p.damps = 1 + 2*math.Max(0, math.Sqrt((p.mueff-1)/(n+1))-1) + p.cs
+	p.chiN = math.Sqrt(n) * (1 - 1/(4*n) + 1/(21*n*n))
+	return p
+}
+
+func (e *CMAES) Ask(rng *rand.Rand) [][]float64 {
+	n := len(e.Mean)
+	b, d := symEigen(e.C)
+	xs := make([][]float64, e.Lambda)
+	for k := range xs {
+		z := make([]float64, n)
+		for j := range z {
+			z[j] = d[j] * rng.NormFloat64()
+		}
+		x := make([]float64, n)
+		for i := range x {
+			var y float64
+			for j := range z {
+				y += b[i][j] * z[j]
+			}
+			// Buiten het bereik speelt de kandidaat op de grens, dus telt
+			// hij ook zo mee in de update.
+			x[i] = clamp(e.Mean[i]+e.Sigma*y, 0, 1)
+		}
+		xs[k] = x
+	}
+	return xs
+}
+
+func (e *CMAES) Tell(xs [][]float64, scores []float64) {
+	n := len(e.Mean)
+	p := e.params()
+	order := ranked(scores)
+	old := e.Mean
+	mean := make([]float64, n)
+	for r := 0; r < p.mu; r++ {
+		for i, v := range xs[order[r]] {
+			mean[i] += p.weights[r] * v
+		}
+	}
+	yw := make([]float64, n)
+	for i := range yw {
+		yw[i] = (mean[i] - old[i]) / e.Sigma
+	}
+
+	// ps volgt C^(-1/2)·yw, zodat zijn lengte los van de vorm van C met
+	// chiN vergeleken kan worden.
+	b, d := symEigen(e.C)
+	tmp := make([]float64, n)
+	for j := range tmp {
+		for i := range yw {
+			tmp[j] += b[i][j] * yw[i]
+		}
+		tmp[j] /= d[j]
+	}
+	ks := math.Sqrt(p.cs * (2 - p.cs) * p.mueff)
+	var psNorm float64
+	for i := range e.Ps {
+		var v float64
+		for j := range tmp {
+			v += b[i][j] * tmp[j]
+		}
+		e.Ps[i] = (1-p.cs)*e.Ps[i] + ks*v
+		psNorm += e.Ps[i] * e.Ps[i]
+	}
+	psNorm = math.Sqrt(psNorm)
+	hsig := 0.0
+	if psNorm/math.Sqrt(1-math.Pow(1-p.cs, 2*float64(e.Gen+1)))/p.chiN < 1.4+2/(float64(n)+1) {
+		hsig = 1
+	}
+	kc := math.Sqrt(p.cc * (2 - p.cc) * p.mueff)
+	for i := range e.Pc {
+		e.Pc[i] = (1-p.cc)*e.Pc[i] + hsig*kc*yw[i]
+	}
+
+	for i := range e.C {
+		for j := range e.C[i] {
+			rankMu := 0.0
+			for r := 0; r < p.mu; r++ {
+				x := xs[order[r]]
+				rankMu += p.weights[r] * (x[i] - old[i]) * (x[j] - old[j]) / (e.Sigma * e.Sigma)
+			}
+			e.C[i][j] = (1-p.c1-p.cm)*e.C[i][j] +
+				p.c1*(e.Pc[i]*e.Pc[j]+(1-hsig)*p.cc*(2-p.cc)*e.C[i][j]) +
+				p.cm*rankMu
+		}
+	}
+	e.Sigma *= math.Exp(p.cs / p.damps * (psNorm/p.chiN - 1))
+	e.Mean = mean
+	e.Gen++
+}
+
+// symEigen ontbindt de symmetrische matrix a met de Jacobi-methode: a =
+// B·diag(d²)·Bᵀ. De kolommen van B zijn de eigenvectoren; d zijn de
+// wortels van de eigenwaarden (negatieve afrondingsfouten worden 0).
+func symEigen(a [][]float64) (b [][]float64, d []float64) {
+	n := len(a)
+	m := make([][]float64, n)
+	b = make([][]float64, n)
+	for i := range m {
+		m[i] = append([]float64(nil), a[i]...)
+		b[i] = make([]float64, n)
+		b[i][i] = 1
+	}
+	for sweep := 0; sweep < 50; sweep++ {
+		var off float64
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				off += m[i][j] * m[i][j]
+			}
+		}
+		if off < 1e-20 {
+			break
+		}
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if math.Abs(m[p][q]) < 1e-15 {
+					continue
+				}
+				theta := (m[q][q] - m[p][p]) / (2 * m[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := 0; k < n; k++ {
+					mkp, mkq := m[k][p], m[k][q]
+					m[k][p], m[k][q] = c*mkp-s*mkq, s*mkp+c*mkq
+				}
+				for k := 0; k < n; k++ {
+					mpk, mqk := m[p][k], m[q][k]
+					m[p][k], m[q][k] = c*mpk-s*mqk, s*mpk+c*mqk
+				}
+				for k := 0; k < n; k++ {
+					bkp, bkq := b[k][p], b[k][q]
+					b[k][p], b[k][q] = c*bkp-s*bkq, s*bkp+c*bkq
+				}
+			}
+		}
+	}
+	d = make([]float64, n)
+	for i := range d {
+		d[i] = math.Sqrt(math.Max(m[i][i], 1e-20))
+	}
+	return b, d
+}
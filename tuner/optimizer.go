@@ -0,0 +1,175 @@
+package tuner
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Optimizer zoekt in de genormaliseerde gewichtenruimte (zie Params) naar
+// de vector met de hoogste score. Elke generatie vraagt de tuner kandidaten
+// met Ask, speelt ze en geeft hun scores terug met Tell.
+//
+// De toestand van een Optimizer moet volledig in zijn JSON-vorm zitten, zodat
+// een checkpoint hem kan bewaren en herstellen.
+type Optimizer interface {
+	// Ask geeft de kandidaten van de volgende generatie.
+	Ask(rng *rand.Rand) [][]float64
+	// Tell geeft de scores van de kandidaten van Ask terug, in dezelfde
+	// volgorde; hoger is beter.
+	Tell(xs [][]float64, scores []float64)
+}
+
+// Optimizers zijn de namen die NewOptimizer kent; de eerste is de standaard.
+var Optimizers = []string{"cmaes", "spsa", "mutate"}
+
+// NewOptimizer maakt optimizer name die vertrekt van x0. Candidates is het
+// aantal kandidaten per generatie (0 = standaard van de optimizer);
+// generations het totaal aantal generaties.
+func NewOptimizer(name string, x0 []float64, candidates, generations int) (Optimizer, error) {
+	x0 = append([]float64(nil), x0...)
+	switch name {
+	case "", "cmaes":
+		return NewCMAES(x0, candidates), nil
+	case "spsa":
+		return NewSPSA(x0, candidates, generations), nil
+	case "mutate":
+		if candidates <= 0 {
+			candidates = 15
+		}
+		return &Mutate{Center: x0, Candidates: candidates, Generations: generations}, nil
+	}
+	return nil, fmt.Errorf("onbekende optimizer %q (%v)", name, Optimizers)
+}
+
+// restoreOptimizer herstelt optimizer name uit zijn JSON-toestand.
+func restoreOptimizer(name string, state json.RawMessage) (Optimizer, error) {
+	opt, err := NewOptimizer(name, make([]float64, len(Params)), 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(state, opt); err != nil {
+		return nil, fmt.Errorf("toestand van %s: %v", name, err)
+	}
+	return opt, nil
+}
+
+// Mutate is de oorspronkelijke tuner: willekeurige mutanten rond het
+// centrum, dat naar de beste kandidaat verhuist zodra die beter scoort dan
+// de basislijn. In het laatste 40% van de generaties wordt fijner gemuteerd.
+type Mutate struct {
+	Center      []float64 `json:"center"`
+	Candidates  int       `json:"candidates"`
+	Generations int       `json:"generations"`
+	Gen         int       `json:"gen"` // afgeronde generaties
+}
+
+func (m *Mutate) Ask(rng *rand.Rand) [][]float64 {
+	strength := 0.22
+	if float64(m.Gen+1) > float64(m.Generations)*0.6 {
+		strength = 0.09
+	}
+	center := FromVector(m.Center)
+	xs := make([][]float64, m.Candidates)
+	for i := range xs {
+		xs[i] = Vector(Perturb(center, rng, strength))
+	}
+	return xs
+}
+
+func (m *Mutate) Tell(xs [][]float64, scores []float64) {
+	m.Gen++
+	best := argmax(scores)
+	if scores[best] > 0 {
+		m.Center = append([]float64(nil), xs[best]...)
+	}
+}
+
+// argmax geeft de index van de hoogste waarde.
+func argmax(xs []float64) int {
+	best := 0
+	for i, x := range xs {
+		if x > xs[best] {
+			best = i
+		}
+	}
+	return best
+}
+
+// ranked geeft de indexen van scores, hoogste eerst.
+func ranked(scores []float64) []int {
+	idx := make([]int, len(scores))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return scores[idx[a]] > scores[idx[b]] })
+	return idx
+}
+
+// SPSA (simultaneous perturbation stochastic approximation) schat de
+// gradiënt uit paren kandidaten Theta ± c·Δ met een willekeurig teken per
+// parameter, en zet een stap in die richting. Twee partijen per richting
+// volstaan, hoeveel parameters er ook zijn.
+type SPSA struct {
+	Theta []float64 `json:"theta"`
+	Pairs int       `json:"pairs"` // kandidatenparen per generatie
+	K     int       `json:"k"`     // afgeronde generaties
+	A     float64   `json:"a"`     // stapgrootte
+	C     float64   `json:"c"`     // perturbatiegrootte (fractie van het bereik)
+	Big   float64   `json:"big_a"` // stabiliteitsconstante voor de eerste stappen
+}
+
+// NewSPSA maakt een SPSA-optimizer met candidates/2 paren per generatie
+// (standaard 2) en de gebruikelijke vervalexponenten.
+func NewSPSA(x0 []float64, candidates, generations int) *SPSA {
+	pairs := candidates / 2
+	if pairs <= 0 {
+		pairs = 2
+	}
+	return &SPSA{Theta: x0, Pairs: pairs, A: 0.05, C: 0.1, Big: math.Max(1, float64(generations)/10)}
+}
+
+const (
+	spsaAlpha = 0.602
+	spsaGamma = 0.101
+)
+
+func (s *SPSA) Ask(rng *rand.Rand) [][]float64 {
+	c := s.C / math.Pow(float64(s.K+1), spsaGamma)
+	xs := make([][]float64, 0, 2*s.Pairs)
+	for p := 0; p < s.Pairs; p++ {
+		plus := make([]float64, len(s.Theta))
+		minus := make([]float64, len(s.Theta))
+		for i, t := range s.Theta {
+			d := c
+			if rng.Intn(2) == 0 {
+				d = -c
+			}
+			plus[i], minus[i] = clamp(t+d, 0, 1), clamp(t-d, 0, 1)
+		}
+		xs = append(xs, plus, minus)
+	}
+	return xs
+}
+
+func (s *SPSA) Tell(xs [][]float64, scores []float64) {
+	a := s.A / math.Pow(float64(s.K+1)+s.Big, spsaAlpha)
+	grad := make([]float64, len(s.Theta))
+	for p := 0; p+1 < len(xs); p += 2 {
+		df := scores[p] - scores[p+1]
+		for i := range grad {
+			// Met de echte (afgeronde) verschillen blijft de schatting
+			// kloppen aan de grenzen; waar beide op de grens liggen is er
+			// geen informatie.
+			if dx := xs[p][i] - xs[p+1][i]; dx != 0 {
+				grad[i] += df / dx
+			}
+		}
+	}
+	for i := range s.Theta {
+		s.Theta[i] = clamp(s.Theta[i]+a*grad[i]/float64(len(xs)/2), 0, 1)
+	}
+	s.K++
+}
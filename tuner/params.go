@@ -0,0 +1,78 @@
+package tuner
+
+import (
+	"math/rand"
+
+	"github.com/azen-engine/engine"
+)
+
+// Param is een tunebaar gewicht met zijn grenzen. De optimizers werken in
+// een genormaliseerde ruimte: 0 is Min, 1 is Max.
+type Param struct {
+	Name     string // veldnaam in weights.json
+	Min, Max float64
+	field    func(w *engine.Weights) *float64
+}
+
+// Params zijn alle velden van engine.Weights met hun grenzen, in de volgorde
+// van de struct.
+var Params = []Param{
+	{"ace_bonus", 0.08, 0.85, func(w *engine.Weights) *float64 { return &w.AceBonus }},
+	{"wild_bonus", 0.08, 0.65, func(w *engine.Weights) *float64 { return &w.WildBonus }},
+	{"synergy_bonus", 0.02, 0.45, func(w *engine.Weights) *float64 { return &w.SynergyBonus }},
+	{"card_diff_weight", 0.02, 0.30, func(w *engine.Weights) *float64 { return &w.CardDiffWeight }},
+	{"king_penalty", 0.01, 0.18, func(w *engine.Weights) *float64 { return &w.KingPenalty }},
+	{"queen_penalty", 0.01, 0.15, func(w *engine.Weights) *float64 { return &w.QueenPenalty }},
+	{"isolated_low_penalty", 0.01, 0.18, func(w *engine.Weights) *float64 { return &w.IsolatedLowPenalty }},
+	{"cluster_bonus", 0.01, 0.20, func(w *engine.Weights) *float64 { return &w.ClusterBonus }},
+	{"tempo_bonus", 0.02, 0.30, func(w *engine.Weights) *float64 { return &w.TempoBonus }},
+	{"ace_play_factor", 0.15, 1.3, func(w *engine.Weights) *float64 { return &w.AcePlayFactor }},
+	{"wild_play_factor", 0.15, 1.1, func(w *engine.Weights) *float64 { return &w.WildPlayFactor }},
+	{"synergy_penalty", 0.15, 1.1, func(w *engine.Weights) *float64 { return &w.SynergyPenalty }},
+	{"rank_preference", 0.02, 0.45, func(w *engine.Weights) *float64 { return &w.RankPreference }},
+	{"pass_base", 0.02, 0.35, func(w *engine.Weights) *float64 { return &w.PassBase }},
+	{"pass_special_factor", 0.05, 0.65, func(w *engine.Weights) *float64 { return &w.PassSpecialFactor }},
+	{"pass_behind_factor", 0.10, 0.85, func(w *engine.Weights) *float64 { return &w.PassBehindFactor }},
+	{"urgency_penalty", 0.02, 0.25, func(w *engine.Weights) *float64 { return &w.UrgencyPenalty }},
+	{"early_game_pass_factor", 0.05, 0.80, func(w *engine.Weights) *float64 { return &w.EarlyGamePassFactor }},
+}
+
+// Vector zet gewichten om naar de genormaliseerde ruimte.
+func Vector(w engine.Weights) []float64 {
+	x := make([]float64, len(Params))
+	for i, p := range Params {
+		x[i] = (*p.field(&w) - p.Min) / (p.Max - p.Min)
+	}
+	return x
+}
+
+// FromVector zet een genormaliseerde vector om naar gewichten; waarden
+// buiten [0, 1] worden naar de grens gebracht.
+func FromVector(x []float64) engine.Weights {
+	w := engine.DefaultWeights()
+	for i, p := range Params {
+		*p.field(&w) = p.Min + clamp(x[i], 0, 1)*(p.Max-p.Min)
+	}
+	return w
+}
+
+// Perturb vermenigvuldigt elk gewicht met een willekeurige factor in
+// [1-strength, 1+strength] en houdt het binnen zijn grenzen.
+func Perturb(base engine.Weights, rng *rand.Rand, strength float64) engine.Weights {
+	w := base
+	for _, p := range Params {
+		f := p.field(&w)
+		*f = clamp(*f*(1+strength*(rng.Float64()*2-1)), p.Min, p.Max)
+	}
+	return w
+}
+
+func clamp(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,300 @@
+// Package tuner optimaliseert de evaluatiegewichten van de engine. Elke
+// kandidaat speelt duplicate-partijen (zelfde deals, elke deelnemer op elke
+// plaats) tegen een basislijn, zodat de score het verschil in speelsterkte
+// meet en niet het kaart- of plaatsgeluk. Het eindresultaat wordt op verse
+// deals tegen de startgewichten gecontroleerd voor het aanvaard wordt.
+package tuner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+
+	"github.com/azen-engine/engine"
+	"github.com/azen-engine/tournament"
+)
+
+// Baseline bepaalt waartegen de kandidaten spelen.
+type Baseline int
+
+const (
+	// BaselineBest: tegen de beste gewichten tot nu toe. Een kandidaat
+	// vervangt die enkel als hij ze significant verslaat.
+	BaselineBest Baseline = iota
+	// BaselineStart: altijd tegen de startgewichten. De kandidaat met het
+	// grootste verschil wordt de nieuwe beste.
+	BaselineStart
+)
+
+func (b Baseline) String() string {
+	if b == BaselineStart {
+		return "start"
+	}
+	return "best"
+}
+
+// ParseBaseline leest "best" of "start".
+func ParseBaseline(s string) (Baseline, error) {
+	switch s {
+	case "best":
+		return BaselineBest, nil
+	case "start":
+		return BaselineStart, nil
+	}
+	return 0, fmt.Errorf("onbekende basislijn %q (best, start)", s)
+}
+
+// Tuner beschrijft een tuning-run: per generatie stelt de optimizer
+// kandidaten voor, die elk tegen de basislijn spelen op dezelfde Deals
+// deals. De beste kandidaat van een generatie wordt de nieuwe beste volgens
+// de regels van Baseline.
+type Tuner struct {
+	Engine      engine.Config // zoekinstellingen; Weights zijn de startgewichten
+	Optimizer   string        // een van Optimizers ("" = cmaes)
+	Generations int
+	Candidates  int // kandidaten per generatie (0 = standaard van de optimizer)
+	Deals       int // deals per kandidaat; elke deal op elke plaats gespeeld
+	Validate    int // deals voor de eindcontrole (0 = Deals)
+	Baseline    Baseline
+	Seed        int64 // bepaalt kandidaten, deals en engines
+	// Jobs is het aantal partijen tegelijk (0 = 1), over alle kandidaten
+	// van een generatie heen. Elke partij heeft eigen engines en toeval;
+	// Engine.NumWorkers is de parallelle zoektocht binnen één zet. Met
+	// weinig iteraties per zet haalt Jobs × 1 worker meer uit de cores dan
+	// 1 × Jobs workers.
+	Jobs     int
+	MaxMoves int // 0 = 1000
+	// CheckpointFile krijgt na elke generatie de voortgang ("" = niet
+	// bewaren); LoadCheckpoint en Run(cp) gaan daar later mee verder.
+	CheckpointFile string
+	// Out krijgt de voortgangsberichten (nil = geen).
+	Out io.Writer
+	// Progress wordt na elke partij aangeroepen met de generatie (0 = de
+	// eindcontrole) en het aantal gespeelde partijen daarin (nil = geen).
+	Progress func(gen, done, total int)
+}
+
+// Checkpoint is de voortgang van een tuning-run na een afgeronde generatie.
+// Omdat elke generatie haar toeval uit Seed en haar nummer haalt, geeft
+// verdergaan vanaf een checkpoint hetzelfde resultaat als een ononderbroken
+// run.
+type Checkpoint struct {
+	Version    int             `json:"version"`
+	Seed       int64           `json:"seed"`
+	Baseline   string          `json:"baseline"`
+	Generation int             `json:"generation"` // afgeronde generaties
+	Optimizer  string          `json:"optimizer"`
+	State      json.RawMessage `json:"state"` // toestand van de optimizer
+	Start      engine.Weights  `json:"start"`
+	Best       engine.Weights  `json:"best"`
+	Score      float64         `json:"score"` // puntenverschil per deal van Best tegen de basislijn
+	Err        float64         `json:"err"`   // 95%-marge van Score
+}
+
+const checkpointVersion = 2
+
+// LoadCheckpoint leest een checkpoint van Tuner.CheckpointFile.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if cp.Version != checkpointVersion {
+		return nil, fmt.Errorf("%s: onbekende versie %d", path, cp.Version)
+	}
+	return &cp, nil
+}
+
+// Save schrijft het checkpoint via een tijdelijk bestand, zodat een
+// onderbreking tijdens het schrijven het vorige checkpoint niet beschadigt.
+func (cp *Checkpoint) Save(path string) error {
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Outcome is het resultaat van een tuning-run.
+type Outcome struct {
+	Start, Best engine.Weights
+	// Validation vergelijkt Best met de startgewichten op verse deals; leeg
+	// als er geen betere kandidaat gevonden werd.
+	Validation tournament.HeadToHead
+}
+
+// Improved meldt of Best op de controledeals significant beter speelde dan
+// de startgewichten. Enkel dan horen de nieuwe gewichten bewaard te worden.
+func (o *Outcome) Improved() bool {
+	return o.Validation.Deals > 0 && o.Validation.Diff > 0 && o.Validation.Significant()
+}
+
+// Run voert de tuning uit, of gaat verder vanaf cp (nil = van voren af aan).
+// Een checkpoint bepaalt dan de seed, de basislijn, de optimizer en de
+// startgewichten.
+func (t *Tuner) Run(cp *Checkpoint) (*Outcome, error) {
+	if t.Generations < 1 || t.Deals < 1 {
+		return nil, fmt.Errorf("minstens 1 generatie en 1 deal nodig")
+	}
+	out := t.Out
+	if out == nil {
+		out = io.Discard
+	}
+	var opt Optimizer
+	if cp == nil {
+		name := t.Optimizer
+		if name == "" {
+			name = Optimizers[0]
+		}
+		var err error
+		if opt, err = NewOptimizer(name, Vector(t.Engine.Weights), t.Candidates, t.Generations); err != nil {
+			return nil, err
+		}
+		cp = &Checkpoint{
+			Version:   checkpointVersion,
+			Seed:      t.Seed,
+			Baseline:  t.Baseline.String(),
+			Optimizer: name,
+			Start:     t.Engine.Weights,
+			Best:      t.Engine.Weights,
+		}
+	} else {
+		b, err := ParseBaseline(cp.Baseline)
+		if err != nil {
+			return nil, err
+		}
+		t.Baseline = b
+		if opt, err = restoreOptimizer(cp.Optimizer, cp.State); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(out, "Verder vanaf generatie %d (seed %d, %s)\n", cp.Generation+1, cp.Seed, cp.Optimizer)
+	}
+	// Met te weinig deals is niets ooit significant en wordt er nooit iets
+	// aanvaard of bewaard (zie tournament.MinSignificantDeals).
+	validate := t.Validate
+	if validate <= 0 {
+		validate = t.Deals
+	}
+	if need := tournament.MinSignificantDeals; validate < need || (t.Baseline == BaselineBest && t.Deals < need) {
+		return nil, fmt.Errorf("minstens %d deals nodig om een significante verbetering vast te stellen", need)
+	}
+
+	for cp.Generation < t.Generations {
+		gen := cp.Generation + 1
+		rng := rand.New(rand.NewSource(cp.Seed + int64(gen)))
+		dealSeed := rng.Int63()
+		baseline := cp.Best
+		if t.Baseline == BaselineStart {
+			baseline = cp.Start
+		}
+		fmt.Fprintf(out, "Generatie %2d/%d  ─  beste: %+.3f ± %.3f tegen %s\n",
+			gen, t.Generations, cp.Score, cp.Err, t.Baseline)
+
+		xs := opt.Ask(rng)
+		results, err := t.evaluate(gen, xs, baseline, dealSeed)
+		if err != nil {
+			return nil, err
+		}
+		scores := make([]float64, len(xs))
+		for c, h := range results {
+			scores[c] = h.Diff
+			fmt.Fprintf(out, "   kandidaat %2d: %+.3f ± %.3f (%d beter, %d gelijk, %d slechter)\n",
+				c+1, h.Diff, h.Err, h.Wins, h.Ties, h.Losses)
+		}
+		opt.Tell(xs, scores)
+		best := argmax(scores)
+		genBest, genScore := FromVector(xs[best]), results[best]
+
+		accept := false
+		switch t.Baseline {
+		case BaselineBest:
+			accept = genScore.Diff > 0 && genScore.Significant()
+		case BaselineStart:
+			accept = genScore.Diff > cp.Score
+		}
+		if accept {
+			cp.Best, cp.Score, cp.Err = genBest, genScore.Diff, genScore.Err
+			fmt.Fprintf(out, "   🔥 NIEUWE BESTE: %+.3f ± %.3f\n", genScore.Diff, genScore.Err)
+		}
+		cp.Generation = gen
+		if cp.State, err = json.Marshal(opt); err != nil {
+			return nil, err
+		}
+		if t.CheckpointFile != "" {
+			if err := cp.Save(t.CheckpointFile); err != nil {
+				return nil, fmt.Errorf("checkpoint bewaren: %v", err)
+			}
+		}
+	}
+
+	o := &Outcome{Start: cp.Start, Best: cp.Best}
+	if cp.Best == cp.Start {
+		fmt.Fprintln(out, "Geen kandidaat beter dan de startgewichten.")
+		return o, nil
+	}
+	fmt.Fprintf(out, "Controle: beste tegen startgewichten op %d verse deals\n", validate)
+	// Generaties gebruiken Seed+1 en verder; Seed zelf levert de controledeals.
+	seed := rand.New(rand.NewSource(cp.Seed)).Int63()
+	hs, err := t.play(0, []*tournament.Tournament{t.tournament(cp.Best, cp.Start, validate, seed)})
+	if err != nil {
+		return nil, err
+	}
+	h := hs[0]
+	h.A, h.B = "beste", "start"
+	o.Validation = h
+	return o, nil
+}
+
+// evaluate speelt elke kandidaat tegen baseline op dezelfde deals. Alle
+// partijen van de generatie delen één pool van Jobs partijen tegelijk.
+func (t *Tuner) evaluate(gen int, xs [][]float64, baseline engine.Weights, dealSeed int64) ([]tournament.HeadToHead, error) {
+	ts := make([]*tournament.Tournament, len(xs))
+	for c, x := range xs {
+		ts[c] = t.tournament(FromVector(x), baseline, t.Deals, dealSeed)
+	}
+	return t.play(gen, ts)
+}
+
+// tournament is een duplicate-toernooi van gewichten a tegen b.
+func (t *Tuner) tournament(a, b engine.Weights, deals int, seed int64) *tournament.Tournament {
+	ca, cb := t.Engine, t.Engine
+	ca.Weights, cb.Weights = a, b
+	return &tournament.Tournament{
+		Entrants: []tournament.Entrant{
+			tournament.EngineEntrant("kandidaat", ca),
+			tournament.EngineEntrant("basis", cb),
+		},
+		Players:  t.Engine.NumPlayers,
+		Deals:    deals,
+		Seed:     seed,
+		MaxMoves: t.MaxMoves,
+	}
+}
+
+// play speelt de toernooien en vergelijkt in elk de eerste deelnemer deal
+// per deal met de tweede.
+func (t *Tuner) play(gen int, ts []*tournament.Tournament) ([]tournament.HeadToHead, error) {
+	var progress func(done, total int)
+	if t.Progress != nil {
+		progress = func(done, total int) { t.Progress(gen, done, total) }
+	}
+	res, err := tournament.RunAll(ts, t.Jobs, progress)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]tournament.HeadToHead, len(res))
+	for i, r := range res {
+		out[i] = r.HeadToHead(0, 1)
+	}
+	return out, nil
+}
@@ -0,0 +1,156 @@
+package tuner
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/azen-engine/engine"
+)
+
+// TestParamsCoverWeights controleert dat elk veld van engine.Weights
+// precies één keer in Params staat, met grenzen rond de standaardwaarde.
+func TestParamsCoverWeights(t *testing.T) {
+	typ := reflect.TypeOf(engine.Weights{})
+	if len(Params) != typ.NumField() {
+		t.Fatalf("%d params voor %d velden", len(Params), typ.NumField())
+	}
+	def := engine.DefaultWeights()
+	for i, p := range Params {
+		if tag, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ","); tag != p.Name {
+			t.Errorf("param %d heet %s, veld %s", i, p.Name, tag)
+		}
+		if v := reflect.ValueOf(def).Field(i).Float(); v < p.Min || v > p.Max {
+			t.Errorf("%s: standaard %.3f buiten [%.3f, %.3f]", p.Name, v, p.Min, p.Max)
+		}
+	}
+	got := FromVector(Vector(def))
+	gv, dv := reflect.ValueOf(got), reflect.ValueOf(def)
+	for i := range Params {
+		if math.Abs(gv.Field(i).Float()-dv.Field(i).Float()) > 1e-12 {
+			t.Errorf("%s: %v na omzetting, want %v", Params[i].Name, gv.Field(i), dv.Field(i))
+		}
+	}
+}
+
+// TestPerturbChangesEveryWeight controleert dat geen enkel gewicht vergeten
+// wordt bij het muteren.
+func TestPerturbChangesEveryWeight(t *testing.T) {
+	base := engine.DefaultWeights()
+	w := Perturb(base, rand.New(rand.NewSource(1)), 0.2)
+	bv, wv := reflect.ValueOf(base), reflect.ValueOf(w)
+	for i := 0; i < bv.NumField(); i++ {
+		if bv.Field(i).Float() == wv.Field(i).Float() {
+			t.Errorf("%s niet gemuteerd", bv.Type().Field(i).Name)
+		}
+	}
+}
+
+func TestSymEigen(t *testing.T) {
+	a := [][]float64{{4, 1, 0.5}, {1, 3, 0.2}, {0.5, 0.2, 1}}
+	b, d := symEigen(a)
+	for i := range a {
+		for j := range a {
+			var v float64
+			for k := range d {
+				v += b[i][k] * d[k] * d[k] * b[j][k]
+			}
+			if math.Abs(v-a[i][j]) > 1e-9 {
+				t.Errorf("B·D²·Bᵀ[%d][%d] = %v, want %v", i, j, v, a[i][j])
+			}
+		}
+	}
+}
+
+// TestOptimizers laat elke optimizer een ruisvrije functie met maximum in
+// target zoeken. Zoals in de tuner is de score relatief tegenover de beste
+// tot nu toe (de basislijn). Halverwege moet een via JSON herstelde
+// optimizer exact hetzelfde verder doen.
+func TestOptimizers(t *testing.T) {
+	n := len(Params)
+	target := make([]float64, n)
+	for i := range target {
+		target[i] = 0.2 + 0.6*float64(i)/float64(n)
+	}
+	score := func(x []float64) float64 {
+		var s float64
+		for i := range x {
+			s -= (x[i] - target[i]) * (x[i] - target[i])
+		}
+		return s
+	}
+	x0 := make([]float64, n)
+	for i := range x0 {
+		x0[i] = 0.5
+	}
+	for _, name := range Optimizers {
+		opt, err := NewOptimizer(name, x0, 0, 60)
+		if err != nil {
+			t.Fatal(err)
+		}
+		best := score(x0)
+		for gen := 0; gen < 60; gen++ {
+			if gen == 30 {
+				state, err := json.Marshal(opt)
+				if err != nil {
+					t.Fatal(err)
+				}
+				restored, err := restoreOptimizer(name, state)
+				if err != nil {
+					t.Fatal(err)
+				}
+				a := opt.Ask(rand.New(rand.NewSource(9)))
+				b := restored.Ask(rand.New(rand.NewSource(9)))
+				if !reflect.DeepEqual(a, b) {
+					t.Errorf("%s: hersteld uit JSON vraagt andere kandidaten", name)
+				}
+				opt = restored
+			}
+			xs := opt.Ask(rand.New(rand.NewSource(int64(gen))))
+			scores := make([]float64, len(xs))
+			for i, x := range xs {
+				scores[i] = score(x) - best
+			}
+			opt.Tell(xs, scores)
+			best += math.Max(scores[argmax(scores)], 0)
+		}
+		if best < score(x0)/4 {
+			t.Errorf("%s: beste score %.4f, start %.4f", name, best, score(x0))
+		}
+	}
+}
+
+func TestCheckpointRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "w.tune.json")
+	state, _ := json.Marshal(NewCMAES(Vector(engine.DefaultWeights()), 0))
+	cp := &Checkpoint{
+		Version:    checkpointVersion,
+		Seed:       42,
+		Baseline:   BaselineStart.String(),
+		Generation: 3,
+		Optimizer:  "cmaes",
+		State:      state,
+		Start:      engine.DefaultWeights(),
+		Best:       Perturb(engine.DefaultWeights(), rand.New(rand.NewSource(2)), 0.1),
+		Score:      0.12,
+		Err:        0.05,
+	}
+	if err := cp.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := restoreOptimizer(got.Optimizer, got.State); err != nil {
+		t.Fatal(err)
+	}
+	got.State, cp.State = nil, nil
+	if !reflect.DeepEqual(got, cp) {
+		t.Errorf("LoadCheckpoint = %+v, want %+v", got, cp)
+	}
+}